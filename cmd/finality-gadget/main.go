@@ -0,0 +1,122 @@
+// finality-gadget serves the finality view of the Babylon finality gadget over HTTP/JSON and gRPC, so that several L2
+// services share one SDK client instead of each polling Babylon and Bitcoin
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/babylonchain/babylon-finality-gadget/sdk/client"
+	sdkconfig "github.com/babylonchain/babylon-finality-gadget/sdk/config"
+	"github.com/babylonchain/babylon-finality-gadget/sdk/l2client"
+	"github.com/babylonchain/babylon-finality-gadget/server"
+)
+
+func main() {
+	serverCfg := server.DefaultConfig()
+	l2Cfg := l2client.DefaultConfig()
+	configPath := flag.String("config", "", "The SDK config file, in TOML or YAML. The BFG_ environment variables override it")
+	sampleConfigPath := flag.String("write-sample-config", "", "Write a sample SDK config to the file and exit")
+	listenAddr := flag.String("listen-addr", ":8080", "The address the HTTP API listens on")
+	grpcListenAddr := flag.String("grpc-listen-addr", ":9090", "The address the gRPC API listens on. Set to empty to disable")
+	flag.DurationVar(&serverCfg.RequestTimeout, "request-timeout", serverCfg.RequestTimeout,
+		"The timeout of the queries of a request to Babylon and Bitcoin. Set to 0 to disable")
+	flag.DurationVar(&serverCfg.ShutdownTimeout, "shutdown-timeout", serverCfg.ShutdownTimeout,
+		"The time the in-flight requests are waited for on shutdown")
+	flag.StringVar(&l2Cfg.RPCAddr, "l2-rpc-addr", "",
+		"The JSON-RPC address of the L2 execution client, e.g. http://localhost:8545. If set, the last finalized L2 block "+
+			"is tracked and streamed by the gRPC API")
+	flag.DurationVar(&l2Cfg.PollInterval, "l2-poll-interval", l2Cfg.PollInterval, "The interval the L2 head is polled at")
+	flag.Parse()
+
+	if *sampleConfigPath != "" {
+		if err := sdkconfig.WriteSampleConfig(*sampleConfigPath); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := run(ctx, *configPath, *listenAddr, *grpcListenAddr, serverCfg, l2Cfg); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(
+	ctx context.Context,
+	configPath string,
+	listenAddr string,
+	grpcListenAddr string,
+	serverCfg *server.Config,
+	l2Cfg *l2client.Config,
+) error {
+	logger, err := zap.NewProduction()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = logger.Sync() }()
+
+	config, err := sdkconfig.LoadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	sdkClient, err := client.NewClient(config)
+	if err != nil {
+		return fmt.Errorf("failed to create the SDK client: %w", err)
+	}
+	defer func() {
+		closeCtx, cancel := context.WithTimeout(context.Background(), serverCfg.ShutdownTimeout)
+		defer cancel()
+		if err := sdkClient.Close(closeCtx); err != nil {
+			logger.Warn("Failed to close the SDK client", zap.Error(err))
+		}
+	}()
+
+	// the tracker follows the L2 chain from its head until the daemon stops
+	if l2Cfg.RPCAddr != "" {
+		l2Client, err := l2client.NewClient(l2Cfg, logger)
+		if err != nil {
+			return err
+		}
+		follower := l2client.NewFollower(l2Client, l2Cfg, logger)
+		go follower.Run(ctx)
+		if err := sdkClient.StartTracker(ctx, follower, client.DefaultTrackerConfig()); err != nil {
+			return err
+		}
+	}
+
+	listener, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", listenAddr, err)
+	}
+	var grpcListener net.Listener
+	if grpcListenAddr != "" {
+		if grpcListener, err = net.Listen("tcp", grpcListenAddr); err != nil {
+			_ = listener.Close()
+			return fmt.Errorf("failed to listen on %s: %w", grpcListenAddr, err)
+		}
+	}
+
+	// both APIs stop once either fails
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		return server.New(sdkClient, sdkClient.CheckReadiness, serverCfg, logger).Serve(gctx, listener)
+	})
+	if grpcListener != nil {
+		g.Go(func() error {
+			return server.NewGRPCServer(sdkClient, sdkClient.Subscribe, serverCfg, logger).Serve(gctx, grpcListener)
+		})
+	}
+	return g.Wait()
+}
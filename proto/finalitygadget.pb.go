@@ -0,0 +1,1103 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.1
+// 	protoc        (unknown)
+// source: finalitygadget.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Block is an L2 block
+type Block struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BlockHeight    uint64 `protobuf:"varint,1,opt,name=block_height,json=blockHeight,proto3" json:"block_height,omitempty"`
+	BlockHash      string `protobuf:"bytes,2,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+	BlockTimestamp uint64 `protobuf:"varint,3,opt,name=block_timestamp,json=blockTimestamp,proto3" json:"block_timestamp,omitempty"`
+}
+
+func (x *Block) Reset() {
+	*x = Block{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_finalitygadget_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Block) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Block) ProtoMessage() {}
+
+func (x *Block) ProtoReflect() protoreflect.Message {
+	mi := &file_finalitygadget_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Block.ProtoReflect.Descriptor instead.
+func (*Block) Descriptor() ([]byte, []int) {
+	return file_finalitygadget_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Block) GetBlockHeight() uint64 {
+	if x != nil {
+		return x.BlockHeight
+	}
+	return 0
+}
+
+func (x *Block) GetBlockHash() string {
+	if x != nil {
+		return x.BlockHash
+	}
+	return ""
+}
+
+func (x *Block) GetBlockTimestamp() uint64 {
+	if x != nil {
+		return x.BlockTimestamp
+	}
+	return 0
+}
+
+type QueryIsBlockBabylonFinalizedRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Block *Block `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
+}
+
+func (x *QueryIsBlockBabylonFinalizedRequest) Reset() {
+	*x = QueryIsBlockBabylonFinalizedRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_finalitygadget_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QueryIsBlockBabylonFinalizedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryIsBlockBabylonFinalizedRequest) ProtoMessage() {}
+
+func (x *QueryIsBlockBabylonFinalizedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_finalitygadget_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryIsBlockBabylonFinalizedRequest.ProtoReflect.Descriptor instead.
+func (*QueryIsBlockBabylonFinalizedRequest) Descriptor() ([]byte, []int) {
+	return file_finalitygadget_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *QueryIsBlockBabylonFinalizedRequest) GetBlock() *Block {
+	if x != nil {
+		return x.Block
+	}
+	return nil
+}
+
+type QueryIsBlockBabylonFinalizedResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	IsFinalized bool `protobuf:"varint,1,opt,name=is_finalized,json=isFinalized,proto3" json:"is_finalized,omitempty"`
+}
+
+func (x *QueryIsBlockBabylonFinalizedResponse) Reset() {
+	*x = QueryIsBlockBabylonFinalizedResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_finalitygadget_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QueryIsBlockBabylonFinalizedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryIsBlockBabylonFinalizedResponse) ProtoMessage() {}
+
+func (x *QueryIsBlockBabylonFinalizedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_finalitygadget_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryIsBlockBabylonFinalizedResponse.ProtoReflect.Descriptor instead.
+func (*QueryIsBlockBabylonFinalizedResponse) Descriptor() ([]byte, []int) {
+	return file_finalitygadget_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *QueryIsBlockBabylonFinalizedResponse) GetIsFinalized() bool {
+	if x != nil {
+		return x.IsFinalized
+	}
+	return false
+}
+
+type QueryBlockRangeBabylonFinalizedRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// blocks are consecutive, from low to high
+	Blocks []*Block `protobuf:"bytes,1,rep,name=blocks,proto3" json:"blocks,omitempty"`
+}
+
+func (x *QueryBlockRangeBabylonFinalizedRequest) Reset() {
+	*x = QueryBlockRangeBabylonFinalizedRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_finalitygadget_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QueryBlockRangeBabylonFinalizedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryBlockRangeBabylonFinalizedRequest) ProtoMessage() {}
+
+func (x *QueryBlockRangeBabylonFinalizedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_finalitygadget_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryBlockRangeBabylonFinalizedRequest.ProtoReflect.Descriptor instead.
+func (*QueryBlockRangeBabylonFinalizedRequest) Descriptor() ([]byte, []int) {
+	return file_finalitygadget_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *QueryBlockRangeBabylonFinalizedRequest) GetBlocks() []*Block {
+	if x != nil {
+		return x.Blocks
+	}
+	return nil
+}
+
+type QueryBlockRangeBabylonFinalizedResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// last_finalized_block_height is not set if the first block is not finalized
+	LastFinalizedBlockHeight *uint64 `protobuf:"varint,1,opt,name=last_finalized_block_height,json=lastFinalizedBlockHeight,proto3,oneof" json:"last_finalized_block_height,omitempty"`
+}
+
+func (x *QueryBlockRangeBabylonFinalizedResponse) Reset() {
+	*x = QueryBlockRangeBabylonFinalizedResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_finalitygadget_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QueryBlockRangeBabylonFinalizedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryBlockRangeBabylonFinalizedResponse) ProtoMessage() {}
+
+func (x *QueryBlockRangeBabylonFinalizedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_finalitygadget_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryBlockRangeBabylonFinalizedResponse.ProtoReflect.Descriptor instead.
+func (*QueryBlockRangeBabylonFinalizedResponse) Descriptor() ([]byte, []int) {
+	return file_finalitygadget_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *QueryBlockRangeBabylonFinalizedResponse) GetLastFinalizedBlockHeight() uint64 {
+	if x != nil && x.LastFinalizedBlockHeight != nil {
+		return *x.LastFinalizedBlockHeight
+	}
+	return 0
+}
+
+type QueryBtcStakingActivatedTimestampRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *QueryBtcStakingActivatedTimestampRequest) Reset() {
+	*x = QueryBtcStakingActivatedTimestampRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_finalitygadget_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QueryBtcStakingActivatedTimestampRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryBtcStakingActivatedTimestampRequest) ProtoMessage() {}
+
+func (x *QueryBtcStakingActivatedTimestampRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_finalitygadget_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryBtcStakingActivatedTimestampRequest.ProtoReflect.Descriptor instead.
+func (*QueryBtcStakingActivatedTimestampRequest) Descriptor() ([]byte, []int) {
+	return file_finalitygadget_proto_rawDescGZIP(), []int{5}
+}
+
+type QueryBtcStakingActivatedTimestampResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ActivatedTimestamp uint64 `protobuf:"varint,1,opt,name=activated_timestamp,json=activatedTimestamp,proto3" json:"activated_timestamp,omitempty"`
+}
+
+func (x *QueryBtcStakingActivatedTimestampResponse) Reset() {
+	*x = QueryBtcStakingActivatedTimestampResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_finalitygadget_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QueryBtcStakingActivatedTimestampResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryBtcStakingActivatedTimestampResponse) ProtoMessage() {}
+
+func (x *QueryBtcStakingActivatedTimestampResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_finalitygadget_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryBtcStakingActivatedTimestampResponse.ProtoReflect.Descriptor instead.
+func (*QueryBtcStakingActivatedTimestampResponse) Descriptor() ([]byte, []int) {
+	return file_finalitygadget_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *QueryBtcStakingActivatedTimestampResponse) GetActivatedTimestamp() uint64 {
+	if x != nil {
+		return x.ActivatedTimestamp
+	}
+	return 0
+}
+
+type QueryBlockFinalityResultRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Block *Block `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
+}
+
+func (x *QueryBlockFinalityResultRequest) Reset() {
+	*x = QueryBlockFinalityResultRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_finalitygadget_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QueryBlockFinalityResultRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryBlockFinalityResultRequest) ProtoMessage() {}
+
+func (x *QueryBlockFinalityResultRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_finalitygadget_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryBlockFinalityResultRequest.ProtoReflect.Descriptor instead.
+func (*QueryBlockFinalityResultRequest) Descriptor() ([]byte, []int) {
+	return file_finalitygadget_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *QueryBlockFinalityResultRequest) GetBlock() *Block {
+	if x != nil {
+		return x.Block
+	}
+	return nil
+}
+
+// FpVote is the voting power and vote status of a finality provider for an L2 block
+type FpVote struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FpBtcPkHex string `protobuf:"bytes,1,opt,name=fp_btc_pk_hex,json=fpBtcPkHex,proto3" json:"fp_btc_pk_hex,omitempty"`
+	Power      uint64 `protobuf:"varint,2,opt,name=power,proto3" json:"power,omitempty"`
+	Voted      bool   `protobuf:"varint,3,opt,name=voted,proto3" json:"voted,omitempty"`
+}
+
+func (x *FpVote) Reset() {
+	*x = FpVote{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_finalitygadget_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FpVote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FpVote) ProtoMessage() {}
+
+func (x *FpVote) ProtoReflect() protoreflect.Message {
+	mi := &file_finalitygadget_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FpVote.ProtoReflect.Descriptor instead.
+func (*FpVote) Descriptor() ([]byte, []int) {
+	return file_finalitygadget_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *FpVote) GetFpBtcPkHex() string {
+	if x != nil {
+		return x.FpBtcPkHex
+	}
+	return ""
+}
+
+func (x *FpVote) GetPower() uint64 {
+	if x != nil {
+		return x.Power
+	}
+	return 0
+}
+
+func (x *FpVote) GetVoted() bool {
+	if x != nil {
+		return x.Voted
+	}
+	return false
+}
+
+// QueryBlockFinalityResultResponse is the detailed finality verdict of an L2 block, see finality.Result
+type QueryBlockFinalityResultResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Enabled   bool   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	Finalized bool   `protobuf:"varint,2,opt,name=finalized,proto3" json:"finalized,omitempty"`
+	BtcHeight uint64 `protobuf:"varint,3,opt,name=btc_height,json=btcHeight,proto3" json:"btc_height,omitempty"`
+	// the sums of voting power are decimal strings, as they can exceed uint64
+	TotalPower        string    `protobuf:"bytes,4,opt,name=total_power,json=totalPower,proto3" json:"total_power,omitempty"`
+	VotedPower        string    `protobuf:"bytes,5,opt,name=voted_power,json=votedPower,proto3" json:"voted_power,omitempty"`
+	FpVotes           []*FpVote `protobuf:"bytes,6,rep,name=fp_votes,json=fpVotes,proto3" json:"fp_votes,omitempty"`
+	ConflictingFps    []string  `protobuf:"bytes,7,rep,name=conflicting_fps,json=conflictingFps,proto3" json:"conflicting_fps,omitempty"`
+	QuorumNumerator   uint64    `protobuf:"varint,8,opt,name=quorum_numerator,json=quorumNumerator,proto3" json:"quorum_numerator,omitempty"`
+	QuorumDenominator uint64    `protobuf:"varint,9,opt,name=quorum_denominator,json=quorumDenominator,proto3" json:"quorum_denominator,omitempty"`
+}
+
+func (x *QueryBlockFinalityResultResponse) Reset() {
+	*x = QueryBlockFinalityResultResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_finalitygadget_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QueryBlockFinalityResultResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryBlockFinalityResultResponse) ProtoMessage() {}
+
+func (x *QueryBlockFinalityResultResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_finalitygadget_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryBlockFinalityResultResponse.ProtoReflect.Descriptor instead.
+func (*QueryBlockFinalityResultResponse) Descriptor() ([]byte, []int) {
+	return file_finalitygadget_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *QueryBlockFinalityResultResponse) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *QueryBlockFinalityResultResponse) GetFinalized() bool {
+	if x != nil {
+		return x.Finalized
+	}
+	return false
+}
+
+func (x *QueryBlockFinalityResultResponse) GetBtcHeight() uint64 {
+	if x != nil {
+		return x.BtcHeight
+	}
+	return 0
+}
+
+func (x *QueryBlockFinalityResultResponse) GetTotalPower() string {
+	if x != nil {
+		return x.TotalPower
+	}
+	return ""
+}
+
+func (x *QueryBlockFinalityResultResponse) GetVotedPower() string {
+	if x != nil {
+		return x.VotedPower
+	}
+	return ""
+}
+
+func (x *QueryBlockFinalityResultResponse) GetFpVotes() []*FpVote {
+	if x != nil {
+		return x.FpVotes
+	}
+	return nil
+}
+
+func (x *QueryBlockFinalityResultResponse) GetConflictingFps() []string {
+	if x != nil {
+		return x.ConflictingFps
+	}
+	return nil
+}
+
+func (x *QueryBlockFinalityResultResponse) GetQuorumNumerator() uint64 {
+	if x != nil {
+		return x.QuorumNumerator
+	}
+	return 0
+}
+
+func (x *QueryBlockFinalityResultResponse) GetQuorumDenominator() uint64 {
+	if x != nil {
+		return x.QuorumDenominator
+	}
+	return 0
+}
+
+// ErrorDetail is attached to the status of a failed query
+type ErrorDetail struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// code is one of the error codes of the finality gadget server, e.g. timestamp_ahead_of_btc_tip
+	Code string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	// last_finalized_block_height is set by QueryBlockRangeBabylonFinalized to the last finalized block found
+	// before the failure, if any
+	LastFinalizedBlockHeight *uint64 `protobuf:"varint,2,opt,name=last_finalized_block_height,json=lastFinalizedBlockHeight,proto3,oneof" json:"last_finalized_block_height,omitempty"`
+}
+
+func (x *ErrorDetail) Reset() {
+	*x = ErrorDetail{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_finalitygadget_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ErrorDetail) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ErrorDetail) ProtoMessage() {}
+
+func (x *ErrorDetail) ProtoReflect() protoreflect.Message {
+	mi := &file_finalitygadget_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ErrorDetail.ProtoReflect.Descriptor instead.
+func (*ErrorDetail) Descriptor() ([]byte, []int) {
+	return file_finalitygadget_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ErrorDetail) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *ErrorDetail) GetLastFinalizedBlockHeight() uint64 {
+	if x != nil && x.LastFinalizedBlockHeight != nil {
+		return *x.LastFinalizedBlockHeight
+	}
+	return 0
+}
+
+type SubscribeFinalizedBlocksRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *SubscribeFinalizedBlocksRequest) Reset() {
+	*x = SubscribeFinalizedBlocksRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_finalitygadget_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubscribeFinalizedBlocksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeFinalizedBlocksRequest) ProtoMessage() {}
+
+func (x *SubscribeFinalizedBlocksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_finalitygadget_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeFinalizedBlocksRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeFinalizedBlocksRequest) Descriptor() ([]byte, []int) {
+	return file_finalitygadget_proto_rawDescGZIP(), []int{11}
+}
+
+type SubscribeFinalizedBlocksResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// block is the new last finalized L2 block
+	Block *Block `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
+}
+
+func (x *SubscribeFinalizedBlocksResponse) Reset() {
+	*x = SubscribeFinalizedBlocksResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_finalitygadget_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubscribeFinalizedBlocksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeFinalizedBlocksResponse) ProtoMessage() {}
+
+func (x *SubscribeFinalizedBlocksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_finalitygadget_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeFinalizedBlocksResponse.ProtoReflect.Descriptor instead.
+func (*SubscribeFinalizedBlocksResponse) Descriptor() ([]byte, []int) {
+	return file_finalitygadget_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SubscribeFinalizedBlocksResponse) GetBlock() *Block {
+	if x != nil {
+		return x.Block
+	}
+	return nil
+}
+
+var File_finalitygadget_proto protoreflect.FileDescriptor
+
+var file_finalitygadget_proto_rawDesc = []byte{
+	0x0a, 0x14, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x67, 0x61, 0x64, 0x67, 0x65, 0x74,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x72, 0x0a,
+	0x05, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x21, 0x0a, 0x0c, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f,
+	0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x62, 0x6c,
+	0x6f, 0x63, 0x6b, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x6c, 0x6f,
+	0x63, 0x6b, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x62,
+	0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x61, 0x73, 0x68, 0x12, 0x27, 0x0a, 0x0f, 0x62, 0x6c, 0x6f, 0x63,
+	0x6b, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x0e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x22, 0x49, 0x0a, 0x23, 0x51, 0x75, 0x65, 0x72, 0x79, 0x49, 0x73, 0x42, 0x6c, 0x6f, 0x63,
+	0x6b, 0x42, 0x61, 0x62, 0x79, 0x6c, 0x6f, 0x6e, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65,
+	0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x22, 0x0a, 0x05, 0x62, 0x6c, 0x6f, 0x63,
+	0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x05, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x22, 0x49, 0x0a, 0x24,
+	0x51, 0x75, 0x65, 0x72, 0x79, 0x49, 0x73, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x42, 0x61, 0x62, 0x79,
+	0x6c, 0x6f, 0x6e, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x73, 0x5f, 0x66, 0x69, 0x6e, 0x61, 0x6c,
+	0x69, 0x7a, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x69, 0x73, 0x46, 0x69,
+	0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x22, 0x4e, 0x0a, 0x26, 0x51, 0x75, 0x65, 0x72, 0x79,
+	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x42, 0x61, 0x62, 0x79, 0x6c, 0x6f,
+	0x6e, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x24, 0x0a, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52,
+	0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x22, 0x8d, 0x01, 0x0a, 0x27, 0x51, 0x75, 0x65, 0x72,
+	0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x42, 0x61, 0x62, 0x79, 0x6c,
+	0x6f, 0x6e, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x42, 0x0a, 0x1b, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x66, 0x69, 0x6e, 0x61,
+	0x6c, 0x69, 0x7a, 0x65, 0x64, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x68, 0x65, 0x69, 0x67,
+	0x68, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x48, 0x00, 0x52, 0x18, 0x6c, 0x61, 0x73, 0x74,
+	0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x65,
+	0x69, 0x67, 0x68, 0x74, 0x88, 0x01, 0x01, 0x42, 0x1e, 0x0a, 0x1c, 0x5f, 0x6c, 0x61, 0x73, 0x74,
+	0x5f, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b,
+	0x5f, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x22, 0x2a, 0x0a, 0x28, 0x51, 0x75, 0x65, 0x72, 0x79,
+	0x42, 0x74, 0x63, 0x53, 0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x41, 0x63, 0x74, 0x69, 0x76, 0x61,
+	0x74, 0x65, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x22, 0x5c, 0x0a, 0x29, 0x51, 0x75, 0x65, 0x72, 0x79, 0x42, 0x74, 0x63, 0x53,
+	0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x41, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x65, 0x64, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x2f, 0x0a, 0x13, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x12, 0x61,
+	0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x65, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x22, 0x45, 0x0a, 0x1f, 0x51, 0x75, 0x65, 0x72, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x46,
+	0x69, 0x6e, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x22, 0x0a, 0x05, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x42, 0x6c, 0x6f, 0x63,
+	0x6b, 0x52, 0x05, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x22, 0x57, 0x0a, 0x06, 0x46, 0x70, 0x56, 0x6f,
+	0x74, 0x65, 0x12, 0x21, 0x0a, 0x0d, 0x66, 0x70, 0x5f, 0x62, 0x74, 0x63, 0x5f, 0x70, 0x6b, 0x5f,
+	0x68, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x66, 0x70, 0x42, 0x74, 0x63,
+	0x50, 0x6b, 0x48, 0x65, 0x78, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x6f, 0x74, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x76, 0x6f, 0x74, 0x65,
+	0x64, 0x22, 0xe8, 0x02, 0x0a, 0x20, 0x51, 0x75, 0x65, 0x72, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64,
+	0x12, 0x1c, 0x0a, 0x09, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x09, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x12, 0x1d,
+	0x0a, 0x0a, 0x62, 0x74, 0x63, 0x5f, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x09, 0x62, 0x74, 0x63, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x1f, 0x0a,
+	0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x12, 0x1f,
+	0x0a, 0x0b, 0x76, 0x6f, 0x74, 0x65, 0x64, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x76, 0x6f, 0x74, 0x65, 0x64, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x12,
+	0x28, 0x0a, 0x08, 0x66, 0x70, 0x5f, 0x76, 0x6f, 0x74, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x46, 0x70, 0x56, 0x6f, 0x74, 0x65,
+	0x52, 0x07, 0x66, 0x70, 0x56, 0x6f, 0x74, 0x65, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x6f, 0x6e,
+	0x66, 0x6c, 0x69, 0x63, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x66, 0x70, 0x73, 0x18, 0x07, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x0e, 0x63, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x69, 0x6e, 0x67, 0x46,
+	0x70, 0x73, 0x12, 0x29, 0x0a, 0x10, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x5f, 0x6e, 0x75, 0x6d,
+	0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x71, 0x75,
+	0x6f, 0x72, 0x75, 0x6d, 0x4e, 0x75, 0x6d, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x2d, 0x0a,
+	0x12, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x5f, 0x64, 0x65, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61,
+	0x74, 0x6f, 0x72, 0x18, 0x09, 0x20, 0x01, 0x28, 0x04, 0x52, 0x11, 0x71, 0x75, 0x6f, 0x72, 0x75,
+	0x6d, 0x44, 0x65, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x22, 0x85, 0x01, 0x0a,
+	0x0b, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x12, 0x12, 0x0a, 0x04,
+	0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65,
+	0x12, 0x42, 0x0a, 0x1b, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a,
+	0x65, 0x64, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x04, 0x48, 0x00, 0x52, 0x18, 0x6c, 0x61, 0x73, 0x74, 0x46, 0x69, 0x6e,
+	0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x65, 0x69, 0x67, 0x68,
+	0x74, 0x88, 0x01, 0x01, 0x42, 0x1e, 0x0a, 0x1c, 0x5f, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x66, 0x69,
+	0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x68, 0x65,
+	0x69, 0x67, 0x68, 0x74, 0x22, 0x21, 0x0a, 0x1f, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62,
+	0x65, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x46, 0x0a, 0x20, 0x53, 0x75, 0x62, 0x73, 0x63,
+	0x72, 0x69, 0x62, 0x65, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x42, 0x6c, 0x6f,
+	0x63, 0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x22, 0x0a, 0x05, 0x62,
+	0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x05, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x32,
+	0xf9, 0x04, 0x0a, 0x0e, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x47, 0x61, 0x64, 0x67,
+	0x65, 0x74, 0x12, 0x79, 0x0a, 0x1c, 0x51, 0x75, 0x65, 0x72, 0x79, 0x49, 0x73, 0x42, 0x6c, 0x6f,
+	0x63, 0x6b, 0x42, 0x61, 0x62, 0x79, 0x6c, 0x6f, 0x6e, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a,
+	0x65, 0x64, 0x12, 0x2a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79,
+	0x49, 0x73, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x42, 0x61, 0x62, 0x79, 0x6c, 0x6f, 0x6e, 0x46, 0x69,
+	0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2b,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x49, 0x73, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x42, 0x61, 0x62, 0x79, 0x6c, 0x6f, 0x6e, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69,
+	0x7a, 0x65, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x00, 0x12, 0x82, 0x01,
+	0x0a, 0x1f, 0x51, 0x75, 0x65, 0x72, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61, 0x6e, 0x67,
+	0x65, 0x42, 0x61, 0x62, 0x79, 0x6c, 0x6f, 0x6e, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65,
+	0x64, 0x12, 0x2d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x42,
+	0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x42, 0x61, 0x62, 0x79, 0x6c, 0x6f, 0x6e,
+	0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x2e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x42, 0x61, 0x62, 0x79, 0x6c, 0x6f, 0x6e, 0x46,
+	0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x30, 0x00, 0x12, 0x88, 0x01, 0x0a, 0x21, 0x51, 0x75, 0x65, 0x72, 0x79, 0x42, 0x74, 0x63, 0x53,
+	0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x41, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x65, 0x64, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x2f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x42, 0x74, 0x63, 0x53, 0x74, 0x61, 0x6b, 0x69, 0x6e, 0x67,
+	0x41, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x65, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x30, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x42, 0x74, 0x63, 0x53, 0x74, 0x61, 0x6b, 0x69, 0x6e,
+	0x67, 0x41, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x65, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x00, 0x12, 0x6d, 0x0a,
+	0x18, 0x51, 0x75, 0x65, 0x72, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x46, 0x69, 0x6e, 0x61, 0x6c,
+	0x69, 0x74, 0x79, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x26, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x46, 0x69, 0x6e, 0x61,
+	0x6c, 0x69, 0x74, 0x79, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x27, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x42,
+	0x6c, 0x6f, 0x63, 0x6b, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x00, 0x12, 0x6d, 0x0a, 0x18,
+	0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a,
+	0x65, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x26, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69,
+	0x7a, 0x65, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x27, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
+	0x62, 0x65, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x42, 0x37, 0x5a, 0x35, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x61, 0x62, 0x79, 0x6c, 0x6f,
+	0x6e, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x2f, 0x62, 0x61, 0x62, 0x79, 0x6c, 0x6f, 0x6e, 0x2d, 0x66,
+	0x69, 0x6e, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x2d, 0x67, 0x61, 0x64, 0x67, 0x65, 0x74, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_finalitygadget_proto_rawDescOnce sync.Once
+	file_finalitygadget_proto_rawDescData = file_finalitygadget_proto_rawDesc
+)
+
+func file_finalitygadget_proto_rawDescGZIP() []byte {
+	file_finalitygadget_proto_rawDescOnce.Do(func() {
+		file_finalitygadget_proto_rawDescData = protoimpl.X.CompressGZIP(file_finalitygadget_proto_rawDescData)
+	})
+	return file_finalitygadget_proto_rawDescData
+}
+
+var file_finalitygadget_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_finalitygadget_proto_goTypes = []interface{}{
+	(*Block)(nil), // 0: proto.Block
+	(*QueryIsBlockBabylonFinalizedRequest)(nil),       // 1: proto.QueryIsBlockBabylonFinalizedRequest
+	(*QueryIsBlockBabylonFinalizedResponse)(nil),      // 2: proto.QueryIsBlockBabylonFinalizedResponse
+	(*QueryBlockRangeBabylonFinalizedRequest)(nil),    // 3: proto.QueryBlockRangeBabylonFinalizedRequest
+	(*QueryBlockRangeBabylonFinalizedResponse)(nil),   // 4: proto.QueryBlockRangeBabylonFinalizedResponse
+	(*QueryBtcStakingActivatedTimestampRequest)(nil),  // 5: proto.QueryBtcStakingActivatedTimestampRequest
+	(*QueryBtcStakingActivatedTimestampResponse)(nil), // 6: proto.QueryBtcStakingActivatedTimestampResponse
+	(*QueryBlockFinalityResultRequest)(nil),           // 7: proto.QueryBlockFinalityResultRequest
+	(*FpVote)(nil),                                    // 8: proto.FpVote
+	(*QueryBlockFinalityResultResponse)(nil),          // 9: proto.QueryBlockFinalityResultResponse
+	(*ErrorDetail)(nil),                               // 10: proto.ErrorDetail
+	(*SubscribeFinalizedBlocksRequest)(nil),           // 11: proto.SubscribeFinalizedBlocksRequest
+	(*SubscribeFinalizedBlocksResponse)(nil),          // 12: proto.SubscribeFinalizedBlocksResponse
+}
+var file_finalitygadget_proto_depIdxs = []int32{
+	0,  // 0: proto.QueryIsBlockBabylonFinalizedRequest.block:type_name -> proto.Block
+	0,  // 1: proto.QueryBlockRangeBabylonFinalizedRequest.blocks:type_name -> proto.Block
+	0,  // 2: proto.QueryBlockFinalityResultRequest.block:type_name -> proto.Block
+	8,  // 3: proto.QueryBlockFinalityResultResponse.fp_votes:type_name -> proto.FpVote
+	0,  // 4: proto.SubscribeFinalizedBlocksResponse.block:type_name -> proto.Block
+	1,  // 5: proto.FinalityGadget.QueryIsBlockBabylonFinalized:input_type -> proto.QueryIsBlockBabylonFinalizedRequest
+	3,  // 6: proto.FinalityGadget.QueryBlockRangeBabylonFinalized:input_type -> proto.QueryBlockRangeBabylonFinalizedRequest
+	5,  // 7: proto.FinalityGadget.QueryBtcStakingActivatedTimestamp:input_type -> proto.QueryBtcStakingActivatedTimestampRequest
+	7,  // 8: proto.FinalityGadget.QueryBlockFinalityResult:input_type -> proto.QueryBlockFinalityResultRequest
+	11, // 9: proto.FinalityGadget.SubscribeFinalizedBlocks:input_type -> proto.SubscribeFinalizedBlocksRequest
+	2,  // 10: proto.FinalityGadget.QueryIsBlockBabylonFinalized:output_type -> proto.QueryIsBlockBabylonFinalizedResponse
+	4,  // 11: proto.FinalityGadget.QueryBlockRangeBabylonFinalized:output_type -> proto.QueryBlockRangeBabylonFinalizedResponse
+	6,  // 12: proto.FinalityGadget.QueryBtcStakingActivatedTimestamp:output_type -> proto.QueryBtcStakingActivatedTimestampResponse
+	9,  // 13: proto.FinalityGadget.QueryBlockFinalityResult:output_type -> proto.QueryBlockFinalityResultResponse
+	12, // 14: proto.FinalityGadget.SubscribeFinalizedBlocks:output_type -> proto.SubscribeFinalizedBlocksResponse
+	10, // [10:15] is the sub-list for method output_type
+	5,  // [5:10] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_finalitygadget_proto_init() }
+func file_finalitygadget_proto_init() {
+	if File_finalitygadget_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_finalitygadget_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Block); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_finalitygadget_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryIsBlockBabylonFinalizedRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_finalitygadget_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryIsBlockBabylonFinalizedResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_finalitygadget_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryBlockRangeBabylonFinalizedRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_finalitygadget_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryBlockRangeBabylonFinalizedResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_finalitygadget_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryBtcStakingActivatedTimestampRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_finalitygadget_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryBtcStakingActivatedTimestampResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_finalitygadget_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryBlockFinalityResultRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_finalitygadget_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FpVote); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_finalitygadget_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryBlockFinalityResultResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_finalitygadget_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ErrorDetail); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_finalitygadget_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubscribeFinalizedBlocksRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_finalitygadget_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubscribeFinalizedBlocksResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_finalitygadget_proto_msgTypes[4].OneofWrappers = []interface{}{}
+	file_finalitygadget_proto_msgTypes[10].OneofWrappers = []interface{}{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_finalitygadget_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_finalitygadget_proto_goTypes,
+		DependencyIndexes: file_finalitygadget_proto_depIdxs,
+		MessageInfos:      file_finalitygadget_proto_msgTypes,
+	}.Build()
+	File_finalitygadget_proto = out.File
+	file_finalitygadget_proto_rawDesc = nil
+	file_finalitygadget_proto_goTypes = nil
+	file_finalitygadget_proto_depIdxs = nil
+}
@@ -1,89 +1,180 @@
 package bbnclient
 
 import (
+	"context"
 	"math"
+	"math/bits"
+	"sync"
 
-	"github.com/babylonchain/babylon/client/query"
 	"github.com/babylonchain/babylon/x/btcstaking/types"
-	sdkquerytypes "github.com/cosmos/cosmos-sdk/types/query"
+	bsctypes "github.com/babylonchain/babylon/x/btcstkconsumer/types"
+
+	"github.com/babylonchain/babylon-finality-gadget/sdk/internal/concurrency"
 )
 
 type Client struct {
-	*query.QueryClient
+	rpcClient      RPCClient
+	queryClient    babylonQueryClient
+	powerCache     *powerCache
+	pageSize       uint64
+	maxConcurrency int
+
+	newBlocksMu sync.Mutex
+	// newBlocks is the subscription to the new Babylon blocks, if any, see SubscribeNewBlocks
+	newBlocks *newBlockSubscription
 }
 
-func (bbnClient *Client) QueryAllFpBtcPubKeys(consumerId string) ([]string, error) {
-	pagination := &sdkquerytypes.PageRequest{}
-	resp, err := bbnClient.QueryClient.QueryConsumerFinalityProviders(consumerId, pagination)
+func NewClient(rpcClient RPCClient, cfg *BBNConfig) (*Client, error) {
+	powerCache, err := newPowerCache(cfg.PowerCacheSize, cfg.PowerCacheDepth, cfg.PowerCacheTTL)
 	if err != nil {
 		return nil, err
 	}
 
-	var pkArr []string
+	maxConcurrency := cfg.MaxConcurrency
+	if maxConcurrency < 1 {
+		maxConcurrency = 1
+	}
 
-	for _, fp := range resp.FinalityProviders {
+	return &Client{
+		rpcClient:      rpcClient,
+		queryClient:    &rpcQueryClient{RPCClient: rpcClient},
+		powerCache:     powerCache,
+		pageSize:       cfg.PageSize,
+		maxConcurrency: maxConcurrency,
+	}, nil
+}
+
+func (bbnClient *Client) QueryAllFpBtcPubKeys(ctx context.Context, consumerId string) ([]string, error) {
+	var pkArr []string
+	err := bbnClient.forEachConsumerFp(ctx, consumerId, func(fp *bsctypes.FinalityProviderResponse) error {
 		pkArr = append(pkArr, fp.BtcPk.MarshalHex())
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	return pkArr, nil
 }
 
-func (bbnClient *Client) QueryFpPower(fpPubkeyHex string, btcHeight uint64) (uint64, error) {
-	totalPower := uint64(0)
-	pagination := &sdkquerytypes.PageRequest{}
-	// queries the BTCStaking module for all delegations of a finality provider
-	resp, err := bbnClient.QueryClient.FinalityProviderDelegations(fpPubkeyHex, pagination)
+func (bbnClient *Client) QueryFpPower(ctx context.Context, fpPubkeyHex string, btcHeight uint64) (uint64, error) {
+	// queries BtcConfirmationDepth, CheckpointFinalizationTimeout and CovenantQuorum
+	params, err := bbnClient.queryStakingParams(ctx)
 	if err != nil {
 		return 0, err
 	}
-	for {
-		// btcDels contains all the queried BTC delegations
-		for _, btcDels := range resp.BtcDelegatorDelegations {
-			for _, btcDel := range btcDels.Dels {
-				// check whether the delegation is active
-				isActive, err := bbnClient.isDelegationActive(btcDel, btcHeight)
-				if err != nil {
-					return 0, err
-				}
-				if isActive {
-					totalPower += btcDel.TotalSat
-				}
-			}
-		}
-		if resp.Pagination == nil || resp.Pagination.NextKey == nil {
-			break
+	return bbnClient.queryFpPower(ctx, fpPubkeyHex, btcHeight, params)
+}
+
+// queryFpPower sums the staked amount of all active delegations of an FP at the BTC height,
+// checking the delegations against the given params snapshot
+func (bbnClient *Client) queryFpPower(
+	ctx context.Context,
+	fpPubkeyHex string,
+	btcHeight uint64,
+	params *stakingParams,
+) (uint64, error) {
+	totalPower := uint64(0)
+	err := bbnClient.forEachFpDelegation(ctx, fpPubkeyHex, func(btcDel *types.BTCDelegationResponse) error {
+		// check whether the delegation is active
+		if !isDelegationActive(btcDel, btcHeight, params) {
+			return nil
 		}
-		pagination.Key = resp.Pagination.NextKey
+		var err error
+		totalPower, err = addPower(totalPower, btcDel.TotalSat)
+		return err
+	})
+	if err != nil {
+		return 0, err
 	}
 
 	return totalPower, nil
 }
 
+// QueryMultiFpPower returns the voting power of the given FPs of the consumer chain at the BTC height
+//
+// the voting power table is cached per (consumer ID, BTC height), so only FPs missing from the cached table
+// are queried from Babylon. The staking params are queried at most once and shared by all FPs, and the
+// missing FPs are queried concurrently, with at most BBNConfig.MaxConcurrency queries in flight
 func (bbnClient *Client) QueryMultiFpPower(
+	ctx context.Context,
+	consumerId string,
 	fpPubkeyHexList []string,
 	btcHeight uint64,
 ) (map[string]uint64, error) {
+	cachedFpPowers, _ := bbnClient.powerCache.get(consumerId, btcHeight)
 	fpPowerMap := make(map[string]uint64)
 
+	var missedFpPks []string
 	for _, fpPubkeyHex := range fpPubkeyHexList {
-		fpPower, err := bbnClient.QueryFpPower(fpPubkeyHex, btcHeight)
+		if fpPower, ok := cachedFpPowers[fpPubkeyHex]; ok {
+			fpPowerMap[fpPubkeyHex] = fpPower
+			continue
+		}
+		missedFpPks = append(missedFpPks, fpPubkeyHex)
+	}
+	if len(missedFpPks) == 0 {
+		return fpPowerMap, nil
+	}
+
+	params, err := bbnClient.queryStakingParams(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// query the missed FPs concurrently, each query writes to its own slot
+	missedPowers := make([]uint64, len(missedFpPks))
+	err = concurrency.ForEach(ctx, bbnClient.maxConcurrency, missedFpPks, func(ctx context.Context, i int, fpPubkeyHex string) error {
+		fpPower, err := bbnClient.queryFpPower(ctx, fpPubkeyHex, btcHeight, params)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		fpPowerMap[fpPubkeyHex] = fpPower
+		missedPowers[i] = fpPower
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	missedFpPowers := make(map[string]uint64, len(missedFpPks))
+	for i, fpPubkeyHex := range missedFpPks {
+		fpPowerMap[fpPubkeyHex] = missedPowers[i]
+		missedFpPowers[fpPubkeyHex] = missedPowers[i]
 	}
+	bbnClient.powerCache.add(consumerId, btcHeight, missedFpPowers)
 
 	return fpPowerMap, nil
 }
 
 // QueryEarliestActiveDelBtcHeight returns the earliest active BTC staking height
-func (bbnClient *Client) QueryEarliestActiveDelBtcHeight(fpPkHexList []string) (uint64, error) {
+//
+// the FPs are queried concurrently, with at most BBNConfig.MaxConcurrency queries in flight
+func (bbnClient *Client) QueryEarliestActiveDelBtcHeight(ctx context.Context, fpPkHexList []string) (uint64, error) {
 	allFpEarliestDelBtcHeight := uint64(math.MaxUint64)
+	if len(fpPkHexList) == 0 {
+		return allFpEarliestDelBtcHeight, nil
+	}
+
+	// the params and the latest BTC header are shared by all FPs
+	params, latestBtcHeight, err := bbnClient.queryActivationParams(ctx)
+	if err != nil {
+		return math.MaxUint64, err
+	}
 
-	for _, fpPkHex := range fpPkHexList {
-		fpEarliestDelBtcHeight, err := bbnClient.QueryFpEarliestActiveDelBtcHeight(fpPkHex)
+	// query the FPs concurrently, each query writes to its own slot
+	fpEarliestDelBtcHeights := make([]uint64, len(fpPkHexList))
+	err = concurrency.ForEach(ctx, bbnClient.maxConcurrency, fpPkHexList, func(ctx context.Context, i int, fpPkHex string) error {
+		fpEarliestDelBtcHeight, err := bbnClient.queryFpEarliestActiveDelBtcHeight(ctx, fpPkHex, latestBtcHeight, params)
 		if err != nil {
-			return math.MaxUint64, err
+			return err
 		}
+		fpEarliestDelBtcHeights[i] = fpEarliestDelBtcHeight
+		return nil
+	})
+	if err != nil {
+		return math.MaxUint64, err
+	}
+
+	for _, fpEarliestDelBtcHeight := range fpEarliestDelBtcHeights {
 		if fpEarliestDelBtcHeight < allFpEarliestDelBtcHeight {
 			allFpEarliestDelBtcHeight = fpEarliestDelBtcHeight
 		}
@@ -92,54 +183,45 @@ func (bbnClient *Client) QueryEarliestActiveDelBtcHeight(fpPkHexList []string) (
 	return allFpEarliestDelBtcHeight, nil
 }
 
-func (bbnClient *Client) QueryFpEarliestActiveDelBtcHeight(fpPubkeyHex string) (uint64, error) {
-	pagination := &sdkquerytypes.PageRequest{
-		Limit: 100,
-	}
-
-	// queries the BTCStaking module for all delegations of a finality provider
-	resp, err := bbnClient.QueryClient.FinalityProviderDelegations(fpPubkeyHex, pagination)
-	if err != nil {
-		return math.MaxUint64, err
-	}
-
-	// queries BtcConfirmationDepth, CovenantQuorum, and the latest BTC header
-	btccheckpointParams, err := bbnClient.QueryClient.BTCCheckpointParams()
+func (bbnClient *Client) QueryFpEarliestActiveDelBtcHeight(ctx context.Context, fpPubkeyHex string) (uint64, error) {
+	params, latestBtcHeight, err := bbnClient.queryActivationParams(ctx)
 	if err != nil {
 		return math.MaxUint64, err
 	}
+	return bbnClient.queryFpEarliestActiveDelBtcHeight(ctx, fpPubkeyHex, latestBtcHeight, params)
+}
 
-	// get the BTC staking params
-	btcstakingParams, err := bbnClient.QueryClient.BTCStakingParams()
+// queryActivationParams queries BtcConfirmationDepth, CovenantQuorum, and the latest BTC height
+func (bbnClient *Client) queryActivationParams(ctx context.Context) (*stakingParams, uint64, error) {
+	params, err := bbnClient.queryStakingParams(ctx)
 	if err != nil {
-		return math.MaxUint64, err
+		return nil, 0, err
 	}
 
 	// get the latest BTC header
-	btcHeader, err := bbnClient.QueryClient.BTCHeaderChainTip()
+	btcHeader, err := bbnClient.queryClient.BTCHeaderChainTip(ctx)
 	if err != nil {
-		return math.MaxUint64, err
+		return nil, 0, err
 	}
+	return params, btcHeader.GetHeader().Height, nil
+}
 
-	kValue := btccheckpointParams.GetParams().BtcConfirmationDepth
-	covQuorum := btcstakingParams.GetParams().CovenantQuorum
-	latestBtcHeight := btcHeader.GetHeader().Height
-
+func (bbnClient *Client) queryFpEarliestActiveDelBtcHeight(
+	ctx context.Context,
+	fpPubkeyHex string,
+	latestBtcHeight uint64,
+	params *stakingParams,
+) (uint64, error) {
 	earliestBtcHeight := uint64(math.MaxUint64)
-	for {
-		// btcDels contains all the queried BTC delegations
-		for _, btcDels := range resp.BtcDelegatorDelegations {
-			for _, btcDel := range btcDels.Dels {
-				activationHeight := getDelFirstActiveHeight(btcDel, latestBtcHeight, kValue, covQuorum)
-				if activationHeight < earliestBtcHeight {
-					earliestBtcHeight = activationHeight
-				}
-			}
-		}
-		if resp.Pagination == nil || resp.Pagination.NextKey == nil {
-			break
+	err := bbnClient.forEachFpDelegation(ctx, fpPubkeyHex, func(btcDel *types.BTCDelegationResponse) error {
+		activationHeight := getDelFirstActiveHeight(btcDel, latestBtcHeight, params.kValue, params.covQuorum)
+		if activationHeight < earliestBtcHeight {
+			earliestBtcHeight = activationHeight
 		}
-		pagination.Key = resp.Pagination.NextKey
+		return nil
+	})
+	if err != nil {
+		return math.MaxUint64, err
 	}
 	return earliestBtcHeight, nil
 }
@@ -159,3 +241,13 @@ func getDelFirstActiveHeight(btcDel *types.BTCDelegationResponse, latestBtcHeigh
 	}
 	return activationHeight
 }
+
+// addPower adds the staked amount of a delegation to the voting power of an FP,
+// returning ErrPowerOverflow instead of silently wrapping around
+func addPower(power, totalSat uint64) (uint64, error) {
+	sum, carry := bits.Add64(power, totalSat, 0)
+	if carry != 0 {
+		return 0, ErrPowerOverflow
+	}
+	return sum, nil
+}
@@ -0,0 +1,592 @@
+package bbnclient
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	bbn "github.com/babylonchain/babylon/types"
+	btcctypes "github.com/babylonchain/babylon/x/btccheckpoint/types"
+	btclctypes "github.com/babylonchain/babylon/x/btclightclient/types"
+	btcstakingtypes "github.com/babylonchain/babylon/x/btcstaking/types"
+	bsctypes "github.com/babylonchain/babylon/x/btcstkconsumer/types"
+	sdkquerytypes "github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testKValue    = 6
+	testWValue    = 20
+	testCovQuorum = 1
+	testTipHeight = 1000
+)
+
+// stubQueryClient serves fixed FPs and delegations per FP and counts the queries it receives
+//
+// if maxPageSize is set, results are split into pages of at most maxPageSize items, and the next key
+// is the offset of the next page
+type stubQueryClient struct {
+	fps         []*bsctypes.FinalityProviderResponse
+	dels        map[string][]*btcstakingtypes.BTCDelegationResponse
+	maxPageSize uint64
+	// delegationErrs fails the delegation queries of the given FPs
+	delegationErrs map[string]error
+	// delegationLatency delays the delegation queries, unless the context is done
+	delegationLatency time.Duration
+
+	inFlight          atomic.Int64
+	maxInFlight       atomic.Int64
+	cancelledQueries  atomic.Int64
+	delegationQueries atomic.Int64
+	paramsQueries     atomic.Int64
+	tipQueries        atomic.Int64
+}
+
+func (c *stubQueryClient) ConsumerFinalityProviders(
+	_ context.Context,
+	_ string,
+	pagination *sdkquerytypes.PageRequest,
+) (*bsctypes.QueryFinalityProvidersResponse, error) {
+	start, end, pageResp, err := c.page(pagination, len(c.fps))
+	if err != nil {
+		return nil, err
+	}
+	return &bsctypes.QueryFinalityProvidersResponse{
+		FinalityProviders: c.fps[start:end],
+		Pagination:        pageResp,
+	}, nil
+}
+
+func (c *stubQueryClient) FinalityProviderDelegations(
+	ctx context.Context,
+	fpPubkeyHex string,
+	pagination *sdkquerytypes.PageRequest,
+) (*btcstakingtypes.QueryFinalityProviderDelegationsResponse, error) {
+	c.delegationQueries.Add(1)
+
+	inFlight := c.inFlight.Add(1)
+	defer c.inFlight.Add(-1)
+	for {
+		maxInFlight := c.maxInFlight.Load()
+		if inFlight <= maxInFlight || c.maxInFlight.CompareAndSwap(maxInFlight, inFlight) {
+			break
+		}
+	}
+
+	if err := c.delegationErrs[fpPubkeyHex]; err != nil {
+		return nil, err
+	}
+	if c.delegationLatency > 0 {
+		select {
+		case <-ctx.Done():
+			c.cancelledQueries.Add(1)
+			return nil, ctx.Err()
+		case <-time.After(c.delegationLatency):
+		}
+	}
+
+	dels := c.dels[fpPubkeyHex]
+	start, end, pageResp, err := c.page(pagination, len(dels))
+	if err != nil {
+		return nil, err
+	}
+	return &btcstakingtypes.QueryFinalityProviderDelegationsResponse{
+		BtcDelegatorDelegations: []*btcstakingtypes.BTCDelegatorDelegationsResponse{
+			{Dels: dels[start:end]},
+		},
+		Pagination: pageResp,
+	}, nil
+}
+
+// page returns the bounds of the requested page of a result of the given length
+func (c *stubQueryClient) page(
+	pagination *sdkquerytypes.PageRequest,
+	length int,
+) (int, int, *sdkquerytypes.PageResponse, error) {
+	start := 0
+	if len(pagination.GetKey()) > 0 {
+		offset, err := strconv.Atoi(string(pagination.GetKey()))
+		if err != nil {
+			return 0, 0, nil, err
+		}
+		start = offset
+	}
+	pageSize := uint64(length)
+	if c.maxPageSize > 0 && c.maxPageSize < pageSize {
+		pageSize = c.maxPageSize
+	}
+	if limit := pagination.GetLimit(); limit > 0 && limit < pageSize {
+		pageSize = limit
+	}
+	end := start + int(pageSize)
+	if end >= length {
+		return start, length, &sdkquerytypes.PageResponse{Total: uint64(length)}, nil
+	}
+	return start, end, &sdkquerytypes.PageResponse{NextKey: []byte(strconv.Itoa(end))}, nil
+}
+
+func (c *stubQueryClient) BTCCheckpointParams(_ context.Context) (*btcctypes.QueryParamsResponse, error) {
+	c.paramsQueries.Add(1)
+	return &btcctypes.QueryParamsResponse{
+		Params: btcctypes.Params{
+			BtcConfirmationDepth:          testKValue,
+			CheckpointFinalizationTimeout: testWValue,
+		},
+	}, nil
+}
+
+func (c *stubQueryClient) BTCStakingParams(_ context.Context) (*btcstakingtypes.QueryParamsResponse, error) {
+	c.paramsQueries.Add(1)
+	return &btcstakingtypes.QueryParamsResponse{
+		Params: btcstakingtypes.Params{CovenantQuorum: testCovQuorum},
+	}, nil
+}
+
+func (c *stubQueryClient) BTCHeaderChainTip(_ context.Context) (*btclctypes.QueryTipResponse, error) {
+	c.tipQueries.Add(1)
+	return &btclctypes.QueryTipResponse{
+		Header: &btclctypes.BTCHeaderInfoResponse{Height: testTipHeight},
+	}, nil
+}
+
+func (c *stubQueryClient) BTCMainChain(
+	_ context.Context,
+	_ *sdkquerytypes.PageRequest,
+) (*btclctypes.QueryMainChainResponse, error) {
+	return nil, fmt.Errorf("the BTC main chain is not served by stubQueryClient")
+}
+
+func (c *stubQueryClient) totalQueries() int64 {
+	return c.delegationQueries.Load() + c.paramsQueries.Load() + c.tipQueries.Load()
+}
+
+// newTestDelegation returns a delegation with a quorum of covenant signatures that is active in
+// the BTC heights [startHeight+k, endHeight-w]
+func newTestDelegation(startHeight, endHeight, totalSat uint64) *btcstakingtypes.BTCDelegationResponse {
+	return &btcstakingtypes.BTCDelegationResponse{
+		StartHeight:  startHeight,
+		EndHeight:    endHeight,
+		TotalSat:     totalSat,
+		CovenantSigs: []*btcstakingtypes.CovenantAdaptorSignatures{{}},
+		UndelegationResponse: &btcstakingtypes.BTCUndelegationResponse{
+			CovenantUnbondingSigList: []*btcstakingtypes.SignatureInfo{{}},
+			CovenantSlashingSigs:     []*btcstakingtypes.CovenantAdaptorSignatures{{}},
+		},
+	}
+}
+
+func newTestFp(pkByte byte) *bsctypes.FinalityProviderResponse {
+	pk := make(bbn.BIP340PubKey, 32)
+	pk[31] = pkByte
+	return &bsctypes.FinalityProviderResponse{BtcPk: &pk}
+}
+
+// newTestClient returns a client without a power cache, so that each query reaches the query client
+func newTestClient(t testing.TB, queryClient babylonQueryClient) *Client {
+	powerCache, err := newPowerCache(0, 0, 0)
+	require.NoError(t, err)
+	return &Client{
+		queryClient:    queryClient,
+		powerCache:     powerCache,
+		pageSize:       defaultPageSize,
+		maxConcurrency: defaultMaxConcurrency,
+	}
+}
+
+func TestQueryFpPowerFetchesParamsOnce(t *testing.T) {
+	queryClient := &stubQueryClient{
+		dels: map[string][]*btcstakingtypes.BTCDelegationResponse{
+			"fp1": {
+				newTestDelegation(100, 200, 10), // active
+				newTestDelegation(100, 200, 20), // active
+				newTestDelegation(170, 300, 40), // not k-deep
+				newTestDelegation(10, 160, 80),  // less than w blocks left
+			},
+		},
+	}
+	bbnClient := newTestClient(t, queryClient)
+
+	power, err := bbnClient.QueryFpPower(context.Background(), "fp1", 170)
+	require.NoError(t, err)
+	require.Equal(t, uint64(30), power)
+	require.Equal(t, int64(1), queryClient.delegationQueries.Load())
+	require.Equal(t, int64(2), queryClient.paramsQueries.Load())
+}
+
+func TestQueryMultiFpPowerFetchesParamsOnce(t *testing.T) {
+	queryClient := &stubQueryClient{
+		dels: map[string][]*btcstakingtypes.BTCDelegationResponse{
+			"fp1": {newTestDelegation(100, 200, 10)},
+			"fp2": {newTestDelegation(100, 200, 20)},
+			"fp3": {newTestDelegation(170, 300, 40)},
+		},
+	}
+	bbnClient := newTestClient(t, queryClient)
+
+	powers, err := bbnClient.QueryMultiFpPower(context.Background(), "consumer", []string{"fp1", "fp2", "fp3"}, 170)
+	require.NoError(t, err)
+	require.Equal(t, map[string]uint64{"fp1": 10, "fp2": 20, "fp3": 0}, powers)
+	require.Equal(t, int64(3), queryClient.delegationQueries.Load())
+	require.Equal(t, int64(2), queryClient.paramsQueries.Load())
+}
+
+func TestQueryMultiFpPowerCaches(t *testing.T) {
+	queryClient := &stubQueryClient{
+		dels: map[string][]*btcstakingtypes.BTCDelegationResponse{
+			"fp1": {newTestDelegation(100, 200, 10)},
+			"fp2": {newTestDelegation(100, 200, 20)},
+			"fp3": {newTestDelegation(170, 300, 40)},
+		},
+	}
+	bbnClient := newTestClient(t, queryClient)
+	powerCache, err := newPowerCache(10, 100, time.Minute)
+	require.NoError(t, err)
+	now := time.Unix(1_700_000_000, 0)
+	powerCache.now = func() time.Time { return now }
+	bbnClient.powerCache = powerCache
+
+	powers, err := bbnClient.QueryMultiFpPower(context.Background(), "consumer", []string{"fp1", "fp2"}, 170)
+	require.NoError(t, err)
+	require.Equal(t, map[string]uint64{"fp1": 10, "fp2": 20}, powers)
+	require.Equal(t, int64(2), queryClient.delegationQueries.Load())
+
+	// the second lookup at the same height is served from the cache
+	powers, err = bbnClient.QueryMultiFpPower(context.Background(), "consumer", []string{"fp1", "fp2"}, 170)
+	require.NoError(t, err)
+	require.Equal(t, map[string]uint64{"fp1": 10, "fp2": 20}, powers)
+	require.Equal(t, int64(2), queryClient.delegationQueries.Load())
+	require.Equal(t, int64(2), queryClient.paramsQueries.Load())
+
+	// only the FPs missing from the cached table are queried
+	powers, err = bbnClient.QueryMultiFpPower(context.Background(), "consumer", []string{"fp1", "fp2", "fp3"}, 170)
+	require.NoError(t, err)
+	require.Equal(t, map[string]uint64{"fp1": 10, "fp2": 20, "fp3": 0}, powers)
+	require.Equal(t, int64(3), queryClient.delegationQueries.Load())
+
+	// the other heights are not served from the cache
+	_, err = bbnClient.QueryMultiFpPower(context.Background(), "consumer", []string{"fp1"}, 171)
+	require.NoError(t, err)
+	require.Equal(t, int64(4), queryClient.delegationQueries.Load())
+
+	// the delegations changed since the table was cached, and it expired
+	queryClient.dels["fp1"] = append(queryClient.dels["fp1"], newTestDelegation(100, 200, 5))
+	now = now.Add(time.Minute)
+	powers, err = bbnClient.QueryMultiFpPower(context.Background(), "consumer", []string{"fp1", "fp2"}, 170)
+	require.NoError(t, err)
+	require.Equal(t, map[string]uint64{"fp1": 15, "fp2": 20}, powers)
+	require.Equal(t, int64(6), queryClient.delegationQueries.Load())
+}
+
+func TestQueryEarliestActiveDelBtcHeightFetchesParamsOnce(t *testing.T) {
+	queryClient := &stubQueryClient{
+		dels: map[string][]*btcstakingtypes.BTCDelegationResponse{
+			"fp1": {newTestDelegation(300, 500, 10)},
+			"fp2": {newTestDelegation(100, 500, 20), newTestDelegation(200, 500, 20)},
+			"fp3": {newTestDelegation(testTipHeight, 2000, 40)}, // not k-deep yet
+		},
+	}
+	bbnClient := newTestClient(t, queryClient)
+
+	height, err := bbnClient.QueryEarliestActiveDelBtcHeight(context.Background(), []string{"fp1", "fp2", "fp3"})
+	require.NoError(t, err)
+	require.Equal(t, uint64(100+testKValue), height)
+	require.Equal(t, int64(3), queryClient.delegationQueries.Load())
+	require.Equal(t, int64(2), queryClient.paramsQueries.Load())
+	require.Equal(t, int64(1), queryClient.tipQueries.Load())
+}
+
+func TestQueryAllFpBtcPubKeysPaginates(t *testing.T) {
+	fps := make([]*bsctypes.FinalityProviderResponse, 7)
+	for i := range fps {
+		fps[i] = newTestFp(byte(i))
+	}
+
+	for _, pageSize := range []uint64{0, 1, 3, 7, 100} {
+		t.Run(fmt.Sprintf("page size %d", pageSize), func(t *testing.T) {
+			bbnClient := newTestClient(t, &stubQueryClient{fps: fps})
+			bbnClient.pageSize = pageSize
+
+			pks, err := bbnClient.QueryAllFpBtcPubKeys(context.Background(), "consumer")
+			require.NoError(t, err)
+			require.Len(t, pks, len(fps))
+			for i, fp := range fps {
+				require.Equal(t, fp.BtcPk.MarshalHex(), pks[i])
+			}
+		})
+	}
+}
+
+func TestQueryFpPowerPaginates(t *testing.T) {
+	dels := make([]*btcstakingtypes.BTCDelegationResponse, 25)
+	for i := range dels {
+		dels[i] = newTestDelegation(100, 200, uint64(i+1))
+	}
+	// an inactive delegation on the last page
+	dels = append(dels, newTestDelegation(170, 300, 1000))
+
+	for _, pageSize := range []uint64{1, 4, 10, 26, 100} {
+		t.Run(fmt.Sprintf("page size %d", pageSize), func(t *testing.T) {
+			queryClient := &stubQueryClient{
+				dels:        map[string][]*btcstakingtypes.BTCDelegationResponse{"fp1": dels},
+				maxPageSize: pageSize,
+			}
+			bbnClient := newTestClient(t, queryClient)
+
+			power, err := bbnClient.QueryFpPower(context.Background(), "fp1", 170)
+			require.NoError(t, err)
+			require.Equal(t, uint64(25*26/2), power)
+
+			expectedPages := (uint64(len(dels)) + pageSize - 1) / pageSize
+			require.Equal(t, int64(expectedPages), queryClient.delegationQueries.Load())
+		})
+	}
+}
+
+func TestQueryFpEarliestActiveDelBtcHeightPaginates(t *testing.T) {
+	dels := []*btcstakingtypes.BTCDelegationResponse{
+		newTestDelegation(500, 900, 1),
+		newTestDelegation(400, 900, 1),
+		newTestDelegation(300, 900, 1),
+		// the earliest delegation is on the last page
+		newTestDelegation(200, 900, 1),
+	}
+	queryClient := &stubQueryClient{
+		dels:        map[string][]*btcstakingtypes.BTCDelegationResponse{"fp1": dels},
+		maxPageSize: 1,
+	}
+	bbnClient := newTestClient(t, queryClient)
+
+	height, err := bbnClient.QueryFpEarliestActiveDelBtcHeight(context.Background(), "fp1")
+	require.NoError(t, err)
+	require.Equal(t, uint64(200+testKValue), height)
+	require.Equal(t, int64(len(dels)), queryClient.delegationQueries.Load())
+}
+
+func TestPaginate(t *testing.T) {
+	t.Run("follows next keys", func(t *testing.T) {
+		var keys []string
+		err := paginate(context.Background(), 10, func(
+			_ context.Context,
+			pagination *sdkquerytypes.PageRequest,
+		) (*sdkquerytypes.PageResponse, error) {
+			require.Equal(t, uint64(10), pagination.Limit)
+			keys = append(keys, string(pagination.Key))
+			if len(keys) == 3 {
+				return &sdkquerytypes.PageResponse{}, nil
+			}
+			return &sdkquerytypes.PageResponse{NextKey: []byte(strconv.Itoa(len(keys)))}, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"", "1", "2"}, keys)
+	})
+
+	t.Run("nil pagination is the last page", func(t *testing.T) {
+		calls := 0
+		err := paginate(context.Background(), 10, func(
+			_ context.Context,
+			_ *sdkquerytypes.PageRequest,
+		) (*sdkquerytypes.PageResponse, error) {
+			calls++
+			return nil, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("repeated next key", func(t *testing.T) {
+		calls := 0
+		err := paginate(context.Background(), 10, func(
+			_ context.Context,
+			_ *sdkquerytypes.PageRequest,
+		) (*sdkquerytypes.PageResponse, error) {
+			calls++
+			return &sdkquerytypes.PageResponse{NextKey: []byte("same")}, nil
+		})
+		require.ErrorIs(t, err, ErrPaginationStuck)
+		require.Equal(t, 2, calls)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		queryErr := fmt.Errorf("query failed")
+		err := paginate(context.Background(), 10, func(
+			_ context.Context,
+			_ *sdkquerytypes.PageRequest,
+		) (*sdkquerytypes.PageResponse, error) {
+			return nil, queryErr
+		})
+		require.ErrorIs(t, err, queryErr)
+	})
+}
+
+func TestQueryMultiFpPowerConcurrently(t *testing.T) {
+	const numFps = 100
+	fpPks := make([]string, numFps)
+	dels := make(map[string][]*btcstakingtypes.BTCDelegationResponse, numFps)
+	expectedPowers := make(map[string]uint64, numFps)
+	for i := range fpPks {
+		fpPks[i] = fmt.Sprintf("fp%d", i)
+		dels[fpPks[i]] = []*btcstakingtypes.BTCDelegationResponse{newTestDelegation(100, 200, uint64(i))}
+		expectedPowers[fpPks[i]] = uint64(i)
+	}
+
+	for _, maxConcurrency := range []int{1, 4, 16} {
+		t.Run(fmt.Sprintf("max concurrency %d", maxConcurrency), func(t *testing.T) {
+			queryClient := &stubQueryClient{dels: dels, delegationLatency: time.Millisecond}
+			bbnClient := newTestClient(t, queryClient)
+			bbnClient.maxConcurrency = maxConcurrency
+
+			powers, err := bbnClient.QueryMultiFpPower(context.Background(), "consumer", fpPks, 170)
+			require.NoError(t, err)
+			require.Equal(t, expectedPowers, powers)
+			require.Equal(t, int64(numFps), queryClient.delegationQueries.Load())
+			require.LessOrEqual(t, queryClient.maxInFlight.Load(), int64(maxConcurrency))
+			require.Equal(t, int64(2), queryClient.paramsQueries.Load())
+		})
+	}
+}
+
+func TestQueryMultiFpPowerFailsFast(t *testing.T) {
+	const numFps = 100
+	fpPks := make([]string, numFps)
+	for i := range fpPks {
+		fpPks[i] = fmt.Sprintf("fp%d", i)
+	}
+	queryErr := fmt.Errorf("query failed")
+	queryClient := &stubQueryClient{
+		delegationErrs:    map[string]error{"fp0": queryErr},
+		delegationLatency: time.Minute,
+	}
+	bbnClient := newTestClient(t, queryClient)
+	bbnClient.maxConcurrency = 4
+	powerCache, err := newPowerCache(10, 100, 0)
+	require.NoError(t, err)
+	bbnClient.powerCache = powerCache
+
+	powers, err := bbnClient.QueryMultiFpPower(context.Background(), "consumer", fpPks, 170)
+	require.ErrorIs(t, err, queryErr)
+	require.Nil(t, powers)
+	// the queries in flight when fp0 failed are cancelled, and no more queries are scheduled
+	require.LessOrEqual(t, queryClient.delegationQueries.Load(), int64(bbnClient.maxConcurrency))
+	require.Equal(t, queryClient.delegationQueries.Load()-1, queryClient.cancelledQueries.Load())
+
+	// a failed query does not populate the cache
+	_, ok := bbnClient.powerCache.get("consumer", 170)
+	require.False(t, ok)
+}
+
+func TestQueryEarliestActiveDelBtcHeightConcurrently(t *testing.T) {
+	const numFps = 50
+	fpPks := make([]string, numFps)
+	dels := make(map[string][]*btcstakingtypes.BTCDelegationResponse, numFps)
+	for i := range fpPks {
+		fpPks[i] = fmt.Sprintf("fp%d", i)
+		dels[fpPks[i]] = []*btcstakingtypes.BTCDelegationResponse{newTestDelegation(uint64(500-i), 900, 1)}
+	}
+	queryClient := &stubQueryClient{dels: dels, delegationLatency: time.Millisecond}
+	bbnClient := newTestClient(t, queryClient)
+	bbnClient.maxConcurrency = 8
+
+	height, err := bbnClient.QueryEarliestActiveDelBtcHeight(context.Background(), fpPks)
+	require.NoError(t, err)
+	require.Equal(t, uint64(500-(numFps-1)+testKValue), height)
+	require.LessOrEqual(t, queryClient.maxInFlight.Load(), int64(bbnClient.maxConcurrency))
+}
+
+func TestQueryEarliestActiveDelBtcHeightCancelled(t *testing.T) {
+	queryClient := &stubQueryClient{delegationLatency: time.Minute}
+	bbnClient := newTestClient(t, queryClient)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	height, err := bbnClient.QueryEarliestActiveDelBtcHeight(ctx, []string{"fp1", "fp2", "fp3"})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, uint64(math.MaxUint64), height)
+}
+
+// queryFpPowerPerDelegationParams is the baseline of QueryFpPower, which queries the staking params for
+// each delegation of the FP instead of once per power computation
+func queryFpPowerPerDelegationParams(
+	ctx context.Context,
+	bbnClient *Client,
+	fpPubkeyHex string,
+	btcHeight uint64,
+) (uint64, error) {
+	totalPower := uint64(0)
+	err := bbnClient.forEachFpDelegation(ctx, fpPubkeyHex, func(btcDel *btcstakingtypes.BTCDelegationResponse) error {
+		params, err := bbnClient.queryStakingParams(ctx)
+		if err != nil {
+			return err
+		}
+		if !isDelegationActive(btcDel, btcHeight, params) {
+			return nil
+		}
+		totalPower, err = addPower(totalPower, btcDel.TotalSat)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return totalPower, nil
+}
+
+func newTestClientWithDelegations(t testing.TB, numDels int) (*Client, *stubQueryClient) {
+	dels := make([]*btcstakingtypes.BTCDelegationResponse, numDels)
+	for i := range dels {
+		dels[i] = newTestDelegation(100, 200, 1)
+	}
+	queryClient := &stubQueryClient{
+		dels: map[string][]*btcstakingtypes.BTCDelegationResponse{"fp1": dels},
+	}
+	return newTestClient(t, queryClient), queryClient
+}
+
+func TestQueryFpPowerParamsQueriesPerDelegation(t *testing.T) {
+	const numDels = 100
+
+	// the baseline queries the checkpoint and staking params for each delegation
+	bbnClient, queryClient := newTestClientWithDelegations(t, numDels)
+	baselinePower, err := queryFpPowerPerDelegationParams(context.Background(), bbnClient, "fp1", 150)
+	require.NoError(t, err)
+	require.Equal(t, int64(2*numDels), queryClient.paramsQueries.Load())
+
+	// QueryFpPower queries them once, whatever the number of delegations
+	bbnClient, queryClient = newTestClientWithDelegations(t, numDels)
+	power, err := bbnClient.QueryFpPower(context.Background(), "fp1", 150)
+	require.NoError(t, err)
+	require.Equal(t, baselinePower, power)
+	require.Equal(t, int64(2), queryClient.paramsQueries.Load())
+}
+
+// BenchmarkQueryFpPower reports the number of Babylon queries issued to compute the voting power of
+// an FP with many delegations, against the baseline that queries the params for each delegation
+func BenchmarkQueryFpPower(b *testing.B) {
+	queryFpPower := func(ctx context.Context, bbnClient *Client, fpPubkeyHex string, btcHeight uint64) (uint64, error) {
+		return bbnClient.QueryFpPower(ctx, fpPubkeyHex, btcHeight)
+	}
+	for _, numDels := range []int{10, 1000, 10000} {
+		for _, bc := range []struct {
+			name         string
+			queryFpPower func(context.Context, *Client, string, uint64) (uint64, error)
+		}{
+			{"params=per-delegation", queryFpPowerPerDelegationParams},
+			{"params=snapshot", queryFpPower},
+		} {
+			b.Run(fmt.Sprintf("dels=%d/%s", numDels, bc.name), func(b *testing.B) {
+				bbnClient, queryClient := newTestClientWithDelegations(b, numDels)
+
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					if _, err := bc.queryFpPower(context.Background(), bbnClient, "fp1", 150); err != nil {
+						b.Fatal(err)
+					}
+				}
+				b.ReportMetric(float64(queryClient.totalQueries())/float64(b.N), "rpcs/op")
+			})
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package bbnclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	btclctypes "github.com/babylonchain/babylon/x/btclightclient/types"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	sdkquerytypes "github.com/cosmos/cosmos-sdk/types/query"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+const (
+	// lightClientHeaderCacheSize is the max number of BTC headers cached by hash
+	lightClientHeaderCacheSize = 4096
+	// maxMainChainPageSize bounds the number of BTC headers requested per page when walking the main chain
+	maxMainChainPageSize = 1000
+)
+
+// BTCLightClient serves BTC block headers from the BTC light client of Babylon, i.e. the btclightclient
+// module, so that no Bitcoin node is needed. It implements the header queries of the Bitcoin client, and
+// only knows the BTC main chain from the base header of the light client up to its tip
+//
+//   - headers are cached by hash, as the header of a hash never changes
+//   - the main chain headers seen since the light client tip last changed are cached by height, and
+//     evicted once the tip changes, as the light client may have been reorged
+type BTCLightClient struct {
+	queryClient babylonQueryClient
+	pageSize    uint64
+
+	mu sync.Mutex
+	// tipHash is the hash of the light client tip the main chain cache was filled at
+	tipHash   string
+	mainChain map[uint64]chainhash.Hash
+	headers   *lru.Cache
+}
+
+func NewBTCLightClient(rpcClient RPCClient, cfg *BBNConfig) (*BTCLightClient, error) {
+	return newBTCLightClient(&rpcQueryClient{RPCClient: rpcClient}, cfg.PageSize)
+}
+
+func newBTCLightClient(queryClient babylonQueryClient, pageSize uint64) (*BTCLightClient, error) {
+	headers, err := lru.New(lightClientHeaderCacheSize)
+	if err != nil {
+		return nil, err
+	}
+	return &BTCLightClient{
+		queryClient: queryClient,
+		pageSize:    pageSize,
+		mainChain:   make(map[uint64]chainhash.Hash),
+		headers:     headers,
+	}, nil
+}
+
+// GetBlockCount returns the height of the light client tip
+func (c *BTCLightClient) GetBlockCount(ctx context.Context) (uint64, error) {
+	tip, err := c.queryTip(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return tip.Height, nil
+}
+
+// GetBlockHashByHeight returns the hash of the main chain header at the height
+//
+// unless it is cached, the main chain is walked back page by page from the tip to the height
+func (c *BTCLightClient) GetBlockHashByHeight(ctx context.Context, height uint64) (*chainhash.Hash, error) {
+	tip, err := c.queryTip(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if height > tip.Height {
+		return nil, fmt.Errorf("BTC block height %d is above the BTC light client tip %d", height, tip.Height)
+	}
+	if blockHash, ok := c.cachedHashByHeight(height); ok {
+		return &blockHash, nil
+	}
+
+	var found *chainhash.Hash
+	var walkTipHash string
+	limit := c.pageSize
+	err = paginate(ctx, limit, func(
+		ctx context.Context,
+		pagination *sdkquerytypes.PageRequest,
+	) (*sdkquerytypes.PageResponse, error) {
+		pagination.Reverse = true
+		pagination.Limit = limit
+		resp, err := c.queryClient.BTCMainChain(ctx, pagination)
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Headers) == 0 {
+			return nil, nil
+		}
+		if pagination.Key == nil {
+			// the first page starts at the tip, which may have changed since it was queried
+			walkTipHash = resp.Headers[0].HashHex
+			c.resetMainChain(walkTipHash)
+		}
+		for _, info := range resp.Headers {
+			blockHash, err := c.addHeader(info, walkTipHash)
+			if err != nil {
+				return nil, err
+			}
+			if info.Height == height {
+				found = blockHash
+				return nil, nil
+			}
+			if info.Height < height {
+				return nil, nil
+			}
+		}
+		// the next page only needs to reach the height
+		limit = resp.Headers[len(resp.Headers)-1].Height - height
+		if limit > maxMainChainPageSize {
+			limit = maxMainChainPageSize
+		}
+		return resp.Pagination, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get BTC block by height %d: %w", height, err)
+	}
+	if found == nil {
+		return nil, fmt.Errorf("BTC block height %d is not in the main chain of the BTC light client", height)
+	}
+	return found, nil
+}
+
+// GetBlockHeaderByHash returns the main chain header of the hash
+func (c *BTCLightClient) GetBlockHeaderByHash(ctx context.Context, blockHash *chainhash.Hash) (*wire.BlockHeader, error) {
+	if header, ok := c.headers.Get(*blockHash); ok {
+		return header.(*wire.BlockHeader), nil
+	}
+
+	// the pagination key of the main chain is the hash of the first header of the page
+	resp, err := c.queryClient.BTCMainChain(ctx, &sdkquerytypes.PageRequest{
+		Key:   blockHash.CloneBytes(),
+		Limit: 1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get BTC block header by hash %s: %w", blockHash.String(), err)
+	}
+	if len(resp.Headers) == 0 || resp.Headers[0].HashHex != blockHash.String() {
+		return nil, fmt.Errorf("BTC block %s is not in the main chain of the BTC light client", blockHash.String())
+	}
+
+	header, err := parseBTCHeader(resp.Headers[0])
+	if err != nil {
+		return nil, err
+	}
+	c.headers.Add(*blockHash, header)
+	return header, nil
+}
+
+// queryTip queries the light client tip, and evicts the main chain cache if the tip changed
+func (c *BTCLightClient) queryTip(ctx context.Context) (*btclctypes.BTCHeaderInfoResponse, error) {
+	resp, err := c.queryClient.BTCHeaderChainTip(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tip := resp.GetHeader()
+	if tip == nil {
+		return nil, fmt.Errorf("the BTC light client has no tip")
+	}
+	c.resetMainChain(tip.HashHex)
+	return tip, nil
+}
+
+// resetMainChain evicts the main chain cache unless it was filled at the given tip
+func (c *BTCLightClient) resetMainChain(tipHash string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.tipHash == tipHash {
+		return
+	}
+	c.tipHash = tipHash
+	c.mainChain = make(map[uint64]chainhash.Hash)
+}
+
+func (c *BTCLightClient) cachedHashByHeight(height uint64) (chainhash.Hash, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	blockHash, ok := c.mainChain[height]
+	return blockHash, ok
+}
+
+// addHeader caches a main chain header seen while walking the main chain back from the given tip
+func (c *BTCLightClient) addHeader(info *btclctypes.BTCHeaderInfoResponse, tipHash string) (*chainhash.Hash, error) {
+	header, err := parseBTCHeader(info)
+	if err != nil {
+		return nil, err
+	}
+	blockHash := header.BlockHash()
+	c.headers.Add(blockHash, header)
+
+	// the main chain cache may have been evicted by a new tip in the meantime
+	c.mu.Lock()
+	if c.tipHash == tipHash {
+		c.mainChain[info.Height] = blockHash
+	}
+	c.mu.Unlock()
+	return &blockHash, nil
+}
+
+// parseBTCHeader decodes the header returned by the light client, and checks it against the returned hash
+func parseBTCHeader(info *btclctypes.BTCHeaderInfoResponse) (*wire.BlockHeader, error) {
+	headerBytes, err := hex.DecodeString(info.HeaderHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid BTC header at height %d: %w", info.Height, err)
+	}
+	header := &wire.BlockHeader{}
+	if err := header.Deserialize(bytes.NewReader(headerBytes)); err != nil {
+		return nil, fmt.Errorf("invalid BTC header at height %d: %w", info.Height, err)
+	}
+	if blockHash := header.BlockHash(); blockHash.String() != info.HashHex {
+		return nil, fmt.Errorf("BTC header at height %d has hash %s, expected %s",
+			info.Height, blockHash.String(), info.HashHex)
+	}
+	return header, nil
+}
@@ -0,0 +1,206 @@
+package bbnclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	btclctypes "github.com/babylonchain/babylon/x/btclightclient/types"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	sdkquerytypes "github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/stretchr/testify/require"
+)
+
+// stubLightClient serves the main chain of a BTC light client whose base header is at baseHeight, paginated
+// the same way as the btclightclient module: the pagination key is the hash of the first header of the page
+type stubLightClient struct {
+	*stubQueryClient
+	baseHeight uint64
+	headers    []*wire.BlockHeader // headers[i] is the header at baseHeight + i
+
+	mainChainQueries atomic.Int64
+}
+
+func newStubLightClient(baseHeight uint64, numHeaders int) *stubLightClient {
+	c := &stubLightClient{stubQueryClient: &stubQueryClient{}, baseHeight: baseHeight}
+	c.extend(numHeaders, 0)
+	return c
+}
+
+// extend appends numHeaders headers, the nonce distinguishes forks
+func (c *stubLightClient) extend(numHeaders int, nonce uint32) {
+	for i := 0; i < numHeaders; i++ {
+		header := &wire.BlockHeader{
+			Timestamp: time.Unix(1_700_000_000+int64(len(c.headers))*600, 0),
+			Nonce:     nonce,
+		}
+		if len(c.headers) > 0 {
+			header.PrevBlock = c.headers[len(c.headers)-1].BlockHash()
+		}
+		c.headers = append(c.headers, header)
+	}
+}
+
+func (c *stubLightClient) headerInfo(i int) *btclctypes.BTCHeaderInfoResponse {
+	var buf bytes.Buffer
+	if err := c.headers[i].Serialize(&buf); err != nil {
+		panic(err)
+	}
+	return &btclctypes.BTCHeaderInfoResponse{
+		HeaderHex: hex.EncodeToString(buf.Bytes()),
+		HashHex:   c.headers[i].BlockHash().String(),
+		Height:    c.baseHeight + uint64(i),
+	}
+}
+
+func (c *stubLightClient) hash(height uint64) chainhash.Hash {
+	return c.headers[height-c.baseHeight].BlockHash()
+}
+
+func (c *stubLightClient) BTCHeaderChainTip(_ context.Context) (*btclctypes.QueryTipResponse, error) {
+	c.tipQueries.Add(1)
+	return &btclctypes.QueryTipResponse{Header: c.headerInfo(len(c.headers) - 1)}, nil
+}
+
+func (c *stubLightClient) BTCMainChain(
+	_ context.Context,
+	pagination *sdkquerytypes.PageRequest,
+) (*btclctypes.QueryMainChainResponse, error) {
+	c.mainChainQueries.Add(1)
+
+	start := 0
+	if pagination.Reverse {
+		start = len(c.headers) - 1
+	}
+	if len(pagination.Key) > 0 {
+		keyHash, err := chainhash.NewHash(pagination.Key)
+		if err != nil {
+			return nil, err
+		}
+		start = -1
+		for i, header := range c.headers {
+			if header.BlockHash() == *keyHash {
+				start = i
+			}
+		}
+		if start < 0 {
+			return nil, fmt.Errorf("header specified by key does not exist")
+		}
+	}
+	limit := int(pagination.Limit)
+	if limit == 0 {
+		limit = 100
+	}
+
+	resp := &btclctypes.QueryMainChainResponse{Pagination: &sdkquerytypes.PageResponse{}}
+	step := 1
+	if pagination.Reverse {
+		step = -1
+	}
+	i := start
+	for ; i >= 0 && i < len(c.headers) && len(resp.Headers) < limit; i += step {
+		resp.Headers = append(resp.Headers, c.headerInfo(i))
+	}
+	if i >= 0 && i < len(c.headers) {
+		nextHash := c.headers[i].BlockHash()
+		resp.Pagination.NextKey = nextHash.CloneBytes()
+	}
+	return resp, nil
+}
+
+func TestBTCLightClientGetBlockHashByHeight(t *testing.T) {
+	stub := newStubLightClient(1000, 500)
+	lightClient, err := newBTCLightClient(stub, 100)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	tipHeight, err := lightClient.GetBlockCount(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1499), tipHeight)
+
+	for _, height := range []uint64{1499, 1450, 1300, 1000} {
+		blockHash, err := lightClient.GetBlockHashByHeight(ctx, height)
+		require.NoError(t, err)
+		require.Equal(t, stub.hash(height), *blockHash, "height %d", height)
+	}
+
+	// the heights below the tip walked so far are cached until the tip changes
+	mainChainQueries := stub.mainChainQueries.Load()
+	blockHash, err := lightClient.GetBlockHashByHeight(ctx, 1200)
+	require.NoError(t, err)
+	require.Equal(t, stub.hash(1200), *blockHash)
+	require.Equal(t, mainChainQueries, stub.mainChainQueries.Load())
+
+	// the heights outside of the light client's main chain
+	_, err = lightClient.GetBlockHashByHeight(ctx, 1500)
+	require.Error(t, err)
+	_, err = lightClient.GetBlockHashByHeight(ctx, 999)
+	require.Error(t, err)
+}
+
+func TestBTCLightClientWalksToTheHeight(t *testing.T) {
+	stub := newStubLightClient(0, 5000)
+	lightClient, err := newBTCLightClient(stub, 10)
+	require.NoError(t, err)
+
+	// the first page is a regular page, the next page reaches the height at once
+	blockHash, err := lightClient.GetBlockHashByHeight(context.Background(), 4500)
+	require.NoError(t, err)
+	require.Equal(t, stub.hash(4500), *blockHash)
+	require.Equal(t, int64(2), stub.mainChainQueries.Load())
+}
+
+func TestBTCLightClientReorg(t *testing.T) {
+	stub := newStubLightClient(0, 100)
+	lightClient, err := newBTCLightClient(stub, 100)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	blockHash, err := lightClient.GetBlockHashByHeight(ctx, 95)
+	require.NoError(t, err)
+	require.Equal(t, stub.hash(95), *blockHash)
+
+	// replace the last 10 headers, the cached heights are evicted once the tip changes
+	stub.headers = stub.headers[:90]
+	stub.extend(12, 1)
+	blockHash, err = lightClient.GetBlockHashByHeight(ctx, 95)
+	require.NoError(t, err)
+	require.Equal(t, stub.hash(95), *blockHash)
+}
+
+func TestBTCLightClientGetBlockHeaderByHash(t *testing.T) {
+	stub := newStubLightClient(0, 100)
+	lightClient, err := newBTCLightClient(stub, 100)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	blockHash := stub.hash(42)
+	header, err := lightClient.GetBlockHeaderByHash(ctx, &blockHash)
+	require.NoError(t, err)
+	require.Equal(t, blockHash, header.BlockHash())
+	require.Equal(t, int64(1), stub.mainChainQueries.Load())
+
+	// headers are cached by hash
+	header, err = lightClient.GetBlockHeaderByHash(ctx, &blockHash)
+	require.NoError(t, err)
+	require.Equal(t, blockHash, header.BlockHash())
+	require.Equal(t, int64(1), stub.mainChainQueries.Load())
+
+	// the headers seen while walking the main chain are cached as well
+	walkedHash, err := lightClient.GetBlockHashByHeight(ctx, 10)
+	require.NoError(t, err)
+	mainChainQueries := stub.mainChainQueries.Load()
+	header, err = lightClient.GetBlockHeaderByHash(ctx, walkedHash)
+	require.NoError(t, err)
+	require.Equal(t, *walkedHash, header.BlockHash())
+	require.Equal(t, mainChainQueries, stub.mainChainQueries.Load())
+
+	unknownHash := chainhash.Hash{1}
+	_, err = lightClient.GetBlockHeaderByHash(ctx, &unknownHash)
+	require.Error(t, err)
+}
@@ -0,0 +1,40 @@
+package bbnclient
+
+import "time"
+
+const (
+	defaultPowerCacheSize  = 1000
+	defaultPowerCacheDepth = 1008 // ~1 week of BTC blocks
+	defaultPowerCacheTTL   = 10 * time.Minute
+	defaultPageSize        = 100
+	defaultMaxConcurrency  = 10
+	defaultEndpointTimeout = 5 * time.Second
+	defaultEndpointBackoff = 5 * time.Second
+	defaultProbeInterval   = 30 * time.Second
+)
+
+// BBNConfig defines configuration for the Babylon query client
+type BBNConfig struct {
+	PowerCacheSize  int           `mapstructure:"power-cache-size" long:"power-cache-size" description:"The max number of (consumer ID, BTC height) voting power tables to cache. Set to 0 to disable the cache."`
+	PowerCacheDepth uint64        `mapstructure:"power-cache-depth" long:"power-cache-depth" description:"Voting power tables of BTC heights that are this many blocks below the latest cached height are evicted."`
+	PowerCacheTTL   time.Duration `mapstructure:"power-cache-ttl" long:"power-cache-ttl" description:"The time a voting power table is cached for, before it is queried again as the delegations may have changed. Set to 0 to keep the tables until they are evicted."`
+	PageSize        uint64        `mapstructure:"page-size" long:"page-size" description:"The number of items requested per page when paginating Babylon queries. Set to 0 to use the default page size of the Babylon node."`
+	MaxConcurrency  int           `mapstructure:"max-concurrency" long:"max-concurrency" description:"The max number of finality providers queried concurrently. Values below 1 query the finality providers one at a time."`
+	// the settings below apply to each endpoint of the RPCPool
+	EndpointTimeout time.Duration `mapstructure:"endpoint-timeout" long:"endpoint-timeout" description:"The timeout of a call to a Babylon RPC endpoint, before failing over to the next endpoint. Set to 0 to disable."`
+	EndpointBackoff time.Duration `mapstructure:"endpoint-backoff" long:"endpoint-backoff" description:"The time a failing Babylon RPC endpoint is skipped for, doubled on each consecutive failure."`
+	ProbeInterval   time.Duration `mapstructure:"probe-interval" long:"probe-interval" description:"The interval between health checks of a Babylon RPC endpoint that serves no queries. Set to 0 to disable."`
+}
+
+func DefaultBBNConfig() *BBNConfig {
+	return &BBNConfig{
+		PowerCacheSize:  defaultPowerCacheSize,
+		PowerCacheDepth: defaultPowerCacheDepth,
+		PowerCacheTTL:   defaultPowerCacheTTL,
+		PageSize:        defaultPageSize,
+		MaxConcurrency:  defaultMaxConcurrency,
+		EndpointTimeout: defaultEndpointTimeout,
+		EndpointBackoff: defaultEndpointBackoff,
+		ProbeInterval:   defaultProbeInterval,
+	}
+}
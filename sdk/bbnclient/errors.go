@@ -0,0 +1,9 @@
+package bbnclient
+
+import "fmt"
+
+var (
+	ErrPowerOverflow   = fmt.Errorf("voting power of the FP overflows uint64")
+	ErrPaginationStuck = fmt.Errorf("paginated query does not make progress")
+	ErrRPCPoolClosed   = fmt.Errorf("the Babylon RPC pool is closed")
+)
@@ -0,0 +1,194 @@
+package bbnclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cometbft/cometbft/libs/service"
+	cmttypes "github.com/cometbft/cometbft/types"
+)
+
+const (
+	// newBlockSubscriber is the subscriber name of the new block subscription. CometBFT ignores it and keys the
+	// subscriptions of a websocket by query, so a client makes one subscription shared by its subscribers
+	newBlockSubscriber     = "babylon-finality-gadget"
+	newBlockEventsCapacity = 16
+)
+
+// newBlockSubscription is the CometBFT subscription to the new Babylon blocks shared by the callers of
+// SubscribeNewBlocks. It is made by the first subscriber, and removed once the last one leaves or the events stop
+type newBlockSubscription struct {
+	// subscribers receive the heights of the new blocks until their context is done, it is guarded by
+	// Client.newBlocksMu
+	subscribers map[chan uint64]context.Context
+	// ctx is cancelled once the subscription is being removed, done is closed once it is removed
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// SubscribeNewBlocks subscribes to the new Babylon blocks with a CometBFT event subscription, starting the websocket
+// of the RPC client if needed, and returns the heights of the new blocks. The channel is closed once the context is
+// done or the subscription ends, e.g. the websocket is stopped
+//
+// the subscribers share one subscription, which is removed with the last subscriber, so that a subscriber that
+// resubscribes alone gets a new subscription. The heights are dropped rather than blocking the websocket if the
+// channel is not drained
+func (bbnClient *Client) SubscribeNewBlocks(ctx context.Context) (<-chan uint64, error) {
+	bbnClient.newBlocksMu.Lock()
+	for sub := bbnClient.newBlocks; sub != nil; sub = bbnClient.newBlocks {
+		// the subscribers that left are removed now rather than once their context is seen done, so that a subscriber
+		// that resubscribes alone does not join the subscription it left
+		for heights, subscriberCtx := range sub.subscribers {
+			if subscriberCtx.Err() != nil {
+				bbnClient.removeNewBlockSubscriberLocked(sub, heights)
+			}
+		}
+		if sub.ctx.Err() == nil {
+			break
+		}
+		// the subscription being removed is waited for, as its removal would remove the new one too
+		bbnClient.newBlocksMu.Unlock()
+		select {
+		case <-sub.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		bbnClient.newBlocksMu.Lock()
+	}
+	defer bbnClient.newBlocksMu.Unlock()
+
+	sub := bbnClient.newBlocks
+	if sub == nil {
+		var err error
+		if sub, err = bbnClient.subscribeNewBlocks(ctx); err != nil {
+			return nil, err
+		}
+		bbnClient.newBlocks = sub
+	}
+
+	heights := make(chan uint64, 1)
+	sub.subscribers[heights] = ctx
+	go func() {
+		select {
+		case <-ctx.Done():
+			bbnClient.removeNewBlockSubscriber(sub, heights)
+		case <-sub.done:
+		}
+	}()
+	return heights, nil
+}
+
+// subscribeNewBlocks makes the CometBFT subscription to the new Babylon blocks, and forwards its events to the
+// subscribers until it is removed. It must be called with newBlocksMu held
+func (bbnClient *Client) subscribeNewBlocks(ctx context.Context) (*newBlockSubscription, error) {
+	if err := startWebsocket(bbnClient.rpcClient); err != nil {
+		return nil, err
+	}
+	query := cmttypes.EventQueryNewBlockHeader.String()
+	events, err := bbnClient.rpcClient.Subscribe(ctx, newBlockSubscriber, query, newBlockEventsCapacity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to the new Babylon blocks: %w", err)
+	}
+
+	subCtx, cancel := context.WithCancel(context.Background())
+	sub := &newBlockSubscription{
+		subscribers: make(map[chan uint64]context.Context),
+		ctx:         subCtx,
+		cancel:      cancel,
+		done:        make(chan struct{}),
+	}
+	go func() {
+		defer func() {
+			cancel()
+			_ = bbnClient.rpcClient.Unsubscribe(context.Background(), newBlockSubscriber, query)
+
+			bbnClient.newBlocksMu.Lock()
+			defer bbnClient.newBlocksMu.Unlock()
+			for ch := range sub.subscribers {
+				close(ch)
+			}
+			sub.subscribers = nil
+			bbnClient.newBlocks = nil
+			close(sub.done)
+		}()
+		for {
+			select {
+			case <-subCtx.Done():
+				return
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				header, ok := event.Data.(cmttypes.EventDataNewBlockHeader)
+				if !ok {
+					continue
+				}
+				bbnClient.publishNewBlock(sub, uint64(header.Header.Height))
+			}
+		}
+	}()
+	return sub, nil
+}
+
+func (bbnClient *Client) publishNewBlock(sub *newBlockSubscription, height uint64) {
+	bbnClient.newBlocksMu.Lock()
+	defer bbnClient.newBlocksMu.Unlock()
+	for ch := range sub.subscribers {
+		select {
+		case ch <- height:
+		default:
+		}
+	}
+}
+
+// removeNewBlockSubscriber closes the channel of the subscriber, and removes the subscription with the last one
+func (bbnClient *Client) removeNewBlockSubscriber(sub *newBlockSubscription, heights chan uint64) {
+	bbnClient.newBlocksMu.Lock()
+	defer bbnClient.newBlocksMu.Unlock()
+	bbnClient.removeNewBlockSubscriberLocked(sub, heights)
+}
+
+// removeNewBlockSubscriberLocked is removeNewBlockSubscriber with newBlocksMu held
+func (bbnClient *Client) removeNewBlockSubscriberLocked(sub *newBlockSubscription, heights chan uint64) {
+	if _, ok := sub.subscribers[heights]; !ok {
+		return
+	}
+	delete(sub.subscribers, heights)
+	close(heights)
+	if len(sub.subscribers) == 0 {
+		sub.cancel()
+	}
+}
+
+// websocketClient is an RPC client with a websocket to start before subscribing, e.g. the CometBFT HTTP client
+type websocketClient interface {
+	IsRunning() bool
+	Start() error
+	Stop() error
+}
+
+// startWebsocket starts the websocket of the RPC client if it has one and it is not running yet
+func startWebsocket(rpcClient interface{}) error {
+	client, ok := rpcClient.(websocketClient)
+	if !ok || client.IsRunning() {
+		return nil
+	}
+	if err := client.Start(); err != nil && !errors.Is(err, service.ErrAlreadyStarted) {
+		return fmt.Errorf("failed to start the Babylon RPC websocket: %w", err)
+	}
+	return nil
+}
+
+// stopWebsocket stops the websocket of the RPC client if it has one and it is running
+func stopWebsocket(rpcClient interface{}) error {
+	client, ok := rpcClient.(websocketClient)
+	if !ok || !client.IsRunning() {
+		return nil
+	}
+	if err := client.Stop(); err != nil && !errors.Is(err, service.ErrAlreadyStopped) {
+		return fmt.Errorf("failed to stop the Babylon RPC websocket: %w", err)
+	}
+	return nil
+}
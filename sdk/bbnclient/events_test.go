@@ -0,0 +1,170 @@
+package bbnclient
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	rpcclient "github.com/cometbft/cometbft/rpc/client"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEventsClient publishes the events sent to it to its single subscription, and counts the subscriptions
+type fakeEventsClient struct {
+	rpcclient.Client
+	events chan ctypes.ResultEvent
+
+	running         atomic.Bool
+	subscriptions   atomic.Int64
+	unsubscriptions atomic.Int64
+}
+
+func (c *fakeEventsClient) IsRunning() bool {
+	return c.running.Load()
+}
+
+func (c *fakeEventsClient) Start() error {
+	c.running.Store(true)
+	return nil
+}
+
+func (c *fakeEventsClient) Subscribe(
+	_ context.Context,
+	_ string,
+	query string,
+	_ ...int,
+) (<-chan ctypes.ResultEvent, error) {
+	if query != cmttypes.EventQueryNewBlockHeader.String() {
+		return nil, context.Canceled
+	}
+	c.subscriptions.Add(1)
+	return c.events, nil
+}
+
+func (c *fakeEventsClient) Unsubscribe(_ context.Context, _ string, _ string) error {
+	c.unsubscriptions.Add(1)
+	return nil
+}
+
+func TestSubscribeNewBlocks(t *testing.T) {
+	rpcClient := &fakeEventsClient{events: make(chan ctypes.ResultEvent)}
+	bbnClient := &Client{rpcClient: rpcClient}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	heights, err := bbnClient.SubscribeNewBlocks(ctx)
+	require.NoError(t, err)
+	// the websocket is started
+	require.True(t, rpcClient.IsRunning())
+
+	rpcClient.events <- ctypes.ResultEvent{Data: cmttypes.EventDataNewBlockHeader{Header: cmttypes.Header{Height: 10}}}
+	require.Equal(t, uint64(10), <-heights)
+	// the other events are skipped
+	rpcClient.events <- ctypes.ResultEvent{Data: cmttypes.EventDataTx{}}
+	rpcClient.events <- ctypes.ResultEvent{Data: cmttypes.EventDataNewBlockHeader{Header: cmttypes.Header{Height: 11}}}
+	require.Equal(t, uint64(11), <-heights)
+
+	// the subscription ends with the context
+	cancel()
+	select {
+	case _, ok := <-heights:
+		require.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("the channel is not closed")
+	}
+	require.Eventually(t, func() bool { return rpcClient.unsubscriptions.Load() == 1 }, time.Second, time.Millisecond)
+}
+
+// requireHeight checks that the next height of the channel is the height
+func requireHeight(t *testing.T, heights <-chan uint64, height uint64) {
+	select {
+	case h, ok := <-heights:
+		require.True(t, ok)
+		require.Equal(t, height, h)
+	case <-time.After(time.Second):
+		t.Fatalf("no height %d", height)
+	}
+}
+
+// requireClosed checks that the channel is closed
+func requireClosed(t *testing.T, heights <-chan uint64) {
+	select {
+	case _, ok := <-heights:
+		require.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("the channel is not closed")
+	}
+}
+
+func TestSubscribeNewBlocksShared(t *testing.T) {
+	rpcClient := &fakeEventsClient{events: make(chan ctypes.ResultEvent)}
+	bbnClient := &Client{rpcClient: rpcClient}
+	newBlock := func(height int64) ctypes.ResultEvent {
+		return ctypes.ResultEvent{Data: cmttypes.EventDataNewBlockHeader{Header: cmttypes.Header{Height: height}}}
+	}
+
+	// the subscribers share one subscription
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	defer cancel1()
+	heights1, err := bbnClient.SubscribeNewBlocks(ctx1)
+	require.NoError(t, err)
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	defer cancel2()
+	heights2, err := bbnClient.SubscribeNewBlocks(ctx2)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, rpcClient.subscriptions.Load())
+	rpcClient.events <- newBlock(10)
+	requireHeight(t, heights1, 10)
+	requireHeight(t, heights2, 10)
+
+	// the subscription is kept while a subscriber is left
+	cancel1()
+	requireClosed(t, heights1)
+	rpcClient.events <- newBlock(11)
+	requireHeight(t, heights2, 11)
+	require.Zero(t, rpcClient.unsubscriptions.Load())
+
+	// and is removed with the last one, so that the next subscriber makes a new subscription
+	cancel2()
+	requireClosed(t, heights2)
+	ctx3, cancel3 := context.WithCancel(context.Background())
+	defer cancel3()
+	heights3, err := bbnClient.SubscribeNewBlocks(ctx3)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, rpcClient.unsubscriptions.Load())
+	require.EqualValues(t, 2, rpcClient.subscriptions.Load())
+	rpcClient.events <- newBlock(12)
+	requireHeight(t, heights3, 12)
+}
+
+func TestSubscribeNewBlocksEnds(t *testing.T) {
+	rpcClient := &fakeEventsClient{events: make(chan ctypes.ResultEvent)}
+	bbnClient := &Client{rpcClient: rpcClient}
+	heights, err := bbnClient.SubscribeNewBlocks(context.Background())
+	require.NoError(t, err)
+
+	// the events stop, e.g. the websocket is stopped, so the subscribers are told to resubscribe
+	close(rpcClient.events)
+	requireClosed(t, heights)
+	require.EqualValues(t, 1, rpcClient.unsubscriptions.Load())
+}
+
+func TestSubscribeNewBlocksResubscribe(t *testing.T) {
+	rpcClient := &fakeEventsClient{events: make(chan ctypes.ResultEvent)}
+	bbnClient := &Client{rpcClient: rpcClient}
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err := bbnClient.SubscribeNewBlocks(ctx)
+	require.NoError(t, err)
+
+	// the subscriber that left right before resubscribing alone gets a new subscription
+	cancel()
+	heights, err := bbnClient.SubscribeNewBlocks(context.Background())
+	require.NoError(t, err)
+	require.EqualValues(t, 1, rpcClient.unsubscriptions.Load())
+	require.EqualValues(t, 2, rpcClient.subscriptions.Load())
+	rpcClient.events <- ctypes.ResultEvent{Data: cmttypes.EventDataNewBlockHeader{Header: cmttypes.Header{Height: 10}}}
+	requireHeight(t, heights, 10)
+}
@@ -0,0 +1,97 @@
+package bbnclient
+
+import (
+	"bytes"
+	"context"
+
+	btcstakingtypes "github.com/babylonchain/babylon/x/btcstaking/types"
+	bsctypes "github.com/babylonchain/babylon/x/btcstkconsumer/types"
+	sdkquerytypes "github.com/cosmos/cosmos-sdk/types/query"
+)
+
+// maxPages bounds the number of pages fetched by a single paginated query, as a safeguard against
+// a node that never stops returning a next key
+const maxPages = 100_000
+
+// pageQuery queries a single page and returns the pagination of the response
+type pageQuery func(ctx context.Context, pagination *sdkquerytypes.PageRequest) (*sdkquerytypes.PageResponse, error)
+
+// paginate issues the query page by page, following NextKey until the last page
+//
+// it returns ErrPaginationStuck if the node returns the same next key twice in a row
+func paginate(ctx context.Context, pageSize uint64, query pageQuery) error {
+	var key []byte
+	for page := 0; page < maxPages; page++ {
+		pagination := &sdkquerytypes.PageRequest{
+			Key:   key,
+			Limit: pageSize,
+		}
+		resp, err := query(ctx, pagination)
+		if err != nil {
+			return err
+		}
+		if resp == nil || len(resp.NextKey) == 0 {
+			return nil
+		}
+		if key != nil && bytes.Equal(resp.NextKey, key) {
+			return ErrPaginationStuck
+		}
+		key = resp.NextKey
+	}
+	return ErrPaginationStuck
+}
+
+// forEachFpDelegation calls fn for each BTC delegation of the FP, across all pages
+//
+// the iteration stops at the first error returned by fn
+func (bbnClient *Client) forEachFpDelegation(
+	ctx context.Context,
+	fpPubkeyHex string,
+	fn func(btcDel *btcstakingtypes.BTCDelegationResponse) error,
+) error {
+	return paginate(ctx, bbnClient.pageSize, func(
+		ctx context.Context,
+		pagination *sdkquerytypes.PageRequest,
+	) (*sdkquerytypes.PageResponse, error) {
+		// queries the BTCStaking module for a page of delegations of a finality provider
+		resp, err := bbnClient.queryClient.FinalityProviderDelegations(ctx, fpPubkeyHex, pagination)
+		if err != nil {
+			return nil, err
+		}
+		// btcDels contains the BTC delegations of a BTC delegator
+		for _, btcDels := range resp.BtcDelegatorDelegations {
+			for _, btcDel := range btcDels.Dels {
+				if err := fn(btcDel); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return resp.Pagination, nil
+	})
+}
+
+// forEachConsumerFp calls fn for each finality provider of the consumer chain, across all pages
+//
+// the iteration stops at the first error returned by fn
+func (bbnClient *Client) forEachConsumerFp(
+	ctx context.Context,
+	consumerId string,
+	fn func(fp *bsctypes.FinalityProviderResponse) error,
+) error {
+	return paginate(ctx, bbnClient.pageSize, func(
+		ctx context.Context,
+		pagination *sdkquerytypes.PageRequest,
+	) (*sdkquerytypes.PageResponse, error) {
+		// queries the BTCStkConsumer module for a page of finality providers of a consumer chain
+		resp, err := bbnClient.queryClient.ConsumerFinalityProviders(ctx, consumerId, pagination)
+		if err != nil {
+			return nil, err
+		}
+		for _, fp := range resp.FinalityProviders {
+			if err := fn(fp); err != nil {
+				return nil, err
+			}
+		}
+		return resp.Pagination, nil
+	})
+}
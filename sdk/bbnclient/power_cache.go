@@ -0,0 +1,169 @@
+package bbnclient
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+)
+
+type powerCacheKey struct {
+	consumerId string
+	btcHeight  uint64
+}
+
+// powerCache is a bounded LRU cache of the FP -> voting power table at a BTC height of a consumer chain
+//
+// consecutive L2 blocks usually map to the same BTC height, so caching the table avoids re-fetching the
+// delegations of every FP for every L2 block. Once the cache sees a BTC height, the tables of heights more
+// than `depth` blocks below it are evicted, as L2 blocks that map to such deep heights are no longer queried
+//
+// the voting power at a BTC height still changes after it is cached, e.g. a delegation receives its covenant
+// signatures or is unbonded, so a table expires `ttl` after it was first cached, and is then queried again
+//
+// a nil powerCache is valid and caches nothing
+type powerCache struct {
+	mu    sync.Mutex
+	cache *lru.Cache
+	depth uint64
+	ttl   time.Duration
+	now   func() time.Time
+	// the cached BTC heights of each consumer chain
+	heights map[string]*cachedHeights
+}
+
+// cachedHeights are the BTC heights cached for a consumer chain, so that the heights that become too deep are
+// evicted without scanning the whole cache
+//
+// a height stays tracked after its table is evicted by the LRU policy or expires, until it is too deep, so that at
+// most depth+1 heights are tracked
+type cachedHeights struct {
+	// latest is the highest cached height
+	latest uint64
+	// heights is a min-heap of the tracked heights, tracked is the set of its heights
+	heights heightHeap
+	tracked map[uint64]struct{}
+}
+
+// heightHeap is a min-heap of BTC heights, see container/heap
+type heightHeap []uint64
+
+func (h heightHeap) Len() int           { return len(h) }
+func (h heightHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h heightHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *heightHeap) Push(x any)        { *h = append(*h, x.(uint64)) }
+
+func (h *heightHeap) Pop() any {
+	old := *h
+	height := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return height
+}
+
+// powerCacheEntry is a cached voting power table, with the time it was first cached
+type powerCacheEntry struct {
+	table    map[string]uint64
+	cachedAt time.Time
+}
+
+// newPowerCache creates a cache of at most size tables, or returns nil if size is not positive. A ttl of 0 keeps
+// the tables until they are evicted
+func newPowerCache(size int, depth uint64, ttl time.Duration) (*powerCache, error) {
+	if size <= 0 {
+		return nil, nil
+	}
+	cache, err := lru.New(size)
+	if err != nil {
+		return nil, err
+	}
+	return &powerCache{
+		cache:   cache,
+		depth:   depth,
+		ttl:     ttl,
+		now:     time.Now,
+		heights: make(map[string]*cachedHeights),
+	}, nil
+}
+
+// get returns the cached voting power table, which may only cover part of the FPs of the consumer chain
+func (c *powerCache) get(consumerId string, btcHeight uint64) (map[string]uint64, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := powerCacheKey{consumerId: consumerId, btcHeight: btcHeight}
+	value, ok := c.cache.Get(key)
+	if !ok {
+		return nil, false
+	}
+	entry := value.(*powerCacheEntry)
+	if c.isExpired(entry) {
+		c.cache.Remove(key)
+		return nil, false
+	}
+	return entry.table, true
+}
+
+// add merges the given voting powers into the cached table of the BTC height
+func (c *powerCache) add(consumerId string, btcHeight uint64, fpPowers map[string]uint64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	heights, ok := c.heights[consumerId]
+	if ok && c.isTooDeep(btcHeight, heights.latest) {
+		// already too deep to be cached
+		return
+	}
+
+	key := powerCacheKey{consumerId: consumerId, btcHeight: btcHeight}
+	// the cached table is never mutated in place, as callers may still hold it
+	entry := &powerCacheEntry{table: make(map[string]uint64, len(fpPowers)), cachedAt: c.now()}
+	if value, ok := c.cache.Peek(key); ok && !c.isExpired(value.(*powerCacheEntry)) {
+		// the merged table expires with the powers cached first
+		cached := value.(*powerCacheEntry)
+		for fpPk, power := range cached.table {
+			entry.table[fpPk] = power
+		}
+		entry.cachedAt = cached.cachedAt
+	}
+	for fpPk, power := range fpPowers {
+		entry.table[fpPk] = power
+	}
+	c.cache.Add(key, entry)
+
+	if !ok {
+		heights = &cachedHeights{latest: btcHeight, tracked: make(map[uint64]struct{})}
+		c.heights[consumerId] = heights
+	}
+	if _, tracked := heights.tracked[btcHeight]; !tracked {
+		heights.tracked[btcHeight] = struct{}{}
+		heap.Push(&heights.heights, btcHeight)
+	}
+	if btcHeight > heights.latest {
+		heights.latest = btcHeight
+		c.evictDeepHeights(consumerId, heights)
+	}
+}
+
+// evictDeepHeights removes the tables that are more than `depth` blocks below the latest height, deepest first
+func (c *powerCache) evictDeepHeights(consumerId string, heights *cachedHeights) {
+	for heights.heights.Len() > 0 && c.isTooDeep(heights.heights[0], heights.latest) {
+		btcHeight := heap.Pop(&heights.heights).(uint64)
+		delete(heights.tracked, btcHeight)
+		c.cache.Remove(powerCacheKey{consumerId: consumerId, btcHeight: btcHeight})
+	}
+}
+
+func (c *powerCache) isExpired(entry *powerCacheEntry) bool {
+	return c.ttl > 0 && c.now().Sub(entry.cachedAt) >= c.ttl
+}
+
+func (c *powerCache) isTooDeep(btcHeight, latestHeight uint64) bool {
+	return btcHeight < latestHeight && latestHeight-btcHeight > c.depth
+}
@@ -0,0 +1,124 @@
+package bbnclient
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPowerCache(t *testing.T) {
+	const consumerId = "consumer-chain-id"
+
+	t.Run("merges tables of the same height", func(t *testing.T) {
+		cache, err := newPowerCache(10, 100, 0)
+		require.NoError(t, err)
+
+		cache.add(consumerId, 111, map[string]uint64{"pk1": 100})
+		cache.add(consumerId, 111, map[string]uint64{"pk2": 200})
+
+		table, ok := cache.get(consumerId, 111)
+		require.True(t, ok)
+		require.Equal(t, map[string]uint64{"pk1": 100, "pk2": 200}, table)
+
+		_, ok = cache.get("other-consumer-id", 111)
+		require.False(t, ok)
+	})
+
+	t.Run("evicts least recently used heights", func(t *testing.T) {
+		cache, err := newPowerCache(2, 100, 0)
+		require.NoError(t, err)
+
+		cache.add(consumerId, 111, map[string]uint64{"pk1": 100})
+		cache.add(consumerId, 112, map[string]uint64{"pk1": 100})
+		_, ok := cache.get(consumerId, 111)
+		require.True(t, ok)
+		cache.add(consumerId, 113, map[string]uint64{"pk1": 100})
+
+		_, ok = cache.get(consumerId, 112)
+		require.False(t, ok)
+		_, ok = cache.get(consumerId, 111)
+		require.True(t, ok)
+	})
+
+	t.Run("evicts heights that are too deep", func(t *testing.T) {
+		cache, err := newPowerCache(10, 5, 0)
+		require.NoError(t, err)
+
+		cache.add(consumerId, 100, map[string]uint64{"pk1": 100})
+		cache.add(consumerId, 105, map[string]uint64{"pk1": 100})
+		_, ok := cache.get(consumerId, 100)
+		require.True(t, ok)
+
+		cache.add(consumerId, 106, map[string]uint64{"pk1": 100})
+		_, ok = cache.get(consumerId, 100)
+		require.False(t, ok)
+		_, ok = cache.get(consumerId, 105)
+		require.True(t, ok)
+
+		// heights below the window are not cached any more
+		cache.add(consumerId, 99, map[string]uint64{"pk1": 100})
+		_, ok = cache.get(consumerId, 99)
+		require.False(t, ok)
+	})
+
+	t.Run("tracks the heights within the depth", func(t *testing.T) {
+		cache, err := newPowerCache(2, 5, 0)
+		require.NoError(t, err)
+
+		for height := uint64(100); height < 200; height++ {
+			cache.add(consumerId, height, map[string]uint64{"pk1": 100})
+			// merging into a cached height does not track it twice
+			cache.add(consumerId, height, map[string]uint64{"pk2": 200})
+			// the heights of other consumer chains are tracked apart
+			cache.add("other-consumer-id", 1000, map[string]uint64{"pk1": 100})
+		}
+		// the heights whose tables were evicted by the LRU policy stay tracked until they are too deep
+		heights := cache.heights[consumerId]
+		require.Equal(t, uint64(199), heights.latest)
+		require.Len(t, heights.heights, 6)
+		require.Len(t, heights.tracked, 6)
+		require.Equal(t, uint64(194), heights.heights[0])
+		require.Len(t, cache.heights["other-consumer-id"].heights, 1)
+
+		_, ok := cache.get(consumerId, 199)
+		require.True(t, ok)
+		_, ok = cache.get("other-consumer-id", 1000)
+		require.True(t, ok)
+	})
+
+	t.Run("expires tables", func(t *testing.T) {
+		cache, err := newPowerCache(10, 100, time.Minute)
+		require.NoError(t, err)
+		now := time.Unix(1_700_000_000, 0)
+		cache.now = func() time.Time { return now }
+
+		cache.add(consumerId, 111, map[string]uint64{"pk1": 100})
+		now = now.Add(30 * time.Second)
+		// the merged table expires with the powers cached first
+		cache.add(consumerId, 111, map[string]uint64{"pk2": 200})
+		table, ok := cache.get(consumerId, 111)
+		require.True(t, ok)
+		require.Equal(t, map[string]uint64{"pk1": 100, "pk2": 200}, table)
+
+		now = now.Add(30 * time.Second)
+		_, ok = cache.get(consumerId, 111)
+		require.False(t, ok)
+
+		// an expired table is not merged into
+		cache.add(consumerId, 111, map[string]uint64{"pk2": 300})
+		table, ok = cache.get(consumerId, 111)
+		require.True(t, ok)
+		require.Equal(t, map[string]uint64{"pk2": 300}, table)
+	})
+
+	t.Run("disabled cache", func(t *testing.T) {
+		cache, err := newPowerCache(0, 5, 0)
+		require.NoError(t, err)
+		require.Nil(t, cache)
+
+		cache.add(consumerId, 100, map[string]uint64{"pk1": 100})
+		_, ok := cache.get(consumerId, 100)
+		require.False(t, ok)
+	})
+}
@@ -0,0 +1,117 @@
+package bbnclient
+
+import (
+	"context"
+
+	btcctypes "github.com/babylonchain/babylon/x/btccheckpoint/types"
+	btclctypes "github.com/babylonchain/babylon/x/btclightclient/types"
+	btcstakingtypes "github.com/babylonchain/babylon/x/btcstaking/types"
+	bsctypes "github.com/babylonchain/babylon/x/btcstkconsumer/types"
+	sdkquerytypes "github.com/cosmos/cosmos-sdk/types/query"
+
+	"github.com/babylonchain/babylon-finality-gadget/sdk/internal/abciquery"
+)
+
+// DefaultTimeout bounds a Babylon query when the caller's context has no deadline.
+// It matches the default timeout of the Babylon query client
+const DefaultTimeout = abciquery.DefaultTimeout
+
+// babylonQueryClient is the set of Babylon gRPC queries used by Client
+type babylonQueryClient interface {
+	ConsumerFinalityProviders(
+		ctx context.Context,
+		consumerId string,
+		pagination *sdkquerytypes.PageRequest,
+	) (*bsctypes.QueryFinalityProvidersResponse, error)
+	FinalityProviderDelegations(
+		ctx context.Context,
+		fpPubkeyHex string,
+		pagination *sdkquerytypes.PageRequest,
+	) (*btcstakingtypes.QueryFinalityProviderDelegationsResponse, error)
+	BTCCheckpointParams(ctx context.Context) (*btcctypes.QueryParamsResponse, error)
+	BTCStakingParams(ctx context.Context) (*btcstakingtypes.QueryParamsResponse, error)
+	BTCHeaderChainTip(ctx context.Context) (*btclctypes.QueryTipResponse, error)
+	BTCMainChain(ctx context.Context, pagination *sdkquerytypes.PageRequest) (*btclctypes.QueryMainChainResponse, error)
+}
+
+// rpcQueryClient sends the Babylon gRPC queries over the CometBFT RPC client
+type rpcQueryClient struct {
+	RPCClient
+}
+
+// ConsumerFinalityProviders queries the BTCStkConsumer module for the finality providers of a consumer chain
+func (c *rpcQueryClient) ConsumerFinalityProviders(
+	ctx context.Context,
+	consumerId string,
+	pagination *sdkquerytypes.PageRequest,
+) (*bsctypes.QueryFinalityProvidersResponse, error) {
+	req := &bsctypes.QueryFinalityProvidersRequest{
+		ConsumerId: consumerId,
+		Pagination: pagination,
+	}
+	resp := &bsctypes.QueryFinalityProvidersResponse{}
+	if err := abciquery.Query(ctx, c.RPCClient, "/babylon.btcstkconsumer.v1.Query/FinalityProviders", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// FinalityProviderDelegations queries the BTCStaking module for the delegations of a finality provider
+func (c *rpcQueryClient) FinalityProviderDelegations(
+	ctx context.Context,
+	fpPubkeyHex string,
+	pagination *sdkquerytypes.PageRequest,
+) (*btcstakingtypes.QueryFinalityProviderDelegationsResponse, error) {
+	req := &btcstakingtypes.QueryFinalityProviderDelegationsRequest{
+		FpBtcPkHex: fpPubkeyHex,
+		Pagination: pagination,
+	}
+	resp := &btcstakingtypes.QueryFinalityProviderDelegationsResponse{}
+	if err := abciquery.Query(ctx, c.RPCClient, "/babylon.btcstaking.v1.Query/FinalityProviderDelegations", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// BTCCheckpointParams queries the BTCCheckpoint module params, e.g. BtcConfirmationDepth
+func (c *rpcQueryClient) BTCCheckpointParams(ctx context.Context) (*btcctypes.QueryParamsResponse, error) {
+	resp := &btcctypes.QueryParamsResponse{}
+	if err := abciquery.Query(ctx, c.RPCClient, "/babylon.btccheckpoint.v1.Query/Params", &btcctypes.QueryParamsRequest{}, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// BTCStakingParams queries the BTCStaking module params, e.g. CovenantQuorum
+func (c *rpcQueryClient) BTCStakingParams(ctx context.Context) (*btcstakingtypes.QueryParamsResponse, error) {
+	resp := &btcstakingtypes.QueryParamsResponse{}
+	if err := abciquery.Query(ctx, c.RPCClient, "/babylon.btcstaking.v1.Query/Params", &btcstakingtypes.QueryParamsRequest{}, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// BTCHeaderChainTip queries the BTCLightclient module for the latest BTC header
+func (c *rpcQueryClient) BTCHeaderChainTip(ctx context.Context) (*btclctypes.QueryTipResponse, error) {
+	resp := &btclctypes.QueryTipResponse{}
+	if err := abciquery.Query(ctx, c.RPCClient, "/babylon.btclightclient.v1.Query/Tip", &btclctypes.QueryTipRequest{}, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// BTCMainChain queries the BTCLightclient module for a page of the BTC main chain
+//
+// the page starts at the header whose hash is the pagination key, or at the base header (the tip if reversed)
+// if the key is empty
+func (c *rpcQueryClient) BTCMainChain(
+	ctx context.Context,
+	pagination *sdkquerytypes.PageRequest,
+) (*btclctypes.QueryMainChainResponse, error) {
+	req := &btclctypes.QueryMainChainRequest{Pagination: pagination}
+	resp := &btclctypes.QueryMainChainResponse{}
+	if err := abciquery.Query(ctx, c.RPCClient, "/babylon.btclightclient.v1.Query/MainChain", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,31 @@
+package bbnclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryClientCancel(t *testing.T) {
+	rpcClient := &fakeRPCClient{name: "endpoint-0"}
+	rpcClient.stalled.Store(true)
+	queryClient := &rpcQueryClient{RPCClient: rpcClient}
+
+	// the cancellation of the caller reaches the in-flight RPC call
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := queryClient.BTCHeaderChainTip(ctx)
+		errCh <- err
+	}()
+	require.Eventually(t, func() bool { return rpcClient.queries.Load() == 1 }, 5*time.Second, time.Millisecond)
+	cancel()
+	select {
+	case err := <-errCh:
+		require.ErrorIs(t, err, context.Canceled)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the query is not aborted")
+	}
+}
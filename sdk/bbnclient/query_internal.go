@@ -1,28 +1,52 @@
 package bbnclient
 
-import btcstakingtypes "github.com/babylonchain/babylon/x/btcstaking/types"
+import (
+	"context"
 
-// we implemented exact logic as in GetStatus
-// https://github.com/babylonchain/babylon-private/blob/c5a8d317091e2965e20ea56fa10e98d34aaa3547/x/btcstaking/types/btc_delegation.go#L88-L109
-func (bbnClient *Client) isDelegationActive(
-	btcDel *btcstakingtypes.BTCDelegationResponse,
-	btcHeight uint64,
-) (bool, error) {
-	btccheckpointParams, err := bbnClient.QueryClient.BTCCheckpointParams()
+	btcstakingtypes "github.com/babylonchain/babylon/x/btcstaking/types"
+)
+
+// stakingParams is a snapshot of the Babylon params that determine whether a BTC delegation is active
+//
+// the params are fetched once per power computation and shared by all delegations, instead of
+// being queried for every single delegation
+type stakingParams struct {
+	kValue    uint64 // BTC confirmation depth
+	wValue    uint64 // checkpoint finalization timeout
+	covQuorum uint32 // covenant quorum
+}
+
+// queryStakingParams queries the BTCCheckpoint and BTCStaking module params
+func (bbnClient *Client) queryStakingParams(ctx context.Context) (*stakingParams, error) {
+	btccheckpointParams, err := bbnClient.queryClient.BTCCheckpointParams(ctx)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	btcstakingParams, err := bbnClient.QueryClient.BTCStakingParams()
+	btcstakingParams, err := bbnClient.queryClient.BTCStakingParams(ctx)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	kValue := btccheckpointParams.GetParams().BtcConfirmationDepth
-	wValue := btccheckpointParams.GetParams().CheckpointFinalizationTimeout
-	covQuorum := btcstakingParams.GetParams().CovenantQuorum
+	return &stakingParams{
+		kValue:    btccheckpointParams.GetParams().BtcConfirmationDepth,
+		wValue:    btccheckpointParams.GetParams().CheckpointFinalizationTimeout,
+		covQuorum: btcstakingParams.GetParams().CovenantQuorum,
+	}, nil
+}
+
+// we implemented exact logic as in GetStatus
+// https://github.com/babylonchain/babylon-private/blob/c5a8d317091e2965e20ea56fa10e98d34aaa3547/x/btcstaking/types/btc_delegation.go#L88-L109
+func isDelegationActive(
+	btcDel *btcstakingtypes.BTCDelegationResponse,
+	btcHeight uint64,
+	params *stakingParams,
+) bool {
+	kValue := params.kValue
+	wValue := params.wValue
+	covQuorum := params.covQuorum
 	ud := btcDel.UndelegationResponse
 
 	if len(ud.GetDelegatorUnbondingSigHex()) > 0 {
-		return false, nil
+		return false
 	}
 
 	// k is not involved in the `GetStatus` logic as Babylon will accept a BTC delegation request
@@ -37,18 +61,18 @@ func (bbnClient *Client) isDelegationActive(
 	//
 	// So in our case, we need to check both to ensure the delegation is active
 	if btcHeight < btcDel.StartHeight+kValue || btcHeight+wValue > btcDel.EndHeight {
-		return false, nil
+		return false
 	}
 
 	if uint32(len(btcDel.CovenantSigs)) < covQuorum {
-		return false, nil
+		return false
 	}
 	if len(ud.CovenantUnbondingSigList) < int(covQuorum) {
-		return false, nil
+		return false
 	}
 	if len(ud.CovenantSlashingSigs) < int(covQuorum) {
-		return false, nil
+		return false
 	}
 
-	return true, nil
+	return true
 }
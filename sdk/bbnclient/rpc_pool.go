@@ -0,0 +1,354 @@
+package bbnclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cometbft/cometbft/libs/bytes"
+	rpcclient "github.com/cometbft/cometbft/rpc/client"
+	rpchttp "github.com/cometbft/cometbft/rpc/client/http"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/babylon-finality-gadget/sdk/internal/failover"
+)
+
+// RPCClient is the part of the CometBFT RPC client used by the SDK: the ABCI queries, which carry the gRPC queries,
+// Status, and the event subscriptions. It is implemented by RPCPool over several endpoints, and by the CometBFT
+// RPC clients over one
+type RPCClient interface {
+	rpcclient.StatusClient
+	rpcclient.EventsClient
+	ABCIQuery(ctx context.Context, path string, data bytes.HexBytes) (*ctypes.ResultABCIQuery, error)
+	ABCIQueryWithOptions(
+		ctx context.Context,
+		path string,
+		data bytes.HexBytes,
+		opts rpcclient.ABCIQueryOptions,
+	) (*ctypes.ResultABCIQuery, error)
+}
+
+var _ RPCClient = (*RPCPool)(nil)
+
+// rpcEndpoint is a Babylon RPC endpoint of RPCPool, with its health
+type rpcEndpoint struct {
+	addr   string
+	client rpcclient.Client
+	health failover.Health
+	// probing is set while the endpoint is health-checked
+	probing bool
+}
+
+// RPCPool is an RPCClient over a list of Babylon RPC endpoints, with failover. It is meant to be shared by Client,
+// BTCLightClient and the CosmWasm client
+//
+//   - the ABCI queries, which carry the gRPC queries, Status and Subscribe go to the healthy endpoint with the
+//     lowest latency, failing over to the next endpoint on error or timeout. A subscription stays on the endpoint
+//     it was made on, the subscriber resubscribes if the endpoint stops sending events
+//   - the latency of an endpoint is a moving average of the duration of its successful calls. The endpoints
+//     whose latency was not measured for ProbeInterval are health-checked with Status in the background, so
+//     that a recovered or faster endpoint is picked up again
+//   - an endpoint that fails is skipped for a backoff that doubles on each consecutive failure, up to
+//     failover.MaxBackoff, and is health-checked once the backoff expires. If no endpoint is healthy, the
+//     endpoints are queried anyway, the soonest to recover first
+//   - Close stops the health checks and the websockets of the endpoints
+type RPCPool struct {
+	mu        sync.Mutex
+	endpoints []*rpcEndpoint
+	// subscriptions holds the endpoint of each subscription, by subscriber and query
+	subscriptions map[subscription]*rpcEndpoint
+
+	timeout       time.Duration
+	backoff       time.Duration
+	probeInterval time.Duration
+	logger        *zap.Logger
+	now           func() time.Time
+	// ctx is cancelled by Close, which then waits for the background health checks tracked by probes
+	ctx    context.Context
+	cancel context.CancelFunc
+	probes sync.WaitGroup
+}
+
+type subscription struct {
+	subscriber string
+	query      string
+}
+
+// NewRPCPool creates a CometBFT RPC client over the Babylon RPC endpoints at addrs
+func NewRPCPool(addrs []string, cfg *BBNConfig, logger *zap.Logger) (*RPCPool, error) {
+	clients := make([]rpcclient.Client, len(addrs))
+	for i, addr := range addrs {
+		client, err := rpchttp.New(addr, "/websocket")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the RPC client of %s: %w", addr, err)
+		}
+		clients[i] = client
+	}
+	return newRPCPool(addrs, clients, cfg.EndpointTimeout, cfg.EndpointBackoff, cfg.ProbeInterval, logger)
+}
+
+func newRPCPool(
+	addrs []string,
+	clients []rpcclient.Client,
+	timeout time.Duration,
+	backoff time.Duration,
+	probeInterval time.Duration,
+	logger *zap.Logger,
+) (*RPCPool, error) {
+	if len(clients) == 0 {
+		return nil, fmt.Errorf("no Babylon RPC endpoint is configured")
+	}
+
+	endpoints := make([]*rpcEndpoint, len(clients))
+	for i, client := range clients {
+		endpoints[i] = &rpcEndpoint{addr: addrs[i], client: client}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &RPCPool{
+		endpoints:     endpoints,
+		subscriptions: make(map[subscription]*rpcEndpoint),
+		timeout:       timeout,
+		backoff:       backoff,
+		probeInterval: probeInterval,
+		logger:        logger,
+		now:           time.Now,
+		ctx:           ctx,
+		cancel:        cancel,
+	}, nil
+}
+
+// Close stops the health checks of the endpoints and waits for the running ones until the context is done, and
+// stops the websockets of the endpoints
+func (p *RPCPool) Close(ctx context.Context) error {
+	p.mu.Lock()
+	p.cancel()
+	p.subscriptions = make(map[subscription]*rpcEndpoint)
+	p.mu.Unlock()
+
+	probesDone := make(chan struct{})
+	go func() {
+		p.probes.Wait()
+		close(probesDone)
+	}()
+
+	var errs []error
+	for _, ep := range p.endpoints {
+		if err := stopWebsocket(ep.client); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ep.addr, err))
+		}
+	}
+	select {
+	case <-probesDone:
+	case <-ctx.Done():
+		errs = append(errs, fmt.Errorf("the health checks of the Babylon RPC endpoints did not stop: %w", ctx.Err()))
+	}
+	return errors.Join(errs...)
+}
+
+func (p *RPCPool) ABCIQuery(ctx context.Context, path string, data bytes.HexBytes) (*ctypes.ResultABCIQuery, error) {
+	return p.ABCIQueryWithOptions(ctx, path, data, rpcclient.DefaultABCIQueryOptions)
+}
+
+func (p *RPCPool) ABCIQueryWithOptions(
+	ctx context.Context,
+	path string,
+	data bytes.HexBytes,
+	opts rpcclient.ABCIQueryOptions,
+) (*ctypes.ResultABCIQuery, error) {
+	var result *ctypes.ResultABCIQuery
+	// an error of the query itself is returned in the response code, so err is an error of the endpoint
+	err := p.failover(ctx, func(ctx context.Context, ep *rpcEndpoint) error {
+		var err error
+		result, err = ep.client.ABCIQueryWithOptions(ctx, path, data, opts)
+		return err
+	})
+	return result, err
+}
+
+func (p *RPCPool) Status(ctx context.Context) (*ctypes.ResultStatus, error) {
+	var result *ctypes.ResultStatus
+	err := p.failover(ctx, func(ctx context.Context, ep *rpcEndpoint) error {
+		var err error
+		result, err = ep.client.Status(ctx)
+		return err
+	})
+	return result, err
+}
+
+// Subscribe subscribes to the events of the query on the first endpoint that accepts the subscription, starting
+// its websocket if needed
+func (p *RPCPool) Subscribe(
+	ctx context.Context,
+	subscriber string,
+	query string,
+	outCapacity ...int,
+) (<-chan ctypes.ResultEvent, error) {
+	if p.ctx.Err() != nil {
+		return nil, ErrRPCPoolClosed
+	}
+	var events <-chan ctypes.ResultEvent
+	var subscribed *rpcEndpoint
+	err := p.failover(ctx, func(ctx context.Context, ep *rpcEndpoint) error {
+		if err := startWebsocket(ep.client); err != nil {
+			return err
+		}
+		var err error
+		if events, err = ep.client.Subscribe(ctx, subscriber, query, outCapacity...); err != nil {
+			return err
+		}
+		subscribed = ep
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.ctx.Err() != nil {
+		_ = subscribed.client.Unsubscribe(context.Background(), subscriber, query)
+		return nil, ErrRPCPoolClosed
+	}
+	p.subscriptions[subscription{subscriber: subscriber, query: query}] = subscribed
+	return events, nil
+}
+
+// Unsubscribe removes the subscription from the endpoint it was made on
+func (p *RPCPool) Unsubscribe(ctx context.Context, subscriber string, query string) error {
+	key := subscription{subscriber: subscriber, query: query}
+	p.mu.Lock()
+	ep, ok := p.subscriptions[key]
+	delete(p.subscriptions, key)
+	p.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("no subscription of %s to %s", subscriber, query)
+	}
+	return ep.client.Unsubscribe(ctx, subscriber, query)
+}
+
+// UnsubscribeAll removes the subscriptions of the subscriber from the endpoints they were made on
+func (p *RPCPool) UnsubscribeAll(ctx context.Context, subscriber string) error {
+	p.mu.Lock()
+	endpoints := make(map[*rpcEndpoint]struct{})
+	for key, ep := range p.subscriptions {
+		if key.subscriber == subscriber {
+			endpoints[ep] = struct{}{}
+			delete(p.subscriptions, key)
+		}
+	}
+	p.mu.Unlock()
+
+	var errs []error
+	for ep := range endpoints {
+		if err := ep.client.UnsubscribeAll(ctx, subscriber); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ep.addr, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// failover calls the query on the endpoints in order of health and latency, until one succeeds
+func (p *RPCPool) failover(ctx context.Context, query func(ctx context.Context, ep *rpcEndpoint) error) error {
+	var errs []error
+	for _, ep := range p.orderedEndpoints() {
+		err := p.call(ctx, ep, func(ctx context.Context) error {
+			return query(ctx, ep)
+		})
+		if err == nil {
+			return nil
+		}
+		// the caller gave up, the other endpoints would not be queried either
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", ep.addr, err))
+	}
+	return fmt.Errorf("all Babylon RPC endpoints failed: %w", errors.Join(errs...))
+}
+
+// call runs the query against the endpoint within the endpoint timeout, and updates the endpoint health
+func (p *RPCPool) call(ctx context.Context, ep *rpcEndpoint, query func(ctx context.Context) error) error {
+	return failover.Call(ctx, p.timeout, p.now, query, func(latency time.Duration, err error) {
+		p.report(ep, latency, err)
+	})
+}
+
+// report records the outcome and the duration of a call to the endpoint
+func (p *RPCPool) report(ep *rpcEndpoint, latency time.Duration, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err == nil {
+		if ep.health.Succeeded(p.now(), latency) {
+			p.logger.Info("Babylon RPC endpoint recovered", zap.String("endpoint", ep.addr))
+		}
+		return
+	}
+
+	backoff := ep.health.Failed(p.now(), p.backoff)
+	p.logger.Warn("Babylon RPC endpoint failed, skipping it",
+		zap.String("endpoint", ep.addr),
+		zap.Int("consecutive_failures", ep.health.Failures),
+		zap.Duration("backoff", backoff),
+		zap.Error(err))
+}
+
+// orderedEndpoints returns the healthy endpoints, the measured ones by latency followed by the unmeasured ones
+// in the configured order, followed by the unhealthy endpoints, the soonest to recover first
+//
+// it also starts the health checks of the endpoints that are due for one
+func (p *RPCPool) orderedEndpoints() []*rpcEndpoint {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	var healthy, unhealthy []*rpcEndpoint
+	for _, ep := range p.endpoints {
+		if ep.health.Failures > 0 {
+			unhealthy = append(unhealthy, ep)
+			if !now.Before(ep.health.UnhealthyUntil) {
+				p.startProbe(ep)
+			}
+			continue
+		}
+		healthy = append(healthy, ep)
+		if p.probeInterval > 0 && (ep.health.MeasuredAt.IsZero() || now.Sub(ep.health.MeasuredAt) >= p.probeInterval) {
+			p.startProbe(ep)
+		}
+	}
+	sort.SliceStable(healthy, func(i, j int) bool {
+		measured, otherMeasured := !healthy[i].health.MeasuredAt.IsZero(), !healthy[j].health.MeasuredAt.IsZero()
+		if !measured || !otherMeasured {
+			return measured && !otherMeasured
+		}
+		return healthy[i].health.Latency < healthy[j].health.Latency
+	})
+	sort.SliceStable(unhealthy, func(i, j int) bool {
+		return unhealthy[i].health.UnhealthyUntil.Before(unhealthy[j].health.UnhealthyUntil)
+	})
+	return append(healthy, unhealthy...)
+}
+
+// startProbe health-checks the endpoint with Status in the background, unless it is already being checked or the
+// pool is closed. It must be called with p.mu held
+func (p *RPCPool) startProbe(ep *rpcEndpoint) {
+	if ep.probing || p.ctx.Err() != nil {
+		return
+	}
+	ep.probing = true
+	p.probes.Add(1)
+	go func() {
+		defer p.probes.Done()
+		_ = p.call(p.ctx, ep, func(ctx context.Context) error {
+			_, err := ep.client.Status(ctx)
+			return err
+		})
+		p.mu.Lock()
+		ep.probing = false
+		p.mu.Unlock()
+	}()
+}
@@ -0,0 +1,276 @@
+package bbnclient
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/libs/bytes"
+	rpcclient "github.com/cometbft/cometbft/rpc/client"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeRPCClient answers the ABCI queries with its name, failing, stalling or delaying the calls while told to,
+// and counts the calls
+type fakeRPCClient struct {
+	rpcclient.Client
+	name    string
+	delay   time.Duration
+	failing atomic.Bool
+	stalled atomic.Bool
+	running atomic.Bool
+
+	queries         atomic.Int64
+	statuses        atomic.Int64
+	subscriptions   atomic.Int64
+	unsubscriptions atomic.Int64
+}
+
+func (c *fakeRPCClient) IsRunning() bool {
+	return c.running.Load()
+}
+
+func (c *fakeRPCClient) Start() error {
+	c.running.Store(true)
+	return nil
+}
+
+func (c *fakeRPCClient) Stop() error {
+	c.running.Store(false)
+	return nil
+}
+
+func (c *fakeRPCClient) before(ctx context.Context) error {
+	if c.stalled.Load() {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	select {
+	case <-time.After(c.delay):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if c.failing.Load() {
+		return fmt.Errorf("connection refused")
+	}
+	return nil
+}
+
+func (c *fakeRPCClient) ABCIQueryWithOptions(
+	ctx context.Context,
+	_ string,
+	_ bytes.HexBytes,
+	_ rpcclient.ABCIQueryOptions,
+) (*ctypes.ResultABCIQuery, error) {
+	c.queries.Add(1)
+	if err := c.before(ctx); err != nil {
+		return nil, err
+	}
+	return &ctypes.ResultABCIQuery{Response: abci.ResponseQuery{Value: []byte(c.name)}}, nil
+}
+
+func (c *fakeRPCClient) Status(ctx context.Context) (*ctypes.ResultStatus, error) {
+	c.statuses.Add(1)
+	if err := c.before(ctx); err != nil {
+		return nil, err
+	}
+	return &ctypes.ResultStatus{}, nil
+}
+
+func (c *fakeRPCClient) Subscribe(
+	ctx context.Context,
+	_ string,
+	_ string,
+	_ ...int,
+) (<-chan ctypes.ResultEvent, error) {
+	c.subscriptions.Add(1)
+	if err := c.before(ctx); err != nil {
+		return nil, err
+	}
+	return make(chan ctypes.ResultEvent), nil
+}
+
+func (c *fakeRPCClient) Unsubscribe(_ context.Context, _ string, _ string) error {
+	c.unsubscriptions.Add(1)
+	return nil
+}
+
+// newTestRPCPool returns an RPCPool over the clients, with a clock that only moves when told to
+func newTestRPCPool(t *testing.T, probeInterval time.Duration, clients ...*fakeRPCClient) (*RPCPool, *atomic.Int64) {
+	addrs := make([]string, len(clients))
+	rpcClients := make([]rpcclient.Client, len(clients))
+	for i, client := range clients {
+		client.name = fmt.Sprintf("endpoint-%d", i)
+		addrs[i] = client.name
+		rpcClients[i] = client
+	}
+	p, err := newRPCPool(addrs, rpcClients, 50*time.Millisecond, time.Second, probeInterval, zap.NewNop())
+	require.NoError(t, err)
+	var now atomic.Int64
+	now.Store(time.Unix(1_700_000_000, 0).UnixNano())
+	p.now = func() time.Time { return time.Unix(0, now.Load()) }
+	return p, &now
+}
+
+// queryEndpoint returns the name of the endpoint that served an ABCI query, once the health checks are done
+func queryEndpoint(t *testing.T, p *RPCPool) string {
+	res, err := p.ABCIQuery(context.Background(), "/cosmos.base.tendermint.v1beta1.Service/GetNodeInfo", nil)
+	p.probes.Wait()
+	require.NoError(t, err)
+	return string(res.Response.Value)
+}
+
+func TestRPCPoolFailover(t *testing.T) {
+	primary, backup := &fakeRPCClient{}, &fakeRPCClient{}
+	p, now := newTestRPCPool(t, 0, primary, backup)
+
+	require.Equal(t, "endpoint-0", queryEndpoint(t, p))
+
+	// the primary fails, the query fails over to the backup
+	primary.failing.Store(true)
+	require.Equal(t, "endpoint-1", queryEndpoint(t, p))
+	require.EqualValues(t, 2, primary.queries.Load())
+
+	// the primary is skipped during its backoff
+	primary.failing.Store(false)
+	require.Equal(t, "endpoint-1", queryEndpoint(t, p))
+	require.EqualValues(t, 2, primary.queries.Load())
+	require.EqualValues(t, 0, primary.statuses.Load())
+
+	// once the backoff expires, the primary is health-checked before serving queries again
+	now.Add(int64(time.Second))
+	require.Equal(t, "endpoint-1", queryEndpoint(t, p))
+	require.EqualValues(t, 1, primary.statuses.Load())
+	require.Zero(t, p.endpoints[0].health.Failures)
+}
+
+func TestRPCPoolBackoff(t *testing.T) {
+	primary, backup := &fakeRPCClient{}, &fakeRPCClient{}
+	p, now := newTestRPCPool(t, 0, primary, backup)
+	primary.failing.Store(true)
+
+	// the backoff doubles on each failed health check
+	require.Equal(t, "endpoint-1", queryEndpoint(t, p))
+	for _, backoff := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		require.Equal(t, time.Unix(0, now.Load()).Add(backoff), p.endpoints[0].health.UnhealthyUntil)
+		now.Add(int64(backoff))
+		require.Equal(t, "endpoint-1", queryEndpoint(t, p))
+	}
+	require.EqualValues(t, 1, primary.queries.Load())
+	require.EqualValues(t, 3, primary.statuses.Load())
+}
+
+func TestRPCPoolAllEndpointsFail(t *testing.T) {
+	primary, backup := &fakeRPCClient{}, &fakeRPCClient{}
+	p, _ := newTestRPCPool(t, 0, primary, backup)
+	primary.failing.Store(true)
+	backup.failing.Store(true)
+
+	_, err := p.ABCIQuery(context.Background(), "/path", nil)
+	require.ErrorContains(t, err, "endpoint-0: connection refused")
+	require.ErrorContains(t, err, "endpoint-1: connection refused")
+
+	// the unhealthy endpoints are still queried, the soonest to recover first
+	backup.failing.Store(false)
+	require.Equal(t, "endpoint-1", queryEndpoint(t, p))
+}
+
+func TestRPCPoolTimeout(t *testing.T) {
+	primary, backup := &fakeRPCClient{}, &fakeRPCClient{}
+	p, _ := newTestRPCPool(t, 0, primary, backup)
+	primary.stalled.Store(true)
+
+	require.Equal(t, "endpoint-1", queryEndpoint(t, p))
+	require.Equal(t, 1, p.endpoints[0].health.Failures)
+}
+
+func TestRPCPoolCallerCancelled(t *testing.T) {
+	primary, backup := &fakeRPCClient{}, &fakeRPCClient{}
+	p, _ := newTestRPCPool(t, 0, primary, backup)
+	primary.stalled.Store(true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := p.ABCIQuery(ctx, "/path", nil)
+	require.ErrorIs(t, err, context.Canceled)
+	// the endpoint is not blamed, and the backup is not queried
+	require.Zero(t, p.endpoints[0].health.Failures)
+	require.Zero(t, backup.queries.Load())
+}
+
+func TestRPCPoolLatency(t *testing.T) {
+	slow, fast := &fakeRPCClient{delay: 30 * time.Millisecond}, &fakeRPCClient{}
+	p, _ := newTestRPCPool(t, time.Minute, slow, fast)
+	p.now = time.Now
+
+	// the endpoints are not measured yet, the first one serves the query while both are health-checked
+	require.Equal(t, "endpoint-0", queryEndpoint(t, p))
+	require.EqualValues(t, 1, slow.statuses.Load())
+	require.EqualValues(t, 1, fast.statuses.Load())
+
+	// the fastest endpoint serves the queries, and is not health-checked again within the probe interval
+	for i := 0; i < 3; i++ {
+		require.Equal(t, "endpoint-1", queryEndpoint(t, p))
+	}
+	require.EqualValues(t, 1, slow.statuses.Load())
+	require.EqualValues(t, 1, fast.statuses.Load())
+}
+
+func TestRPCPoolSubscribe(t *testing.T) {
+	primary, backup := &fakeRPCClient{}, &fakeRPCClient{}
+	p, _ := newTestRPCPool(t, 0, primary, backup)
+	primary.failing.Store(true)
+
+	// the subscription fails over to the backup, whose websocket is started
+	_, err := p.Subscribe(context.Background(), "subscriber", "query")
+	require.NoError(t, err)
+	require.EqualValues(t, 1, primary.subscriptions.Load())
+	require.EqualValues(t, 1, backup.subscriptions.Load())
+	require.True(t, backup.IsRunning())
+
+	// the subscription is removed from the endpoint it was made on, even once the primary recovered
+	primary.failing.Store(false)
+	require.NoError(t, p.Unsubscribe(context.Background(), "subscriber", "query"))
+	require.Zero(t, primary.unsubscriptions.Load())
+	require.EqualValues(t, 1, backup.unsubscriptions.Load())
+	require.Error(t, p.Unsubscribe(context.Background(), "subscriber", "query"))
+}
+
+func TestRPCPoolClose(t *testing.T) {
+	primary, backup := &fakeRPCClient{}, &fakeRPCClient{}
+	p, _ := newTestRPCPool(t, time.Minute, primary, backup)
+	p.timeout = 0
+	_, err := p.Subscribe(context.Background(), "subscriber", "query")
+	require.NoError(t, err)
+	p.probes.Wait()
+	// the next health check of the backup stalls until the pool is closed
+	backup.stalled.Store(true)
+	p.endpoints[1].health.MeasuredAt = time.Time{}
+	_, err = p.ABCIQuery(context.Background(), "/path", nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, p.Close(ctx))
+	require.False(t, primary.IsRunning())
+	require.Zero(t, p.endpoints[1].health.Failures)
+
+	// no health check is started once the pool is closed
+	statuses := backup.statuses.Load()
+	_, err = p.ABCIQuery(context.Background(), "/path", nil)
+	require.NoError(t, err)
+	p.probes.Wait()
+	require.Equal(t, statuses, backup.statuses.Load())
+	_, err = p.Subscribe(context.Background(), "subscriber", "query")
+	require.ErrorIs(t, err, ErrRPCPoolClosed)
+}
+
+func TestNewRPCPoolNoEndpoint(t *testing.T) {
+	_, err := NewRPCPool(nil, DefaultBBNConfig(), zap.NewNop())
+	require.Error(t, err)
+}
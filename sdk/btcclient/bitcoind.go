@@ -0,0 +1,116 @@
+package btcclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/rpcclient"
+	"github.com/btcsuite/btcd/wire"
+	"go.uber.org/zap"
+)
+
+// bitcoindClient fetches BTC block headers from the bitcoind RPC, retrying failed calls
+type bitcoindClient struct {
+	client *rpcclient.Client
+	logger *zap.Logger
+	cfg    *BTCConfig
+}
+
+type BlockCountResponse struct {
+	count int64
+}
+
+func (c *bitcoindClient) GetBlockCount(ctx context.Context) (uint64, error) {
+	callForBlockCount := func() (*BlockCountResponse, error) {
+		count, err := receiveWithContext(ctx, c.client.GetBlockCountAsync().Receive)
+		if err != nil {
+			return nil, err
+		}
+
+		return &BlockCountResponse{count: count}, nil
+	}
+
+	blockCount, err := clientCallWithRetry(ctx, callForBlockCount, c.logger, c.cfg)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get block count: %w", err)
+	}
+
+	return uint64(blockCount.count), nil
+}
+
+func (c *bitcoindClient) GetBlockHashByHeight(ctx context.Context, height uint64) (*chainhash.Hash, error) {
+	callForBlockHash := func() (*chainhash.Hash, error) {
+		return receiveWithContext(ctx, c.client.GetBlockHashAsync(int64(height)).Receive)
+	}
+
+	blockHash, err := clientCallWithRetry(ctx, callForBlockHash, c.logger, c.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block by height %d: %w", height, err)
+	}
+
+	return blockHash, nil
+}
+
+func (c *bitcoindClient) GetBlockHeaderByHash(ctx context.Context, blockHash *chainhash.Hash) (*wire.BlockHeader, error) {
+	callForBlockHeader := func() (*wire.BlockHeader, error) {
+		return receiveWithContext(ctx, c.client.GetBlockHeaderAsync(blockHash).Receive)
+	}
+
+	header, err := clientCallWithRetry(ctx, callForBlockHeader, c.logger, c.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block header by hash %s: %w", blockHash.String(), err)
+	}
+
+	return header, nil
+}
+
+func clientCallWithRetry[T any](
+	ctx context.Context, call retry.RetryableFuncWithData[*T], logger *zap.Logger, cfg *BTCConfig,
+) (*T, error) {
+	result, err := retry.DoWithData(
+		call,
+		retry.Context(ctx),
+		retry.Attempts(cfg.MaxRetryTimes),
+		retry.Delay(cfg.RetryInterval),
+		retry.LastErrorOnly(true),
+		retry.OnRetry(func(n uint, err error) {
+			logger.Debug(
+				"failed to call the RPC client",
+				zap.Uint("attempt", n+1),
+				zap.Uint("max_attempts", cfg.MaxRetryTimes),
+				zap.Error(err),
+			)
+		}),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// receiveWithContext waits for the result of an async RPC call, or returns early
+// once the context is done. The rpcclient does not accept a context itself, so
+// the in-flight request is left to finish in the background.
+func receiveWithContext[T any](ctx context.Context, receive func() (T, error)) (T, error) {
+	type result struct {
+		value T
+		err   error
+	}
+
+	resultChan := make(chan result, 1)
+	go func() {
+		value, err := receive()
+		resultChan <- result{value: value, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	case res := <-resultChan:
+		return res.value, res.err
+	}
+}
@@ -1,96 +1,149 @@
 package btcclient
 
 import (
+	"context"
 	"fmt"
 
-	"github.com/avast/retry-go/v4"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/rpcclient"
 	"github.com/btcsuite/btcd/wire"
 	"go.uber.org/zap"
 )
 
+// HeaderSource fetches BTC block headers, e.g. from a Bitcoin node or from the BTC light client of Babylon
+type HeaderSource interface {
+	GetBlockCount(ctx context.Context) (uint64, error)
+	GetBlockHashByHeight(ctx context.Context, height uint64) (*chainhash.Hash, error)
+	GetBlockHeaderByHash(ctx context.Context, blockHash *chainhash.Hash) (*wire.BlockHeader, error)
+}
+
 type BTCClient struct {
-	client *rpcclient.Client
+	src    HeaderSource
 	logger *zap.Logger
 	cfg    *BTCConfig
+	// headerIndex resolves timestamps and heights locally, nil if disabled
+	headerIndex *headerIndex
 }
 
+// NewBTCClient creates a BTC client that fetches the BTC block headers from the bitcoind RPC of the config
+//
+// if BTCConfig.RPCHosts is set, the daemons are queried with failover instead, see multiSource
 func NewBTCClient(cfg *BTCConfig, logger *zap.Logger) (*BTCClient, error) {
-	c, err := rpcclient.New(cfg.ToConnConfig(), nil)
-	if err != nil {
-		return nil, err
+	if len(cfg.RPCHosts) == 0 {
+		return newBitcoindBTCClient(cfg, cfg.ToConnConfig(), logger)
 	}
 
-	return &BTCClient{
-		client: c,
-		logger: logger,
-		cfg:    cfg,
-	}, nil
-}
-
-type BlockCountResponse struct {
-	count int64
-}
-
-func (c *BTCClient) GetBlockCount() (uint64, error) {
-	callForBlockCount := func() (*BlockCountResponse, error) {
-		count, err := c.client.GetBlockCount()
+	// failing over to the next daemon replaces retrying the same daemon
+	endpointCfg := *cfg
+	endpointCfg.MaxRetryTimes = 1
+	srcs := make([]HeaderSource, len(cfg.RPCHosts))
+	for i, host := range cfg.RPCHosts {
+		c, err := rpcclient.New(cfg.toConnConfig(host), nil)
 		if err != nil {
 			return nil, err
 		}
-
-		return &BlockCountResponse{count: count}, nil
+		srcs[i] = &bitcoindClient{client: c, logger: logger, cfg: &endpointCfg}
 	}
-
-	blockCount, err := clientCallWithRetry(callForBlockCount, c.logger, c.cfg)
+	src, err := newMultiSource(cfg.RPCHosts, srcs, cfg.HashQuorum, cfg.EndpointTimeout, cfg.EndpointBackoff, logger)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get block count: %w", err)
+		return nil, err
 	}
+	return NewBTCClientFromSource(src, cfg, logger)
+}
 
-	return uint64(blockCount.count), nil
+func newBitcoindBTCClient(cfg *BTCConfig, connCfg *rpcclient.ConnConfig, logger *zap.Logger) (*BTCClient, error) {
+	c, err := rpcclient.New(connCfg, nil)
+	if err != nil {
+		return nil, err
+	}
+	return NewBTCClientFromSource(&bitcoindClient{client: c, logger: logger, cfg: cfg}, cfg, logger)
 }
 
-func (c *BTCClient) GetBlockHashByHeight(height uint64) (*chainhash.Hash, error) {
-	callForBlockHash := func() (*chainhash.Hash, error) {
-		return c.client.GetBlockHash(int64(height))
+// NewBTCClientFromSource creates a BTC client that fetches the BTC block headers from the given source. The
+// header index and the confirmation depth of the config apply to the source as well
+func NewBTCClientFromSource(src HeaderSource, cfg *BTCConfig, logger *zap.Logger) (*BTCClient, error) {
+	btcClient := &BTCClient{
+		src:    src,
+		logger: logger,
+		cfg:    cfg,
 	}
 
-	blockHash, err := clientCallWithRetry(callForBlockHash, c.logger, c.cfg)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get block by height %d: %w", height, err)
+	if cfg.HeaderIndexBackfill > 0 {
+		var err error
+		btcClient.headerIndex, err = newHeaderIndex(
+			src, cfg.HeaderIndexBackfill, cfg.ConfirmationDepth, cfg.HeaderIndexRetention,
+			cfg.HeaderIndexInterval, cfg.HeaderIndexPath, logger)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return blockHash, nil
+	return btcClient, nil
 }
 
-func (c *BTCClient) GetBlockHeaderByHash(blockHash *chainhash.Hash) (*wire.BlockHeader, error) {
-	callForBlockHeader := func() (*wire.BlockHeader, error) {
-		return c.client.GetBlockHeader(blockHash)
-	}
+func (c *BTCClient) GetBlockCount(ctx context.Context) (uint64, error) {
+	return c.src.GetBlockCount(ctx)
+}
 
-	header, err := clientCallWithRetry(callForBlockHeader, c.logger, c.cfg)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get block header by hash %s: %w", blockHash.String(), err)
-	}
+func (c *BTCClient) GetBlockHashByHeight(ctx context.Context, height uint64) (*chainhash.Hash, error) {
+	return c.src.GetBlockHashByHeight(ctx, height)
+}
+
+func (c *BTCClient) GetBlockHeaderByHash(ctx context.Context, blockHash *chainhash.Hash) (*wire.BlockHeader, error) {
+	return c.src.GetBlockHeaderByHash(ctx, blockHash)
+}
 
-	return header, nil
+// GetBlockHeightByTimestamp returns the height of the last BTC block with a timestamp not after the target
+// timestamp
+//
+//   - returns ErrTimestampAheadOfBtcTip if the target timestamp is after the tip, as the height is not known yet
+//   - returns ErrTimestampBeforeGenesis if the target timestamp is before the genesis block
+//
+// if BTCConfig.ConfirmationDepth is set, only the blocks with at least that many confirmations are considered,
+// so the returned height does not change when Bitcoin reorgs near the tip. A target timestamp after the last
+// confirmed block is treated as after the tip
+//
+// if the local header index is enabled, the timestamp is resolved from the index against the timestamp
+// envelope of the blocks, which keeps the mapping monotone in the target timestamp even though block timestamps
+// are not, see headerIndex.blockHeightByTimestamp. Otherwise, it binary searches the node's chain
+func (c *BTCClient) GetBlockHeightByTimestamp(ctx context.Context, targetTimestamp uint64) (uint64, error) {
+	if c.headerIndex != nil {
+		return c.headerIndex.blockHeightByTimestamp(ctx, targetTimestamp)
+	}
+	return searchBlockHeightByTimestamp(ctx, c.src, targetTimestamp, c.cfg.ConfirmationDepth)
 }
 
-func (c *BTCClient) GetBlockHeightByTimestamp(targetTimestamp uint64) (uint64, error) {
+// searchBlockHeightByTimestamp binary searches the blocks of the node's chain with at least the given number
+// of confirmations for the height of the timestamp
+func searchBlockHeightByTimestamp(
+	ctx context.Context,
+	src HeaderSource,
+	targetTimestamp uint64,
+	confirmations uint64,
+) (uint64, error) {
 	// get the height of the most-work fully-validated chain
-	blockHeight, err := c.GetBlockCount()
+	tipHeight, err := src.GetBlockCount(ctx)
 	if err != nil {
 		return 0, err
 	}
 
+	// the tip has 1 confirmation, so a block is confirmed if it is at most confirmations-1 blocks below the tip
+	blockHeight := tipHeight
+	if confirmations > 1 {
+		if tipHeight < confirmations-1 {
+			return 0, fmt.Errorf("%w: timestamp %d, no BTC block has %d confirmations",
+				ErrTimestampAheadOfBtcTip, targetTimestamp, confirmations)
+		}
+		blockHeight = tipHeight - (confirmations - 1)
+	}
+
 	lowerBound := uint64(0)
 	upperBound := blockHeight
 
 	for lowerBound <= upperBound {
 		midHeight := (lowerBound + upperBound) / 2
 
-		blockTimestamp, err := c.GetBlockTimestampByHeight(midHeight)
+		blockTimestamp, err := fetchBlockTimestamp(ctx, src, midHeight)
 		if err != nil {
 			return 0, err
 		}
@@ -98,6 +151,9 @@ func (c *BTCClient) GetBlockHeightByTimestamp(targetTimestamp uint64) (uint64, e
 		if blockTimestamp < targetTimestamp {
 			lowerBound = midHeight + 1
 		} else if blockTimestamp > targetTimestamp {
+			if midHeight == 0 {
+				break
+			}
 			upperBound = midHeight - 1
 		} else {
 			return midHeight, nil
@@ -107,48 +163,39 @@ func (c *BTCClient) GetBlockHeightByTimestamp(targetTimestamp uint64) (uint64, e
 	// timestamp is in the future (not in the most-work fully-validated chain)
 	// so we cannot determine the height from the timestamp
 	if lowerBound > blockHeight {
-		return 0, nil
+		return 0, fmt.Errorf("%w: timestamp %d, BTC block at height %d",
+			ErrTimestampAheadOfBtcTip, targetTimestamp, blockHeight)
+	}
+	if lowerBound == 0 {
+		return 0, fmt.Errorf("%w: timestamp %d", ErrTimestampBeforeGenesis, targetTimestamp)
 	}
 
 	return lowerBound - 1, nil
 }
 
-func (c *BTCClient) GetBlockTimestampByHeight(height uint64) (uint64, error) {
+func (c *BTCClient) GetBlockTimestampByHeight(ctx context.Context, height uint64) (uint64, error) {
+	if c.headerIndex != nil {
+		if header, ok := c.headerIndex.headerByHeight(height); ok {
+			return header.Timestamp, nil
+		}
+	}
+
+	return fetchBlockTimestamp(ctx, c.src, height)
+}
+
+// fetchBlockTimestamp fetches the timestamp of the block at the height from the node
+func fetchBlockTimestamp(ctx context.Context, src HeaderSource, height uint64) (uint64, error) {
 	// get block hash by height
-	blockHash, err := c.GetBlockHashByHeight(height)
+	blockHash, err := src.GetBlockHashByHeight(ctx, height)
 	if err != nil {
 		return 0, err
 	}
 
 	// get block header by hash. the header contains info such as the block time expressed in UNIX epoch time
-	blockHeader, err := c.GetBlockHeaderByHash(blockHash)
+	blockHeader, err := src.GetBlockHeaderByHash(ctx, blockHash)
 	if err != nil {
 		return 0, err
 	}
 
 	return uint64(blockHeader.Timestamp.Unix()), nil
 }
-
-func clientCallWithRetry[T any](
-	call retry.RetryableFuncWithData[*T], logger *zap.Logger, cfg *BTCConfig,
-) (*T, error) {
-	result, err := retry.DoWithData(
-		call,
-		retry.Attempts(cfg.MaxRetryTimes),
-		retry.Delay(cfg.RetryInterval),
-		retry.LastErrorOnly(true),
-		retry.OnRetry(func(n uint, err error) {
-			logger.Debug(
-				"failed to call the RPC client",
-				zap.Uint("attempt", n+1),
-				zap.Uint("max_attempts", cfg.MaxRetryTimes),
-				zap.Error(err),
-			)
-		}),
-	)
-
-	if err != nil {
-		return nil, err
-	}
-	return result, nil
-}
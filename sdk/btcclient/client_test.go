@@ -1,43 +1,187 @@
 package btcclient
 
 import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
-// TODO: 1) not rely on mainnet RPC; 2) add more tests for some other edge cases
+// newBitcoindStub serves the bitcoind JSON-RPC methods used by the BTC client from the chain
+func newBitcoindStub(t *testing.T, chain *fakeChain) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req btcjson.Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		result, err := handleBitcoindRequest(r.Context(), chain, &req)
+		var rpcErr *btcjson.RPCError
+		if err != nil {
+			rpcErr = btcjson.NewRPCError(btcjson.ErrRPCBlockNotFound, err.Error())
+		}
+		resp, err := btcjson.MarshalResponse(req.Jsonrpc, req.ID, result, rpcErr)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write(resp)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func handleBitcoindRequest(ctx context.Context, chain *fakeChain, req *btcjson.Request) (interface{}, error) {
+	switch req.Method {
+	case "getblockcount":
+		return chain.GetBlockCount(ctx)
+	case "getblockhash":
+		var height uint64
+		if err := json.Unmarshal(req.Params[0], &height); err != nil {
+			return nil, err
+		}
+		blockHash, err := chain.GetBlockHashByHeight(ctx, height)
+		if err != nil {
+			return nil, err
+		}
+		return blockHash.String(), nil
+	case "getblockheader":
+		var blockHashStr string
+		if err := json.Unmarshal(req.Params[0], &blockHashStr); err != nil {
+			return nil, err
+		}
+		blockHash, err := chainhash.NewHashFromStr(blockHashStr)
+		if err != nil {
+			return nil, err
+		}
+		header, err := chain.GetBlockHeaderByHash(ctx, blockHash)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := header.Serialize(&buf); err != nil {
+			return nil, err
+		}
+		return hex.EncodeToString(buf.Bytes()), nil
+	default:
+		return nil, fmt.Errorf("unsupported method %s", req.Method)
+	}
+}
+
+// newTestBTCClient returns a BTC client connected to a bitcoind stub serving the chain
+func newTestBTCClient(t *testing.T, chain *fakeChain, cfg *BTCConfig) *BTCClient {
+	server := newBitcoindStub(t, chain)
+	cfg.RPCHost = strings.TrimPrefix(server.URL, "http://")
+	cfg.MaxRetryTimes = 1
+	connCfg := cfg.ToConnConfig()
+	connCfg.DisableTLS = true
+
+	btc, err := newBitcoindBTCClient(cfg, connCfg, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(btc.src.(*bitcoindClient).client.Shutdown)
+	return btc
+}
+
 func TestBtcClient(t *testing.T) {
-	var blockHeight uint64
-	var err error
+	testCases := []struct {
+		name                string
+		headerIndexBackfill uint64
+	}{
+		{"remote search", 0},
+		{"header index", 100},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			chain := newFakeChain(1000, 1_700_000_000)
+			btcConfig := DefaultBTCConfig()
+			btcConfig.HeaderIndexBackfill = tc.headerIndexBackfill
+			// map timestamps onto the tip
+			btcConfig.ConfirmationDepth = 0
+			btc := newTestBTCClient(t, chain, btcConfig)
+			ctx := context.Background()
+
+			// timestamp between block 500 and 501
+			blockHeight, err := btc.GetBlockHeightByTimestamp(ctx, chain.timestamp(500)+300)
+			require.NoError(t, err)
+			require.Equal(t, uint64(500), blockHeight)
+
+			// the exact timestamp of block 500
+			blockHeight, err = btc.GetBlockHeightByTimestamp(ctx, chain.timestamp(500))
+			require.NoError(t, err)
+			require.Equal(t, uint64(500), blockHeight)
+
+			// the exact timestamp minus one of block 500
+			blockHeight, err = btc.GetBlockHeightByTimestamp(ctx, chain.timestamp(500)-1)
+			require.NoError(t, err)
+			require.Equal(t, uint64(499), blockHeight)
+
+			// the exact timestamp of the tip
+			blockHeight, err = btc.GetBlockHeightByTimestamp(ctx, chain.timestamp(999))
+			require.NoError(t, err)
+			require.Equal(t, uint64(999), blockHeight)
 
-	// Create logger.
+			// a timestamp after the tip
+			_, err = btc.GetBlockHeightByTimestamp(ctx, chain.timestamp(999)+1)
+			require.ErrorIs(t, err, ErrTimestampAheadOfBtcTip)
+
+			// a timestamp before the genesis block
+			_, err = btc.GetBlockHeightByTimestamp(ctx, chain.timestamp(0)-1)
+			require.ErrorIs(t, err, ErrTimestampBeforeGenesis)
+
+			blockTimestamp, err := btc.GetBlockTimestampByHeight(ctx, 500)
+			require.NoError(t, err)
+			require.Equal(t, chain.timestamp(500), blockTimestamp)
+		})
+	}
+}
+
+func TestBtcClientHonorsContextDeadline(t *testing.T) {
 	logger, err := zap.NewDevelopment()
 	require.Nil(t, err)
 
-	// Create BTC client
+	// nothing listens on this port, so every attempt fails and would be retried
 	btcConfig := DefaultBTCConfig()
+	btcConfig.RPCHost = "127.0.0.1:1"
+	btcConfig.RetryInterval = time.Second
 	btc, err := NewBTCClient(btcConfig, logger)
 	require.Nil(t, err)
 
-	// timestmap between block 848682 and 848683
-	blockHeight, err = btc.GetBlockHeightByTimestamp(uint64(1718840690))
-	require.Nil(t, err)
-	require.Equal(t, uint64(848682), blockHeight)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
 
-	// the exact timestamp of block 848682
-	blockHeight, err = btc.GetBlockHeightByTimestamp(uint64(1718839311))
-	require.Nil(t, err)
-	require.Equal(t, uint64(848682), blockHeight)
+	start := time.Now()
+	_, err = btc.GetBlockCount(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), btcConfig.RetryInterval)
+}
 
-	// the exact timestamp minus one of block 848682
-	blockHeight, err = btc.GetBlockHeightByTimestamp(uint64(1718839310))
-	require.Nil(t, err)
-	require.Equal(t, uint64(848681), blockHeight)
+func TestBtcClientFromSource(t *testing.T) {
+	chain := newFakeChain(1000, 1_700_000_000)
+	btcConfig := DefaultBTCConfig()
+	btcConfig.ConfirmationDepth = 6
+	btc, err := NewBTCClientFromSource(chain, btcConfig, zap.NewNop())
+	require.NoError(t, err)
 
-	// a timestamp in the future i.e. year 2056
-	blockHeight, err = btc.GetBlockHeightByTimestamp(uint64(2718840690))
-	require.Nil(t, err)
-	require.Equal(t, uint64(0), blockHeight)
+	// the header index and the confirmation depth apply to the source
+	blockHeight, err := btc.GetBlockHeightByTimestamp(context.Background(), chain.timestamp(994))
+	require.NoError(t, err)
+	require.Equal(t, uint64(994), blockHeight)
+	_, err = btc.GetBlockHeightByTimestamp(context.Background(), chain.timestamp(995))
+	require.ErrorIs(t, err, ErrTimestampAheadOfBtcTip)
+
+	blockCount, err := btc.GetBlockCount(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(999), blockCount)
 }
@@ -16,6 +16,12 @@ const (
 	defaultTxPollingInterval      = 30 * time.Second
 	defaultMaxRetryTimes          = 5
 	defaultRetryInterval          = 500 * time.Millisecond
+	defaultHeaderIndexBackfill    = 144   // ~1 day of BTC blocks
+	defaultHeaderIndexRetention   = 52560 // ~1 year of BTC blocks
+	defaultEndpointTimeout        = 10 * time.Second
+	defaultEndpointBackoff        = 5 * time.Second
+	defaultConfirmationDepth      = 6 // Bitcoin reorgs are almost never deeper
+	defaultHeaderIndexInterval    = time.Minute
 	// DefaultTxPollingJitter defines the default TxPollingIntervalJitter
 	// to be used for bitcoind backend.
 	DefaultTxPollingJitter = 0.5
@@ -23,15 +29,25 @@ const (
 
 // BTCConfig defines configuration for the Bitcoin client
 type BTCConfig struct {
-	RPCHost              string        `long:"rpchost" description:"The daemon's rpc listening address."`
-	RPCUser              string        `long:"rpcuser" description:"Username for RPC connections."`
-	RPCPass              string        `long:"rpcpass" default-mask:"-" description:"Password for RPC connections."`
-	PrunedNodeMaxPeers   int           `long:"pruned-node-max-peers" description:"The maximum number of peers staker will choose from the backend node to retrieve pruned blocks from. This only applies to pruned nodes."`
-	BlockPollingInterval time.Duration `long:"blockpollinginterval" description:"The interval that will be used to poll bitcoind for new blocks. Only used if rpcpolling is true."`
-	TxPollingInterval    time.Duration `long:"txpollinginterval" description:"The interval that will be used to poll bitcoind for new tx. Only used if rpcpolling is true."`
-	BlockCacheSize       uint64        `long:"block-cache-size" description:"Size of the Bitcoin blocks cache."`
-	MaxRetryTimes        uint          `long:"max-retry-times" description:"The max number of retries to an RPC call in case of failure."`
-	RetryInterval        time.Duration `long:"retry-interval" description:"The time interval between each retry."`
+	RPCHost              string        `mapstructure:"rpchost" long:"rpchost" description:"The daemon's rpc listening address."`
+	RPCHosts             []string      `mapstructure:"rpchosts" long:"rpchosts" description:"The rpc listening addresses of multiple daemons sharing the RPC credentials, queried in order with failover. Overrides rpchost if set."`
+	HashQuorum           int           `mapstructure:"hash-quorum" long:"hash-quorum" description:"The number of daemons in rpchosts that must agree on the hash of a block height. Values below 2 trust the first daemon that answers."`
+	EndpointTimeout      time.Duration `mapstructure:"endpoint-timeout" long:"endpoint-timeout" description:"The timeout of a call to one of the daemons in rpchosts, before failing over to the next one."`
+	EndpointBackoff      time.Duration `mapstructure:"endpoint-backoff" long:"endpoint-backoff" description:"The time a failing daemon in rpchosts is skipped for, doubled on each consecutive failure."`
+	RPCUser              string        `mapstructure:"rpcuser" long:"rpcuser" description:"Username for RPC connections."`
+	RPCPass              string        `mapstructure:"rpcpass" long:"rpcpass" default-mask:"-" description:"Password for RPC connections."`
+	PrunedNodeMaxPeers   int           `mapstructure:"pruned-node-max-peers" long:"pruned-node-max-peers" description:"The maximum number of peers staker will choose from the backend node to retrieve pruned blocks from. This only applies to pruned nodes."`
+	BlockPollingInterval time.Duration `mapstructure:"blockpollinginterval" long:"blockpollinginterval" description:"The interval that will be used to poll bitcoind for new blocks. Only used if rpcpolling is true."`
+	TxPollingInterval    time.Duration `mapstructure:"txpollinginterval" long:"txpollinginterval" description:"The interval that will be used to poll bitcoind for new tx. Only used if rpcpolling is true."`
+	BlockCacheSize       uint64        `mapstructure:"block-cache-size" long:"block-cache-size" description:"Size of the Bitcoin blocks cache."`
+	MaxRetryTimes        uint          `mapstructure:"max-retry-times" long:"max-retry-times" description:"The max number of retries to an RPC call in case of failure."`
+	RetryInterval        time.Duration `mapstructure:"retry-interval" long:"retry-interval" description:"The time interval between each retry."`
+	HeaderIndexBackfill  uint64        `mapstructure:"header-index-backfill" long:"header-index-backfill" description:"The number of blocks below the tip that the local BTC header index covers after its first sync. Earlier timestamps are resolved by querying the node. Set to 0 to disable the header index."`
+	HeaderIndexRetention uint64        `mapstructure:"header-index-retention" long:"header-index-retention" description:"The number of blocks below the tip that the local BTC header index keeps, at least header-index-backfill. Older headers are pruned, and their timestamps are resolved by querying the node. Set to 0 to keep all the headers."`
+	HeaderIndexPath      string        `mapstructure:"header-index-path" long:"header-index-path" description:"The file the local BTC header index is persisted to. Leave empty to keep the index in memory only."`
+	HeaderIndexInterval  time.Duration `mapstructure:"header-index-interval" long:"header-index-interval" description:"The max time the local BTC header index maps timestamps without checking its tip against the node, so that a Bitcoin reorg is detected even if no later timestamp is queried. Set to 0 to check on every query."`
+	EsploraURL           string        `mapstructure:"esplora-url" long:"esplora-url" description:"The base URL of the Esplora HTTP API, e.g. https://mempool.space/api. Only used by the esplora BTC backend."`
+	ConfirmationDepth    uint64        `mapstructure:"confirmation-depth" long:"confirmation-depth" description:"The number of confirmations a BTC block needs before timestamps are mapped onto it, so that the mapped heights do not change when Bitcoin reorgs near the tip. The tip has 1 confirmation. Set to 0 to map timestamps onto the tip."`
 }
 
 func DefaultBTCConfig() *BTCConfig {
@@ -44,13 +60,22 @@ func DefaultBTCConfig() *BTCConfig {
 		BlockCacheSize:       defaultBitcoindBlockCacheSize,
 		MaxRetryTimes:        defaultMaxRetryTimes,
 		RetryInterval:        defaultRetryInterval,
+		HeaderIndexBackfill:  defaultHeaderIndexBackfill,
+		HeaderIndexRetention: defaultHeaderIndexRetention,
+		EndpointTimeout:      defaultEndpointTimeout,
+		EndpointBackoff:      defaultEndpointBackoff,
+		ConfirmationDepth:    defaultConfirmationDepth,
+		HeaderIndexInterval:  defaultHeaderIndexInterval,
 	}
 }
 
-
 func (cfg *BTCConfig) ToConnConfig() *rpcclient.ConnConfig {
+	return cfg.toConnConfig(cfg.RPCHost)
+}
+
+func (cfg *BTCConfig) toConnConfig(host string) *rpcclient.ConnConfig {
 	return &rpcclient.ConnConfig{
-		Host:                 cfg.RPCHost,
+		Host:                 host,
 		User:                 cfg.RPCUser,
 		Pass:                 cfg.RPCPass,
 		DisableTLS:           false,
@@ -60,4 +85,4 @@ func (cfg *BTCConfig) ToConnConfig() *rpcclient.ConnConfig {
 		// we may need to re-consider it later if we need any notifications
 		HTTPPostMode: true,
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,12 @@
+package btcclient
+
+import "fmt"
+
+var (
+	// ErrTimestampAheadOfBtcTip means that no BTC block is known for the timestamp yet, the caller should wait
+	// for more BTC blocks
+	ErrTimestampAheadOfBtcTip = fmt.Errorf("timestamp is ahead of the BTC tip")
+	ErrTimestampBeforeGenesis = fmt.Errorf("timestamp is before the BTC genesis block")
+	// ErrNoHashQuorum means that not enough BTC endpoints agree on the hash of a block height
+	ErrNoHashQuorum = fmt.Errorf("not enough BTC endpoints agree on the block hash")
+)
@@ -0,0 +1,134 @@
+package btcclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"go.uber.org/zap"
+)
+
+const (
+	// esploraRequestTimeout bounds an Esplora request when the caller's context has no deadline
+	esploraRequestTimeout = 20 * time.Second
+	// maxEsploraResponseSize bounds the size of an Esplora response, the largest being a hex-encoded header
+	maxEsploraResponseSize = 4096
+)
+
+// esploraClient fetches BTC block headers from the Esplora HTTP API, e.g. mempool.space or blockstream.info,
+// retrying failed requests unless the Esplora server rejects them with a 4xx status
+type esploraClient struct {
+	baseURL    string
+	httpClient *http.Client
+	logger     *zap.Logger
+	cfg        *BTCConfig
+}
+
+// NewEsploraBTCClient creates a BTC client that fetches the BTC block headers from the Esplora HTTP API
+// at BTCConfig.EsploraURL
+func NewEsploraBTCClient(cfg *BTCConfig, logger *zap.Logger) (*BTCClient, error) {
+	if cfg.EsploraURL == "" {
+		return nil, fmt.Errorf("the Esplora URL is not set")
+	}
+	if _, err := url.ParseRequestURI(cfg.EsploraURL); err != nil {
+		return nil, fmt.Errorf("invalid Esplora URL %s: %w", cfg.EsploraURL, err)
+	}
+
+	src := &esploraClient{
+		baseURL:    strings.TrimSuffix(cfg.EsploraURL, "/"),
+		httpClient: &http.Client{Timeout: esploraRequestTimeout},
+		logger:     logger,
+		cfg:        cfg,
+	}
+	return NewBTCClientFromSource(src, cfg, logger)
+}
+
+func (c *esploraClient) GetBlockCount(ctx context.Context) (uint64, error) {
+	body, err := c.get(ctx, "/blocks/tip/height")
+	if err != nil {
+		return 0, fmt.Errorf("failed to get block count: %w", err)
+	}
+	blockCount, err := strconv.ParseUint(body, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get block count: invalid height %q: %w", body, err)
+	}
+	return blockCount, nil
+}
+
+func (c *esploraClient) GetBlockHashByHeight(ctx context.Context, height uint64) (*chainhash.Hash, error) {
+	body, err := c.get(ctx, fmt.Sprintf("/block-height/%d", height))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block by height %d: %w", height, err)
+	}
+	blockHash, err := chainhash.NewHashFromStr(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block by height %d: invalid hash %q: %w", height, body, err)
+	}
+	return blockHash, nil
+}
+
+func (c *esploraClient) GetBlockHeaderByHash(ctx context.Context, blockHash *chainhash.Hash) (*wire.BlockHeader, error) {
+	body, err := c.get(ctx, fmt.Sprintf("/block/%s/header", blockHash.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block header by hash %s: %w", blockHash.String(), err)
+	}
+	headerBytes, err := hex.DecodeString(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block header by hash %s: %w", blockHash.String(), err)
+	}
+	header := &wire.BlockHeader{}
+	if err := header.Deserialize(bytes.NewReader(headerBytes)); err != nil {
+		return nil, fmt.Errorf("failed to get block header by hash %s: %w", blockHash.String(), err)
+	}
+	if header.BlockHash() != *blockHash {
+		return nil, fmt.Errorf("failed to get block header by hash %s: the header has hash %s",
+			blockHash.String(), header.BlockHash().String())
+	}
+	return header, nil
+}
+
+// get requests the Esplora endpoint at the path, and returns the response body with surrounding whitespace trimmed
+func (c *esploraClient) get(ctx context.Context, path string) (string, error) {
+	callForBody := func() (*string, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+		if err != nil {
+			return nil, retry.Unrecoverable(err)
+		}
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxEsploraResponseSize))
+		if err != nil {
+			return nil, err
+		}
+		bodyStr := strings.TrimSpace(string(body))
+		if resp.StatusCode != http.StatusOK {
+			err := fmt.Errorf("esplora returned status %d: %s", resp.StatusCode, bodyStr)
+			// the request itself is rejected, e.g. the block is not found, so retrying does not help
+			if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+				return nil, retry.Unrecoverable(err)
+			}
+			return nil, err
+		}
+		return &bodyStr, nil
+	}
+
+	body, err := clientCallWithRetry(ctx, callForBody, c.logger, c.cfg)
+	if err != nil {
+		return "", err
+	}
+	return *body, nil
+}
@@ -0,0 +1,153 @@
+package btcclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// esploraStub serves the Esplora HTTP API endpoints used by the BTC client from the chain
+//
+// the first failures requests fail with a 503 status
+type esploraStub struct {
+	chain    *fakeChain
+	failures atomic.Int64
+	requests atomic.Int64
+}
+
+func (s *esploraStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.requests.Add(1)
+	if s.failures.Add(-1) >= 0 {
+		http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/api")
+	switch {
+	case path == "/blocks/tip/height":
+		fmt.Fprintf(w, "%d", len(s.chain.headers)-1)
+	case strings.HasPrefix(path, "/block-height/"):
+		height, err := strconv.ParseUint(strings.TrimPrefix(path, "/block-height/"), 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid height", http.StatusBadRequest)
+			return
+		}
+		blockHash, err := s.chain.GetBlockHashByHeight(r.Context(), height)
+		if err != nil {
+			http.Error(w, "Block not found", http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, blockHash.String())
+	case strings.HasPrefix(path, "/block/") && strings.HasSuffix(path, "/header"):
+		blockHash, err := chainhash.NewHashFromStr(strings.TrimSuffix(strings.TrimPrefix(path, "/block/"), "/header"))
+		if err != nil {
+			http.Error(w, "Invalid hex string", http.StatusBadRequest)
+			return
+		}
+		header, err := s.chain.GetBlockHeaderByHash(r.Context(), blockHash)
+		if err != nil {
+			http.Error(w, "Block not found", http.StatusNotFound)
+			return
+		}
+		var buf bytes.Buffer
+		if err := header.Serialize(&buf); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, hex.EncodeToString(buf.Bytes()))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// newTestEsploraClient returns a BTC client connected to an Esplora stub serving the chain
+func newTestEsploraClient(t *testing.T, stub *esploraStub, cfg *BTCConfig) *BTCClient {
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+	cfg.EsploraURL = server.URL + "/api/"
+	cfg.RetryInterval = time.Millisecond
+
+	btc, err := NewEsploraBTCClient(cfg, zap.NewNop())
+	require.NoError(t, err)
+	return btc
+}
+
+func TestEsploraClient(t *testing.T) {
+	testCases := []struct {
+		name                string
+		headerIndexBackfill uint64
+	}{
+		{"remote search", 0},
+		{"header index", 100},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stub := &esploraStub{chain: newFakeChain(1000, 1_700_000_000)}
+			btcConfig := DefaultBTCConfig()
+			btcConfig.HeaderIndexBackfill = tc.headerIndexBackfill
+			btc := newTestEsploraClient(t, stub, btcConfig)
+			ctx := context.Background()
+
+			blockCount, err := btc.GetBlockCount(ctx)
+			require.NoError(t, err)
+			require.Equal(t, uint64(999), blockCount)
+
+			blockHash, err := btc.GetBlockHashByHeight(ctx, 500)
+			require.NoError(t, err)
+			require.Equal(t, stub.chain.headers[500].BlockHash(), *blockHash)
+
+			header, err := btc.GetBlockHeaderByHash(ctx, blockHash)
+			require.NoError(t, err)
+			require.Equal(t, stub.chain.headers[500].Timestamp, header.Timestamp)
+
+			blockHeight, err := btc.GetBlockHeightByTimestamp(ctx, stub.chain.timestamp(500)+300)
+			require.NoError(t, err)
+			require.Equal(t, uint64(500), blockHeight)
+
+			_, err = btc.GetBlockHeightByTimestamp(ctx, stub.chain.timestamp(999)+1)
+			require.ErrorIs(t, err, ErrTimestampAheadOfBtcTip)
+		})
+	}
+}
+
+func TestEsploraClientRetries(t *testing.T) {
+	stub := &esploraStub{chain: newFakeChain(10, 1_700_000_000)}
+	btcConfig := DefaultBTCConfig()
+	btcConfig.MaxRetryTimes = 3
+	btc := newTestEsploraClient(t, stub, btcConfig)
+
+	// server errors are retried
+	stub.failures.Store(2)
+	blockCount, err := btc.GetBlockCount(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(9), blockCount)
+	require.Equal(t, int64(3), stub.requests.Load())
+
+	// a block that is not found is not retried
+	stub.requests.Store(0)
+	_, err = btc.GetBlockHashByHeight(context.Background(), 10)
+	require.ErrorContains(t, err, "404")
+	require.Equal(t, int64(1), stub.requests.Load())
+}
+
+func TestNewEsploraBTCClientInvalidURL(t *testing.T) {
+	btcConfig := DefaultBTCConfig()
+	_, err := NewEsploraBTCClient(btcConfig, zap.NewNop())
+	require.Error(t, err)
+
+	btcConfig.EsploraURL = "mempool.space/api"
+	_, err = NewEsploraBTCClient(btcConfig, zap.NewNop())
+	require.Error(t, err)
+}
@@ -0,0 +1,628 @@
+package btcclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"go.uber.org/zap"
+)
+
+const (
+	// medianTimeBlocks is the number of previous blocks, including the block itself, whose timestamps
+	// the median-time-past of a block is computed from
+	medianTimeBlocks = 11
+	// maxIndexExtension is the max number of headers fetched to extend the index backwards to cover an
+	// earlier timestamp. Earlier timestamps are resolved by a binary search over the node's chain instead
+	maxIndexExtension = 2016 // ~2 weeks of BTC blocks
+	// pruneBatch is the number of headers the index holds beyond its retention before they are pruned, so that
+	// the index file is only rewritten once per pruneBatch blocks
+	pruneBatch = 2016 // ~2 weeks of BTC blocks
+	// maxReorgDepth is the max number of indexed headers dropped while looking for the common ancestor
+	// with the node's chain, before the index is rebuilt from scratch
+	maxReorgDepth = 100
+	// indexedHeaderSize is the size of an indexed header in the index file
+	indexedHeaderSize = 8 + chainhash.HashSize + 8 + 8
+)
+
+// indexedHeader is the part of a BTC block header kept in the header index
+type indexedHeader struct {
+	Height         uint64
+	Hash           chainhash.Hash
+	Timestamp      uint64
+	MedianTimePast uint64
+}
+
+// headerIndex is a local index of a contiguous range of BTC block headers ending at the node's tip
+//
+//   - on the first sync, the headers from backfill blocks below the tip are fetched. The medianTimeBlocks-1
+//     headers before them are fetched as well, so that the median-time-past of every covered header is exact
+//   - later syncs only fetch the headers above the last indexed header
+//   - if the last indexed header is no longer in the node's chain, the indexed headers are dropped one by
+//     one until the common ancestor is found
+//   - timestamps before the first indexed header are covered by extending the index backwards, see
+//     blockHeightByTimestamp
+//   - timestamps are only mapped onto the headers with at least confirmations confirmations, so that the
+//     mapped heights do not change when the node's chain is reorged near the tip
+//   - the last indexed header is checked against the node's chain at least every interval, so that a deeper
+//     reorg is detected even if no later timestamp is queried
+//   - if retention is not 0, the headers more than retention blocks below the last indexed header are pruned
+//   - if path is not empty, the index is persisted to path after each sync and loaded back on creation. The new
+//     headers are appended to the file, which is only rewritten when its first headers change, e.g. on pruning
+type headerIndex struct {
+	mu      sync.RWMutex
+	headers []indexedHeader // headers[i] is the header at height headers[0].Height + i
+	// envelopes[i] is the max timestamp of headers[0..i], see heightByTimestamp
+	envelopes []uint64
+	// lastSync is the start time of the last completed sync
+	lastSync time.Time
+
+	syncMu        sync.Mutex
+	src           HeaderSource
+	backfill      uint64
+	confirmations uint64
+	retention     uint64
+	interval      time.Duration
+	path          string
+	logger        *zap.Logger
+	// persisted are the headers in the file at path, nil if the file is to be rewritten
+	persisted []indexedHeader
+	now       func() time.Time
+}
+
+func newHeaderIndex(
+	src HeaderSource,
+	backfill uint64,
+	confirmations uint64,
+	retention uint64,
+	interval time.Duration,
+	path string,
+	logger *zap.Logger,
+) (*headerIndex, error) {
+	// the backfill window is never pruned
+	if retention > 0 && retention < backfill {
+		retention = backfill
+	}
+	idx := &headerIndex{
+		src:           src,
+		backfill:      backfill,
+		confirmations: confirmations,
+		retention:     retention,
+		interval:      interval,
+		path:          path,
+		logger:        logger,
+		now:           time.Now,
+	}
+	if path == "" {
+		return idx, nil
+	}
+
+	headers, err := loadIndexedHeaders(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the BTC header index from %s: %w", path, err)
+	}
+	idx.headers = headers
+	idx.envelopes = timestampEnvelopes(headers)
+	idx.persisted = headers
+	return idx, nil
+}
+
+// sync fetches the headers above the last indexed header up to the node's tip
+func (idx *headerIndex) sync(ctx context.Context) error {
+	idx.syncMu.Lock()
+	defer idx.syncMu.Unlock()
+
+	syncStart := idx.now()
+	tipHeight, err := idx.src.GetBlockCount(ctx)
+	if err != nil {
+		return err
+	}
+
+	// headers are only appended to or dropped from this copy, and published once the sync is done
+	idx.mu.RLock()
+	headers := idx.headers
+	idx.mu.RUnlock()
+	changed := false
+
+	// drop the indexed headers that are no longer in the node's chain
+	reorgDepth := 0
+	for len(headers) > 0 {
+		last := headers[len(headers)-1]
+		if last.Height <= tipHeight {
+			blockHash, err := idx.src.GetBlockHashByHeight(ctx, last.Height)
+			if err != nil {
+				return idx.publish(headers, changed, err)
+			}
+			if *blockHash == last.Hash {
+				break
+			}
+		}
+		headers = idx.dropLast(headers, &reorgDepth)
+		changed = true
+	}
+
+	for {
+		nextHeight := idx.initialHeight(tipHeight)
+		if len(headers) > 0 {
+			nextHeight = headers[len(headers)-1].Height + 1
+		}
+		if nextHeight > tipHeight {
+			break
+		}
+
+		blockHash, err := idx.src.GetBlockHashByHeight(ctx, nextHeight)
+		if err != nil {
+			return idx.publish(headers, changed, err)
+		}
+		header, err := idx.src.GetBlockHeaderByHash(ctx, blockHash)
+		if err != nil {
+			return idx.publish(headers, changed, err)
+		}
+
+		// the node's chain was reorged during the sync, drop the last indexed header and retry
+		if len(headers) > 0 && header.PrevBlock != headers[len(headers)-1].Hash {
+			headers = idx.dropLast(headers, &reorgDepth)
+			changed = true
+			continue
+		}
+
+		headers = append(headers, newIndexedHeader(headers, nextHeight, blockHash, header))
+		changed = true
+	}
+
+	if reorgDepth > 0 {
+		idx.logReorg(reorgDepth)
+	}
+	if pruned, ok := idx.prune(headers); ok {
+		headers = pruned
+		changed = true
+	}
+	idx.mu.Lock()
+	idx.lastSync = syncStart
+	idx.mu.Unlock()
+	return idx.publish(headers, changed, nil)
+}
+
+// stale returns true if the last indexed header was not checked against the node's chain for idx.interval
+func (idx *headerIndex) stale() bool {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.now().Sub(idx.lastSync) >= idx.interval
+}
+
+// prune drops the headers more than idx.retention blocks below the last header, keeping the headers needed for
+// their median-time-past, once more than pruneBatch headers can be dropped. Returns false if nothing is dropped
+func (idx *headerIndex) prune(headers []indexedHeader) ([]indexedHeader, bool) {
+	if idx.retention == 0 || len(headers) == 0 {
+		return headers, false
+	}
+	lastHeight := headers[len(headers)-1].Height
+	if lastHeight < idx.retention+medianTimeBlocks-1 {
+		return headers, false
+	}
+	firstHeight := lastHeight - idx.retention - (medianTimeBlocks - 1)
+	if firstHeight < headers[0].Height+pruneBatch {
+		return headers, false
+	}
+	// the kept headers are copied, so that the pruned ones are released
+	return append([]indexedHeader(nil), headers[firstHeight-headers[0].Height:]...), true
+}
+
+// logReorg logs the headers dropped from the index because they were reorged out of the node's chain
+func (idx *headerIndex) logReorg(reorgDepth int) {
+	// the dropped headers were not confirmed, so no timestamp was mapped onto them
+	if uint64(reorgDepth) < idx.confirmations {
+		idx.logger.Info("dropped BTC headers reorged out of the node's chain", zap.Int("depth", reorgDepth))
+		return
+	}
+	idx.logger.Warn("dropped confirmed BTC headers reorged out of the node's chain, "+
+		"the heights previously mapped onto them may have changed",
+		zap.Int("depth", reorgDepth),
+		zap.Uint64("confirmations", idx.confirmations))
+}
+
+// blockHeightByTimestamp returns the height of the last confirmed BTC block whose timestamp envelope is not
+// after the target timestamp, or ErrTimestampAheadOfBtcTip if the target timestamp is after the last confirmed
+// block
+//
+//   - the index is synced with the node only if the target timestamp is after the last confirmed block, or if
+//     the index is stale
+//   - if the target timestamp is before the first covered block, the index is extended backwards to cover it,
+//     unless more than maxIndexExtension headers would have to be fetched. In that case, the height is resolved
+//     by a binary search over the raw timestamps of the node's chain, which is only monotone if the timestamps
+//     in the searched range are
+//   - returns ErrTimestampBeforeGenesis if the target timestamp is before the genesis block
+func (idx *headerIndex) blockHeightByTimestamp(ctx context.Context, targetTimestamp uint64) (uint64, error) {
+	if _, tipEnvelope, ok := idx.confirmedTip(); !ok || targetTimestamp > tipEnvelope || idx.stale() {
+		if err := idx.sync(ctx); err != nil {
+			return 0, err
+		}
+	}
+
+	// timestamp is in the future (not in the most-work fully-validated chain, or not confirmed yet)
+	// so we cannot determine the height from the timestamp
+	tip, tipEnvelope, ok := idx.confirmedTip()
+	if !ok {
+		return 0, fmt.Errorf("%w: timestamp %d, no BTC block has %d confirmations",
+			ErrTimestampAheadOfBtcTip, targetTimestamp, idx.confirmations)
+	}
+	if targetTimestamp > tipEnvelope {
+		return 0, fmt.Errorf("%w: timestamp %d, BTC block at height %d",
+			ErrTimestampAheadOfBtcTip, targetTimestamp, tip.Height)
+	}
+
+	if height, ok := idx.heightByTimestamp(targetTimestamp); ok {
+		return height, nil
+	}
+
+	// find where the target timestamp is in the node's chain, and extend the index to cover it
+	searchedHeight, err := searchBlockHeightByTimestamp(ctx, idx.src, targetTimestamp, idx.confirmations)
+	if err != nil {
+		return 0, err
+	}
+	headers, _ := idx.coveredHeaders()
+	if len(headers) == 0 || searchedHeight >= headers[0].Height {
+		return searchedHeight, nil
+	}
+	firstHeight := headers[0].Height
+	// the blocks before the searched height may have a larger timestamp than the target timestamp, so
+	// the index is extended further back, doubling the extension until the target timestamp is covered
+	extension := firstHeight - searchedHeight + medianTimeBlocks
+	for extension <= maxIndexExtension {
+		startHeight := uint64(0)
+		if firstHeight > extension {
+			startHeight = firstHeight - extension
+		}
+		if err := idx.extendBack(ctx, startHeight); err != nil {
+			return 0, err
+		}
+		if height, ok := idx.heightByTimestamp(targetTimestamp); ok {
+			return height, nil
+		}
+		// the index covers the genesis block, so the target timestamp is before it
+		if startHeight == 0 {
+			return 0, fmt.Errorf("%w: timestamp %d", ErrTimestampBeforeGenesis, targetTimestamp)
+		}
+		extension *= 2
+	}
+	return searchedHeight, nil
+}
+
+// extendBack fetches the headers before the first indexed header, so that the index covers the start height
+func (idx *headerIndex) extendBack(ctx context.Context, startHeight uint64) error {
+	idx.syncMu.Lock()
+	defer idx.syncMu.Unlock()
+
+	idx.mu.RLock()
+	headers := idx.headers
+	idx.mu.RUnlock()
+
+	// fetch the headers needed to compute the median-time-past of the start height
+	fromHeight := uint64(0)
+	if startHeight > medianTimeBlocks-1 {
+		fromHeight = startHeight - (medianTimeBlocks - 1)
+	}
+	if len(headers) == 0 || fromHeight >= headers[0].Height {
+		return nil
+	}
+
+	extended := make([]indexedHeader, 0, int(headers[0].Height-fromHeight)+len(headers))
+	for height := fromHeight; height < headers[0].Height; height++ {
+		blockHash, err := idx.src.GetBlockHashByHeight(ctx, height)
+		if err != nil {
+			return err
+		}
+		header, err := idx.src.GetBlockHeaderByHash(ctx, blockHash)
+		if err != nil {
+			return err
+		}
+		extended = append(extended, newIndexedHeader(extended, height, blockHash, header))
+	}
+	// the node's chain was reorged below the first indexed header, the next sync will rebuild the index
+	firstHeader, err := idx.src.GetBlockHeaderByHash(ctx, &headers[0].Hash)
+	if err != nil {
+		return err
+	}
+	if firstHeader.PrevBlock != extended[len(extended)-1].Hash {
+		return fmt.Errorf("the BTC header at height %d is no longer in the node's chain", headers[0].Height)
+	}
+
+	// the median-time-past of the first indexed headers is now exact
+	numFetched := len(extended)
+	extended = append(extended, headers...)
+	for i := numFetched; i < numFetched+medianTimeBlocks-1 && i < len(extended); i++ {
+		extended[i].MedianTimePast = medianTimePast(extended[:i+1])
+	}
+
+	return idx.publish(extended, true, nil)
+}
+
+// dropLast drops the last indexed header, or all the indexed headers once more than maxReorgDepth headers
+// were dropped without finding the common ancestor with the node's chain
+func (idx *headerIndex) dropLast(headers []indexedHeader, reorgDepth *int) []indexedHeader {
+	*reorgDepth++
+	if *reorgDepth > maxReorgDepth {
+		idx.logger.Warn("no common ancestor with the node's chain, rebuilding the BTC header index",
+			zap.Int("max_reorg_depth", maxReorgDepth))
+		*reorgDepth = 0
+		return nil
+	}
+	// the capacity is capped so that appending does not overwrite headers visible to lookups
+	n := len(headers) - 1
+	return headers[:n:n]
+}
+
+// initialHeight returns the height the first sync starts indexing from
+func (idx *headerIndex) initialHeight(tipHeight uint64) uint64 {
+	startHeight := uint64(0)
+	if tipHeight > idx.backfill {
+		startHeight = tipHeight - idx.backfill
+	}
+	// fetch the headers needed to compute the median-time-past of the start height
+	if startHeight > medianTimeBlocks-1 {
+		return startHeight - (medianTimeBlocks - 1)
+	}
+	return 0
+}
+
+// publish makes the synced headers visible to lookups and persists them, even if the sync failed midway,
+// so that the headers fetched so far are not fetched again
+func (idx *headerIndex) publish(headers []indexedHeader, changed bool, syncErr error) error {
+	if !changed {
+		return syncErr
+	}
+
+	envelopes := timestampEnvelopes(headers)
+	idx.mu.Lock()
+	idx.headers = headers
+	idx.envelopes = envelopes
+	idx.mu.Unlock()
+
+	if idx.path != "" {
+		if err := idx.persist(headers); err != nil {
+			// the file is rewritten by the next sync
+			idx.persisted = nil
+			return errors.Join(syncErr, fmt.Errorf("failed to save the BTC header index to %s: %w", idx.path, err))
+		}
+		idx.persisted = headers
+	}
+	return syncErr
+}
+
+// persist writes the headers to the file. If the file holds the first headers already, only the headers after
+// them are written, otherwise the file is replaced
+func (idx *headerIndex) persist(headers []indexedHeader) error {
+	persisted := idx.persisted
+	if len(persisted) == 0 || len(headers) == 0 || persisted[0].Height != headers[0].Height {
+		return saveIndexedHeaders(idx.path, headers)
+	}
+	// the headers are only dropped from or appended to the end of the persisted ones, so the common headers
+	// are found from the end
+	n := len(persisted)
+	if len(headers) < n {
+		n = len(headers)
+	}
+	for n > 0 && persisted[n-1].Hash != headers[n-1].Hash {
+		n--
+	}
+	if n == 0 {
+		return saveIndexedHeaders(idx.path, headers)
+	}
+	return appendIndexedHeaders(idx.path, n, headers[n:])
+}
+
+// firstCovered returns the index of the first header with an exact median-time-past
+func firstCovered(headers []indexedHeader) int {
+	if len(headers) == 0 || headers[0].Height == 0 {
+		return 0
+	}
+	if len(headers) < medianTimeBlocks-1 {
+		return len(headers)
+	}
+	return medianTimeBlocks - 1
+}
+
+// coveredHeaders returns the indexed headers with an exact median-time-past, and their timestamp envelopes
+func (idx *headerIndex) coveredHeaders() ([]indexedHeader, []uint64) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	first := firstCovered(idx.headers)
+	return idx.headers[first:], idx.envelopes[first:]
+}
+
+// tip returns the last indexed header and its timestamp envelope, and false if the index is empty
+func (idx *headerIndex) tip() (indexedHeader, uint64, bool) {
+	headers, envelopes := idx.coveredHeaders()
+	if len(headers) == 0 {
+		return indexedHeader{}, 0, false
+	}
+	return headers[len(headers)-1], envelopes[len(envelopes)-1], true
+}
+
+// confirmedTip returns the last indexed header with at least idx.confirmations confirmations and its
+// timestamp envelope, and false if there is no such header
+func (idx *headerIndex) confirmedTip() (indexedHeader, uint64, bool) {
+	headers, envelopes := idx.confirmedHeaders()
+	if len(headers) == 0 {
+		return indexedHeader{}, 0, false
+	}
+	return headers[len(headers)-1], envelopes[len(envelopes)-1], true
+}
+
+// confirmedHeaders returns the covered headers with at least idx.confirmations confirmations, and their
+// timestamp envelopes. The last indexed header has 1 confirmation
+func (idx *headerIndex) confirmedHeaders() ([]indexedHeader, []uint64) {
+	headers, envelopes := idx.coveredHeaders()
+	if idx.confirmations <= 1 {
+		return headers, envelopes
+	}
+	unconfirmed := idx.confirmations - 1
+	if uint64(len(headers)) <= unconfirmed {
+		return nil, nil
+	}
+	n := len(headers) - int(unconfirmed)
+	return headers[:n], envelopes[:n]
+}
+
+// headerByHeight returns the indexed header at the height, and false if the height is not covered
+func (idx *headerIndex) headerByHeight(height uint64) (indexedHeader, bool) {
+	headers, _ := idx.coveredHeaders()
+	if len(headers) == 0 || height < headers[0].Height || height > headers[len(headers)-1].Height {
+		return indexedHeader{}, false
+	}
+	return headers[height-headers[0].Height], true
+}
+
+// heightByTimestamp returns the height of the last confirmed block whose timestamp envelope is not after the
+// target timestamp, and false if the target timestamp is before the envelope of the first covered block,
+// so the index cannot tell whether an earlier block matches
+//
+// Bitcoin block timestamps are not monotone: a block may be timestamped before its parent, as long as it is
+// after the median-time-past of its parent. Searching the raw timestamps may therefore map a later L2
+// timestamp to a lower BTC height. Instead, the timestamp envelope of a block is the max timestamp of the
+// block and all the indexed blocks before it. The envelope is monotone, so the mapping is monotone in the
+// target timestamp, and equal to the raw timestamp search whenever the timestamps are monotone.
+//
+// Note: the envelope only considers the indexed blocks. If a block before the index is timestamped after
+// some indexed blocks, extending the index backwards raises their envelope. As a block timestamp cannot be
+// more than 2 hours after the network time, this only affects the blocks within a few hours of the first
+// indexed block
+func (idx *headerIndex) heightByTimestamp(targetTimestamp uint64) (uint64, bool) {
+	headers, envelopes := idx.confirmedHeaders()
+	if len(headers) == 0 || targetTimestamp < envelopes[0] {
+		return 0, false
+	}
+	i := sort.Search(len(envelopes), func(i int) bool {
+		return envelopes[i] > targetTimestamp
+	})
+	return headers[i-1].Height, true
+}
+
+// timestampEnvelopes returns the running max of the header timestamps
+func timestampEnvelopes(headers []indexedHeader) []uint64 {
+	envelopes := make([]uint64, len(headers))
+	for i, header := range headers {
+		envelopes[i] = header.Timestamp
+		if i > 0 && envelopes[i-1] > envelopes[i] {
+			envelopes[i] = envelopes[i-1]
+		}
+	}
+	return envelopes
+}
+
+// newIndexedHeader returns the indexed header of the block at the height, appended to the given headers
+func newIndexedHeader(
+	headers []indexedHeader,
+	height uint64,
+	blockHash *chainhash.Hash,
+	header *wire.BlockHeader,
+) indexedHeader {
+	indexed := indexedHeader{
+		Height:    height,
+		Hash:      *blockHash,
+		Timestamp: uint64(header.Timestamp.Unix()),
+	}
+	indexed.MedianTimePast = medianTimePast(append(headers[:len(headers):len(headers)], indexed))
+	return indexed
+}
+
+// medianTimePast returns the median timestamp of the last header and the medianTimeBlocks-1 headers before it
+func medianTimePast(headers []indexedHeader) uint64 {
+	timestamps := make([]uint64, 0, medianTimeBlocks)
+	for i := len(headers) - 1; i >= 0 && len(timestamps) < medianTimeBlocks; i-- {
+		timestamps = append(timestamps, headers[i].Timestamp)
+	}
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i] < timestamps[j] })
+	return timestamps[len(timestamps)/2]
+}
+
+// loadIndexedHeaders reads the indexed headers from the file, returning no headers if the file does not exist
+func loadIndexedHeaders(path string) ([]indexedHeader, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// a partially appended header is dropped, and overwritten by the next append
+	data = data[:len(data)-len(data)%indexedHeaderSize]
+
+	headers := make([]indexedHeader, 0, len(data)/indexedHeaderSize)
+	r := bytes.NewReader(data)
+	for r.Len() > 0 {
+		var header indexedHeader
+		if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+			return nil, err
+		}
+		if len(headers) > 0 && header.Height != headers[len(headers)-1].Height+1 {
+			return nil, fmt.Errorf("non-contiguous height %d after %d", header.Height, headers[len(headers)-1].Height)
+		}
+		headers = append(headers, header)
+	}
+	return headers, nil
+}
+
+// saveIndexedHeaders atomically replaces the file with the indexed headers
+func saveIndexedHeaders(path string, headers []indexedHeader) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if err := writeIndexedHeaders(tmpFile, headers); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), path)
+}
+
+// appendIndexedHeaders truncates the file to its first n indexed headers, and appends the headers after them
+func appendIndexedHeaders(path string, n int, headers []indexedHeader) error {
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	offset := int64(n) * indexedHeaderSize
+	if err := file.Truncate(offset); err != nil {
+		file.Close()
+		return err
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return err
+	}
+	if err := writeIndexedHeaders(file, headers); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func writeIndexedHeaders(w io.Writer, headers []indexedHeader) error {
+	buf := bytes.NewBuffer(make([]byte, 0, len(headers)*indexedHeaderSize))
+	for _, header := range headers {
+		if err := binary.Write(buf, binary.BigEndian, &header); err != nil {
+			return err
+		}
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
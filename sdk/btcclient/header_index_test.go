@@ -0,0 +1,564 @@
+package btcclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"pgregory.net/rapid"
+)
+
+// fakeChain is an in-memory Bitcoin client serving the block headers of a chain, counting the header queries
+type fakeChain struct {
+	headers []*wire.BlockHeader
+	// heights maps the block hashes to their heights
+	heights map[chainhash.Hash]uint64
+
+	headerQueries atomic.Int64
+}
+
+// newFakeChainFromTimestamps returns a chain with the given block timestamps
+func newFakeChainFromTimestamps(timestamps []uint64) *fakeChain {
+	chain := &fakeChain{}
+	for _, timestamp := range timestamps {
+		header := &wire.BlockHeader{Timestamp: time.Unix(int64(timestamp), 0)}
+		if len(chain.headers) > 0 {
+			header.PrevBlock = chain.headers[len(chain.headers)-1].BlockHash()
+		}
+		chain.push(header)
+	}
+	return chain
+}
+
+// newFakeChain returns a chain of numBlocks blocks mined every 10 minutes from the timestamp
+func newFakeChain(numBlocks int, startTimestamp int64) *fakeChain {
+	chain := &fakeChain{}
+	chain.extend(numBlocks, startTimestamp, 0)
+	return chain
+}
+
+// extend mines numBlocks blocks every 10 minutes from the timestamp, the nonce distinguishes forks
+func (c *fakeChain) extend(numBlocks int, startTimestamp int64, nonce uint32) {
+	for i := 0; i < numBlocks; i++ {
+		header := &wire.BlockHeader{
+			Timestamp: time.Unix(startTimestamp+int64(i)*600, 0),
+			Nonce:     nonce,
+		}
+		if len(c.headers) > 0 {
+			header.PrevBlock = c.headers[len(c.headers)-1].BlockHash()
+		}
+		c.push(header)
+	}
+}
+
+func (c *fakeChain) push(header *wire.BlockHeader) {
+	if c.heights == nil {
+		c.heights = make(map[chainhash.Hash]uint64)
+	}
+	c.heights[header.BlockHash()] = uint64(len(c.headers))
+	c.headers = append(c.headers, header)
+}
+
+// reorg replaces the blocks from the height with numBlocks new blocks
+func (c *fakeChain) reorg(height uint64, numBlocks int) {
+	startTimestamp := c.headers[height].Timestamp.Unix()
+	for _, header := range c.headers[height:] {
+		delete(c.heights, header.BlockHash())
+	}
+	c.headers = c.headers[:height]
+	c.extend(numBlocks, startTimestamp, 1)
+}
+
+func (c *fakeChain) GetBlockCount(_ context.Context) (uint64, error) {
+	return uint64(len(c.headers) - 1), nil
+}
+
+func (c *fakeChain) GetBlockHashByHeight(_ context.Context, height uint64) (*chainhash.Hash, error) {
+	if height >= uint64(len(c.headers)) {
+		return nil, fmt.Errorf("block height %d out of range", height)
+	}
+	blockHash := c.headers[height].BlockHash()
+	return &blockHash, nil
+}
+
+func (c *fakeChain) GetBlockHeaderByHash(_ context.Context, blockHash *chainhash.Hash) (*wire.BlockHeader, error) {
+	c.headerQueries.Add(1)
+	if height, ok := c.heights[*blockHash]; ok {
+		return c.headers[height], nil
+	}
+	return nil, fmt.Errorf("block %s not found", blockHash)
+}
+
+func (c *fakeChain) timestamp(height uint64) uint64 {
+	return uint64(c.headers[height].Timestamp.Unix())
+}
+
+func TestHeaderIndexSync(t *testing.T) {
+	const startTimestamp = 1_700_000_000
+	chain := newFakeChain(1000, startTimestamp)
+	idx, err := newHeaderIndex(chain, 100, 0, 0, 0, "", zap.NewNop())
+	require.NoError(t, err)
+
+	// the first sync covers the backfill window, plus the headers needed for the median-time-past
+	require.NoError(t, idx.sync(context.Background()))
+	require.Equal(t, int64(100+medianTimeBlocks), chain.headerQueries.Load())
+	tip, _, ok := idx.tip()
+	require.True(t, ok)
+	require.Equal(t, uint64(999), tip.Height)
+	require.Equal(t, chain.headers[999].BlockHash(), tip.Hash)
+
+	_, ok = idx.headerByHeight(898)
+	require.False(t, ok)
+	header, ok := idx.headerByHeight(899)
+	require.True(t, ok)
+	require.Equal(t, chain.timestamp(899), header.Timestamp)
+	// blocks are mined every 10 minutes, so the median-time-past is the timestamp of the block 5 blocks earlier
+	require.Equal(t, chain.timestamp(894), header.MedianTimePast)
+
+	// later syncs only fetch the new headers
+	chain.extend(5, startTimestamp+1000*600, 0)
+	require.NoError(t, idx.sync(context.Background()))
+	require.Equal(t, int64(100+medianTimeBlocks+5), chain.headerQueries.Load())
+	tip, _, _ = idx.tip()
+	require.Equal(t, uint64(1004), tip.Height)
+
+	// a sync without new blocks does not fetch any header
+	require.NoError(t, idx.sync(context.Background()))
+	require.Equal(t, int64(100+medianTimeBlocks+5), chain.headerQueries.Load())
+}
+
+func TestHeaderIndexSyncFromGenesis(t *testing.T) {
+	chain := newFakeChain(20, 1_700_000_000)
+	idx, err := newHeaderIndex(chain, 100, 0, 0, 0, "", zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, idx.sync(context.Background()))
+
+	// the genesis block is covered, with the median-time-past over the available blocks
+	header, ok := idx.headerByHeight(0)
+	require.True(t, ok)
+	require.Equal(t, chain.timestamp(0), header.MedianTimePast)
+	header, ok = idx.headerByHeight(4)
+	require.True(t, ok)
+	require.Equal(t, chain.timestamp(2), header.MedianTimePast)
+}
+
+func TestHeaderIndexHeightByTimestamp(t *testing.T) {
+	chain := newFakeChain(1000, 1_700_000_000)
+	idx, err := newHeaderIndex(chain, 100, 0, 0, 0, "", zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, idx.sync(context.Background()))
+
+	for height := uint64(899); height < 1000; height++ {
+		// the exact timestamp of the block
+		indexedHeight, ok := idx.heightByTimestamp(chain.timestamp(height))
+		require.True(t, ok)
+		require.Equal(t, height, indexedHeight)
+
+		// a timestamp between the block and the next block
+		indexedHeight, ok = idx.heightByTimestamp(chain.timestamp(height) + 300)
+		require.True(t, ok)
+		require.Equal(t, height, indexedHeight)
+	}
+
+	// the timestamp is before the first covered block
+	_, ok := idx.heightByTimestamp(chain.timestamp(899) - 1)
+	require.False(t, ok)
+}
+
+func TestHeaderIndexReorg(t *testing.T) {
+	chain := newFakeChain(1000, 1_700_000_000)
+	idx, err := newHeaderIndex(chain, 100, 0, 0, 0, "", zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, idx.sync(context.Background()))
+
+	// replace the last 3 blocks with 5 new blocks
+	chain.reorg(997, 5)
+	require.NoError(t, idx.sync(context.Background()))
+
+	for height := uint64(899); height <= 1001; height++ {
+		header, ok := idx.headerByHeight(height)
+		require.True(t, ok)
+		require.Equal(t, chain.headers[height].BlockHash(), header.Hash)
+	}
+
+	// the node's tip is below the last indexed block
+	chain.reorg(995, 2)
+	require.NoError(t, idx.sync(context.Background()))
+	tip, _, ok := idx.tip()
+	require.True(t, ok)
+	require.Equal(t, uint64(996), tip.Height)
+	require.Equal(t, chain.headers[996].BlockHash(), tip.Hash)
+}
+
+func TestHeaderIndexPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "btc-headers.idx")
+	chain := newFakeChain(1000, 1_700_000_000)
+
+	idx, err := newHeaderIndex(chain, 100, 0, 0, 0, path, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, idx.sync(context.Background()))
+	synced, _ := idx.coveredHeaders()
+
+	// a new index loads the persisted headers, and only fetches the new headers
+	chain.extend(3, 1_700_000_000+1000*600, 0)
+	headerQueries := chain.headerQueries.Load()
+	reloaded, err := newHeaderIndex(chain, 100, 0, 0, 0, path, zap.NewNop())
+	require.NoError(t, err)
+	reloadedHeaders, _ := reloaded.coveredHeaders()
+	require.Equal(t, synced, reloadedHeaders)
+	require.NoError(t, reloaded.sync(context.Background()))
+	require.Equal(t, headerQueries+3, chain.headerQueries.Load())
+	tip, _, _ := reloaded.tip()
+	require.Equal(t, uint64(1002), tip.Height)
+}
+
+func TestHeaderIndexPersistenceAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "btc-headers.idx")
+	chain := newFakeChain(1000, 1_700_000_000)
+	idx, err := newHeaderIndex(chain, 100, 0, 0, 0, path, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, idx.sync(context.Background()))
+	saved, err := os.Stat(path)
+	require.NoError(t, err)
+
+	requireReloaded := func() {
+		headers, _ := idx.coveredHeaders()
+		reloaded, err := newHeaderIndex(chain, 100, 0, 0, 0, path, zap.NewNop())
+		require.NoError(t, err)
+		reloadedHeaders, _ := reloaded.coveredHeaders()
+		require.Equal(t, headers, reloadedHeaders)
+
+		// the file is updated in place instead of being replaced
+		updated, err := os.Stat(path)
+		require.NoError(t, err)
+		require.True(t, os.SameFile(saved, updated))
+		require.Equal(t, int64(len(idx.headers)*indexedHeaderSize), updated.Size())
+	}
+
+	// the new headers are appended
+	chain.extend(3, 1_700_000_000+1000*600, 0)
+	require.NoError(t, idx.sync(context.Background()))
+	requireReloaded()
+
+	// the reorged headers are overwritten
+	chain.reorg(998, 2)
+	require.NoError(t, idx.sync(context.Background()))
+	requireReloaded()
+
+	// a partially appended header is dropped
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = file.Write(make([]byte, indexedHeaderSize/2))
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	idx, err = newHeaderIndex(chain, 100, 0, 0, 0, path, zap.NewNop())
+	require.NoError(t, err)
+	chain.extend(1, 1_700_000_000+1000*600, 0)
+	require.NoError(t, idx.sync(context.Background()))
+	requireReloaded()
+}
+
+func TestHeaderIndexPrune(t *testing.T) {
+	const startTimestamp = 1_700_000_000
+	path := filepath.Join(t.TempDir(), "btc-headers.idx")
+	chain := newFakeChain(1000, startTimestamp)
+	// the retention is raised to the backfill window
+	idx, err := newHeaderIndex(chain, 100, 0, 50, 0, path, zap.NewNop())
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), idx.retention)
+	require.NoError(t, idx.sync(context.Background()))
+
+	// the headers below the retention are kept until pruneBatch headers can be pruned
+	chain.extend(pruneBatch-1, startTimestamp+1000*600, 0)
+	require.NoError(t, idx.sync(context.Background()))
+	headers, _ := idx.coveredHeaders()
+	require.Equal(t, uint64(899), headers[0].Height)
+
+	chain.extend(1, startTimestamp+int64(999+pruneBatch)*600, 0)
+	require.NoError(t, idx.sync(context.Background()))
+	headers, _ = idx.coveredHeaders()
+	tip := headers[len(headers)-1]
+	require.Equal(t, uint64(999+pruneBatch), tip.Height)
+	require.Equal(t, tip.Height-100, headers[0].Height)
+	require.Len(t, idx.headers, 100+medianTimeBlocks)
+	// the median-time-past of the first covered header is still exact
+	require.Equal(t, chain.timestamp(headers[0].Height-5), headers[0].MedianTimePast)
+
+	// the pruned index is persisted
+	reloaded, err := newHeaderIndex(chain, 100, 0, 50, 0, path, zap.NewNop())
+	require.NoError(t, err)
+	reloadedHeaders, _ := reloaded.coveredHeaders()
+	require.Equal(t, headers, reloadedHeaders)
+
+	// the pruned timestamps are resolved by querying the node
+	height, err := idx.blockHeightByTimestamp(context.Background(), chain.timestamp(10))
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), height)
+}
+
+func TestHeaderIndexNonMonotoneTimestamps(t *testing.T) {
+	// block 3 is timestamped before its parent, which is valid as long as it is after the median-time-past
+	chain := newFakeChainFromTimestamps([]uint64{100, 200, 300, 250, 400, 500})
+	idx, err := newHeaderIndex(chain, 100, 0, 0, 0, "", zap.NewNop())
+	require.NoError(t, err)
+
+	testCases := []struct {
+		targetTimestamp uint64
+		expectedHeight  uint64
+	}{
+		{100, 0},
+		{199, 0},
+		{200, 1},
+		// the raw timestamp of block 3 is not after the target timestamp, but its parent's is
+		{250, 1},
+		{299, 1},
+		{300, 3},
+		{399, 3},
+		{400, 4},
+		{500, 5},
+	}
+	for _, tc := range testCases {
+		height, err := idx.blockHeightByTimestamp(context.Background(), tc.targetTimestamp)
+		require.NoError(t, err)
+		require.Equal(t, tc.expectedHeight, height, "target timestamp %d", tc.targetTimestamp)
+	}
+
+	// the target timestamp is after the tip
+	_, err = idx.blockHeightByTimestamp(context.Background(), 501)
+	require.ErrorIs(t, err, ErrTimestampAheadOfBtcTip)
+
+	// the target timestamp is before the genesis block
+	_, err = idx.blockHeightByTimestamp(context.Background(), 99)
+	require.ErrorIs(t, err, ErrTimestampBeforeGenesis)
+}
+
+// genNonMonotoneTimestamps generates block timestamps that are only constrained by the consensus rule that a
+// block is timestamped after the median-time-past of its parent
+func genNonMonotoneTimestamps(t *rapid.T, numBlocks int) []uint64 {
+	timestamps := []uint64{1_700_000_000}
+	for len(timestamps) < numBlocks {
+		headers := make([]indexedHeader, len(timestamps))
+		for i, timestamp := range timestamps {
+			headers[i].Timestamp = timestamp
+		}
+		minTimestamp := medianTimePast(headers) + 1
+		timestamps = append(timestamps, minTimestamp+rapid.Uint64Range(0, 7200).Draw(t, "delta"))
+	}
+	return timestamps
+}
+
+func TestPropHeaderIndexMappingIsMonotone(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		numBlocks := rapid.IntRange(1, 150).Draw(t, "numBlocks")
+		timestamps := genNonMonotoneTimestamps(t, numBlocks)
+		chain := newFakeChainFromTimestamps(timestamps)
+		// a partial index is extended backwards by the earliest target timestamp
+		backfill := rapid.Uint64Range(1, uint64(numBlocks)+10).Draw(t, "backfill")
+		idx, err := newHeaderIndex(chain, backfill, 0, 0, 0, "", zap.NewNop())
+		require.NoError(t, err)
+
+		targetTimestamps := rapid.SliceOfN(
+			rapid.Uint64Range(timestamps[0], timestamps[0]+uint64(numBlocks)*3600),
+			1, 50,
+		).Draw(t, "targetTimestamps")
+		sort.Slice(targetTimestamps, func(i, j int) bool { return targetTimestamps[i] < targetTimestamps[j] })
+
+		var lastHeight uint64
+		for _, targetTimestamp := range targetTimestamps {
+			height, err := idx.blockHeightByTimestamp(context.Background(), targetTimestamp)
+			if errors.Is(err, ErrTimestampAheadOfBtcTip) {
+				continue
+			}
+			require.NoError(t, err)
+			require.GreaterOrEqual(t, height, lastHeight)
+			lastHeight = height
+		}
+	})
+}
+
+func TestPropHeaderIndexMappingMatchesEnvelope(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		numBlocks := rapid.IntRange(1, 150).Draw(t, "numBlocks")
+		timestamps := genNonMonotoneTimestamps(t, numBlocks)
+		chain := newFakeChainFromTimestamps(timestamps)
+		// the index covers the whole chain
+		idx, err := newHeaderIndex(chain, uint64(numBlocks), 0, 0, 0, "", zap.NewNop())
+		require.NoError(t, err)
+
+		targetTimestamp := rapid.Uint64Range(timestamps[0], timestamps[0]+uint64(numBlocks)*3600).
+			Draw(t, "targetTimestamp")
+		height, err := idx.blockHeightByTimestamp(context.Background(), targetTimestamp)
+
+		// the expected height is the last block whose timestamp and all earlier timestamps are not after the
+		// target timestamp, or ErrTimestampAheadOfBtcTip if the target timestamp is after all timestamps
+		expectedHeight := uint64(0)
+		maxTimestamp := uint64(0)
+		for h, timestamp := range timestamps {
+			if timestamp > maxTimestamp {
+				maxTimestamp = timestamp
+			}
+			if maxTimestamp <= targetTimestamp {
+				expectedHeight = uint64(h)
+			}
+		}
+		if targetTimestamp > maxTimestamp {
+			require.ErrorIs(t, err, ErrTimestampAheadOfBtcTip)
+			return
+		}
+		require.NoError(t, err)
+		require.Equal(t, expectedHeight, height)
+	})
+}
+
+func TestHeaderIndexExtendBack(t *testing.T) {
+	chain := newFakeChain(3000, 1_700_000_000)
+	idx, err := newHeaderIndex(chain, 100, 0, 0, 0, "", zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, idx.sync(context.Background()))
+
+	// the index is extended backwards to cover an earlier timestamp
+	height, err := idx.blockHeightByTimestamp(context.Background(), chain.timestamp(2500)+1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2500), height)
+	headers, _ := idx.coveredHeaders()
+	require.LessOrEqual(t, headers[0].Height, uint64(2500))
+	for _, header := range headers {
+		require.Equal(t, chain.headers[header.Height].BlockHash(), header.Hash)
+		require.Equal(t, chain.timestamp(header.Height-5), header.MedianTimePast)
+	}
+
+	// the covered timestamps are resolved without fetching headers
+	headerQueries := chain.headerQueries.Load()
+	height, err = idx.blockHeightByTimestamp(context.Background(), chain.timestamp(2600))
+	require.NoError(t, err)
+	require.Equal(t, uint64(2600), height)
+	require.Equal(t, headerQueries, chain.headerQueries.Load())
+
+	// the index is not extended further back than maxIndexExtension blocks
+	height, err = idx.blockHeightByTimestamp(context.Background(), chain.timestamp(10))
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), height)
+	headers, _ = idx.coveredHeaders()
+	require.Greater(t, headers[0].Height, uint64(10))
+}
+
+func TestHeaderIndexConfirmationDepth(t *testing.T) {
+	const startTimestamp = 1_700_000_000
+	chain := newFakeChain(1000, startTimestamp)
+	idx, err := newHeaderIndex(chain, 100, 6, 0, 0, "", zap.NewNop())
+	require.NoError(t, err)
+
+	// the blocks above height 994 have less than 6 confirmations
+	_, err = idx.blockHeightByTimestamp(context.Background(), chain.timestamp(999))
+	require.ErrorIs(t, err, ErrTimestampAheadOfBtcTip)
+	_, err = idx.blockHeightByTimestamp(context.Background(), chain.timestamp(995))
+	require.ErrorIs(t, err, ErrTimestampAheadOfBtcTip)
+	height, err := idx.blockHeightByTimestamp(context.Background(), chain.timestamp(994))
+	require.NoError(t, err)
+	require.Equal(t, uint64(994), height)
+
+	// a new block confirms the block at height 995
+	chain.extend(1, startTimestamp+1000*600, 0)
+	height, err = idx.blockHeightByTimestamp(context.Background(), chain.timestamp(995))
+	require.NoError(t, err)
+	require.Equal(t, uint64(995), height)
+}
+
+func TestHeaderIndexReorgNearTip(t *testing.T) {
+	chain := newFakeChain(1000, 1_700_000_000)
+	idx, err := newHeaderIndex(chain, 100, 6, 0, 0, "", zap.NewNop())
+	require.NoError(t, err)
+
+	mappedHeights := make(map[uint64]uint64)
+	for height := uint64(990); height <= 994; height++ {
+		mappedHeight, err := idx.blockHeightByTimestamp(context.Background(), chain.timestamp(height))
+		require.NoError(t, err)
+		mappedHeights[chain.timestamp(height)] = mappedHeight
+	}
+
+	// replace the last 3 unconfirmed blocks with 5 new blocks
+	chain.reorg(997, 5)
+	height, err := idx.blockHeightByTimestamp(context.Background(), chain.timestamp(996))
+	require.NoError(t, err)
+	require.Equal(t, uint64(996), height)
+
+	// the reorged headers are evicted from the index
+	for height := uint64(997); height <= 1001; height++ {
+		header, ok := idx.headerByHeight(height)
+		require.True(t, ok)
+		require.Equal(t, chain.headers[height].BlockHash(), header.Hash)
+	}
+
+	// the timestamps mapped onto confirmed blocks keep their heights
+	for targetTimestamp, mappedHeight := range mappedHeights {
+		height, err := idx.blockHeightByTimestamp(context.Background(), targetTimestamp)
+		require.NoError(t, err)
+		require.Equal(t, mappedHeight, height)
+	}
+}
+
+func TestHeaderIndexInterval(t *testing.T) {
+	chain := newFakeChain(1000, 1_700_000_000)
+	idx, err := newHeaderIndex(chain, 100, 6, 0, time.Minute, "", zap.NewNop())
+	require.NoError(t, err)
+	now := time.Unix(1_800_000_000, 0)
+	idx.now = func() time.Time { return now }
+
+	height, err := idx.blockHeightByTimestamp(context.Background(), chain.timestamp(990))
+	require.NoError(t, err)
+	require.Equal(t, uint64(990), height)
+
+	// replace the blocks from height 985, including confirmed ones, with 20 new blocks
+	chain.reorg(985, 20)
+
+	// the index is not checked against the node's chain within the interval
+	now = now.Add(time.Minute - time.Second)
+	_, err = idx.blockHeightByTimestamp(context.Background(), chain.timestamp(990))
+	require.NoError(t, err)
+	header, ok := idx.headerByHeight(990)
+	require.True(t, ok)
+	require.NotEqual(t, chain.headers[990].BlockHash(), header.Hash)
+
+	// the reorg is detected once the interval is over, without querying a later timestamp
+	now = now.Add(time.Second)
+	height, err = idx.blockHeightByTimestamp(context.Background(), chain.timestamp(990))
+	require.NoError(t, err)
+	require.Equal(t, uint64(990), height)
+	for height := uint64(985); height <= 1004; height++ {
+		header, ok := idx.headerByHeight(height)
+		require.True(t, ok)
+		require.Equal(t, chain.headers[height].BlockHash(), header.Hash)
+	}
+}
+
+func TestSearchBlockHeightByTimestampConfirmationDepth(t *testing.T) {
+	chain := newFakeChain(100, 1_700_000_000)
+
+	height, err := searchBlockHeightByTimestamp(context.Background(), chain, chain.timestamp(99), 0)
+	require.NoError(t, err)
+	require.Equal(t, uint64(99), height)
+
+	// the blocks above height 94 have less than 6 confirmations
+	_, err = searchBlockHeightByTimestamp(context.Background(), chain, chain.timestamp(99), 6)
+	require.ErrorIs(t, err, ErrTimestampAheadOfBtcTip)
+	height, err = searchBlockHeightByTimestamp(context.Background(), chain, chain.timestamp(94), 6)
+	require.NoError(t, err)
+	require.Equal(t, uint64(94), height)
+	height, err = searchBlockHeightByTimestamp(context.Background(), chain, chain.timestamp(93)+1, 6)
+	require.NoError(t, err)
+	require.Equal(t, uint64(93), height)
+
+	// no block has 200 confirmations
+	_, err = searchBlockHeightByTimestamp(context.Background(), chain, chain.timestamp(0), 200)
+	require.ErrorIs(t, err, ErrTimestampAheadOfBtcTip)
+}
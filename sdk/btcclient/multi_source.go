@@ -0,0 +1,223 @@
+package btcclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/babylon-finality-gadget/sdk/internal/failover"
+)
+
+// endpoint is a header source queried by multiSource, with its health
+type endpoint struct {
+	name   string
+	src    HeaderSource
+	health failover.Health
+}
+
+// multiSource queries a list of header sources with failover
+//
+//   - queries go to the healthy endpoints in order, failing over to the next endpoint on error or timeout
+//   - an endpoint that fails is skipped for a backoff that doubles on each consecutive failure, up to
+//     failover.MaxBackoff. If no endpoint is healthy, the endpoints are queried anyway, the soonest to recover first
+//   - if hashQuorum > 1, the hash of a block height is only returned once hashQuorum endpoints agree on it,
+//     otherwise ErrNoHashQuorum is returned
+type multiSource struct {
+	mu        sync.Mutex
+	endpoints []*endpoint
+
+	hashQuorum int
+	timeout    time.Duration
+	backoff    time.Duration
+	logger     *zap.Logger
+	now        func() time.Time
+}
+
+func newMultiSource(
+	names []string,
+	srcs []HeaderSource,
+	hashQuorum int,
+	timeout time.Duration,
+	backoff time.Duration,
+	logger *zap.Logger,
+) (*multiSource, error) {
+	if len(srcs) == 0 {
+		return nil, fmt.Errorf("no BTC endpoint is configured")
+	}
+	if hashQuorum > len(srcs) {
+		return nil, fmt.Errorf("the hash quorum %d exceeds the number of BTC endpoints %d", hashQuorum, len(srcs))
+	}
+
+	endpoints := make([]*endpoint, len(srcs))
+	for i, src := range srcs {
+		endpoints[i] = &endpoint{name: names[i], src: src}
+	}
+	return &multiSource{
+		endpoints:  endpoints,
+		hashQuorum: hashQuorum,
+		timeout:    timeout,
+		backoff:    backoff,
+		logger:     logger,
+		now:        time.Now,
+	}, nil
+}
+
+func (s *multiSource) GetBlockCount(ctx context.Context) (uint64, error) {
+	var blockCount uint64
+	err := s.failover(ctx, func(ctx context.Context, src HeaderSource) error {
+		var err error
+		blockCount, err = src.GetBlockCount(ctx)
+		return err
+	})
+	return blockCount, err
+}
+
+func (s *multiSource) GetBlockHashByHeight(ctx context.Context, height uint64) (*chainhash.Hash, error) {
+	if s.hashQuorum > 1 {
+		return s.quorumBlockHash(ctx, height)
+	}
+
+	var blockHash *chainhash.Hash
+	err := s.failover(ctx, func(ctx context.Context, src HeaderSource) error {
+		var err error
+		blockHash, err = src.GetBlockHashByHeight(ctx, height)
+		return err
+	})
+	return blockHash, err
+}
+
+func (s *multiSource) GetBlockHeaderByHash(ctx context.Context, blockHash *chainhash.Hash) (*wire.BlockHeader, error) {
+	var header *wire.BlockHeader
+	err := s.failover(ctx, func(ctx context.Context, src HeaderSource) error {
+		var err error
+		header, err = src.GetBlockHeaderByHash(ctx, blockHash)
+		if err != nil {
+			return err
+		}
+		// the header is checked against its hash, so a single endpoint cannot serve a forged header
+		if header.BlockHash() != *blockHash {
+			return fmt.Errorf("the header of block %s has hash %s", blockHash.String(), header.BlockHash().String())
+		}
+		return nil
+	})
+	return header, err
+}
+
+// failover calls the query on the endpoints in order of health, until one succeeds
+func (s *multiSource) failover(ctx context.Context, query func(ctx context.Context, src HeaderSource) error) error {
+	var errs []error
+	for _, ep := range s.orderedEndpoints() {
+		err := s.call(ctx, ep, func(ctx context.Context) error {
+			return query(ctx, ep.src)
+		})
+		if err == nil {
+			return nil
+		}
+		// the caller gave up, the other endpoints would not be queried either
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", ep.name, err))
+	}
+	return fmt.Errorf("all BTC endpoints failed: %w", errors.Join(errs...))
+}
+
+// quorumBlockHash queries all the endpoints concurrently, and returns the first hash that s.hashQuorum
+// endpoints agree on
+func (s *multiSource) quorumBlockHash(ctx context.Context, height uint64) (*chainhash.Hash, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		name      string
+		blockHash *chainhash.Hash
+		err       error
+	}
+	endpoints := s.orderedEndpoints()
+	results := make(chan result, len(endpoints))
+	for _, ep := range endpoints {
+		go func(ep *endpoint) {
+			var blockHash *chainhash.Hash
+			err := s.call(ctx, ep, func(ctx context.Context) error {
+				var err error
+				blockHash, err = ep.src.GetBlockHashByHeight(ctx, height)
+				return err
+			})
+			results <- result{name: ep.name, blockHash: blockHash, err: err}
+		}(ep)
+	}
+
+	votes := make(map[chainhash.Hash]int)
+	var errs []error
+	for range endpoints {
+		res := <-results
+		if res.err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", res.name, res.err))
+			continue
+		}
+		votes[*res.blockHash]++
+		if votes[*res.blockHash] >= s.hashQuorum {
+			return res.blockHash, nil
+		}
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return nil, fmt.Errorf("%w: height %d, %d of %d endpoints needed, votes %v: %w",
+		ErrNoHashQuorum, height, s.hashQuorum, len(endpoints), votes, errors.Join(errs...))
+}
+
+// call runs the query against the endpoint within the endpoint timeout, and updates the endpoint health
+func (s *multiSource) call(ctx context.Context, ep *endpoint, query func(ctx context.Context) error) error {
+	return failover.Call(ctx, s.timeout, s.now, query, func(latency time.Duration, err error) {
+		s.report(ep, latency, err)
+	})
+}
+
+// report records the outcome and the duration of a query to the endpoint
+func (s *multiSource) report(ep *endpoint, latency time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err == nil {
+		if ep.health.Succeeded(s.now(), latency) {
+			s.logger.Info("BTC endpoint recovered", zap.String("endpoint", ep.name))
+		}
+		return
+	}
+
+	backoff := ep.health.Failed(s.now(), s.backoff)
+	s.logger.Warn("BTC endpoint failed, skipping it",
+		zap.String("endpoint", ep.name),
+		zap.Int("consecutive_failures", ep.health.Failures),
+		zap.Duration("backoff", backoff),
+		zap.Error(err))
+}
+
+// orderedEndpoints returns the healthy endpoints in the configured order, followed by the unhealthy
+// endpoints, the soonest to recover first
+func (s *multiSource) orderedEndpoints() []*endpoint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	var healthy, unhealthy []*endpoint
+	for _, ep := range s.endpoints {
+		if now.Before(ep.health.UnhealthyUntil) {
+			unhealthy = append(unhealthy, ep)
+		} else {
+			healthy = append(healthy, ep)
+		}
+	}
+	sort.SliceStable(unhealthy, func(i, j int) bool {
+		return unhealthy[i].health.UnhealthyUntil.Before(unhealthy[j].health.UnhealthyUntil)
+	})
+	return append(healthy, unhealthy...)
+}
@@ -0,0 +1,202 @@
+package btcclient
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// flakySource serves a chain, failing or stalling the queries while told to, and counts the queries
+type flakySource struct {
+	chain   *fakeChain
+	failing atomic.Bool
+	stalled atomic.Bool
+	// forgeHeaders serves the headers of a different block
+	forgeHeaders atomic.Bool
+
+	queries atomic.Int64
+}
+
+func (s *flakySource) before(ctx context.Context) error {
+	s.queries.Add(1)
+	if s.stalled.Load() {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	if s.failing.Load() {
+		return fmt.Errorf("connection refused")
+	}
+	return nil
+}
+
+func (s *flakySource) GetBlockCount(ctx context.Context) (uint64, error) {
+	if err := s.before(ctx); err != nil {
+		return 0, err
+	}
+	return s.chain.GetBlockCount(ctx)
+}
+
+func (s *flakySource) GetBlockHashByHeight(ctx context.Context, height uint64) (*chainhash.Hash, error) {
+	if err := s.before(ctx); err != nil {
+		return nil, err
+	}
+	return s.chain.GetBlockHashByHeight(ctx, height)
+}
+
+func (s *flakySource) GetBlockHeaderByHash(ctx context.Context, blockHash *chainhash.Hash) (*wire.BlockHeader, error) {
+	if err := s.before(ctx); err != nil {
+		return nil, err
+	}
+	if s.forgeHeaders.Load() {
+		return s.chain.headers[0], nil
+	}
+	return s.chain.GetBlockHeaderByHash(ctx, blockHash)
+}
+
+// newTestMultiSource returns a multiSource over the sources, with a clock that only moves when told to
+func newTestMultiSource(t *testing.T, hashQuorum int, srcs ...*flakySource) (*multiSource, *time.Time) {
+	names := make([]string, len(srcs))
+	headerSrcs := make([]HeaderSource, len(srcs))
+	for i, src := range srcs {
+		names[i] = fmt.Sprintf("endpoint-%d", i)
+		headerSrcs[i] = src
+	}
+	s, err := newMultiSource(names, headerSrcs, hashQuorum, 50*time.Millisecond, time.Second, zap.NewNop())
+	require.NoError(t, err)
+	now := time.Unix(1_700_000_000, 0)
+	s.now = func() time.Time { return now }
+	return s, &now
+}
+
+func TestMultiSourceFailover(t *testing.T) {
+	chain := newFakeChain(100, 1_700_000_000)
+	primary, backup := &flakySource{chain: chain}, &flakySource{chain: chain}
+	s, now := newTestMultiSource(t, 0, primary, backup)
+	ctx := context.Background()
+
+	// the primary endpoint is queried first
+	blockCount, err := s.GetBlockCount(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(99), blockCount)
+	require.Equal(t, int64(1), primary.queries.Load())
+	require.Equal(t, int64(0), backup.queries.Load())
+
+	// the failing primary endpoint fails over to the backup
+	primary.failing.Store(true)
+	blockCount, err = s.GetBlockCount(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(99), blockCount)
+	require.Equal(t, int64(2), primary.queries.Load())
+	require.Equal(t, int64(1), backup.queries.Load())
+
+	// the primary endpoint is skipped during its backoff
+	primary.failing.Store(false)
+	_, err = s.GetBlockCount(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), primary.queries.Load())
+	require.Equal(t, int64(2), backup.queries.Load())
+
+	// and queried first again once it is over
+	*now = now.Add(time.Second)
+	_, err = s.GetBlockCount(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), primary.queries.Load())
+	require.Equal(t, int64(2), backup.queries.Load())
+}
+
+func TestMultiSourceBackoff(t *testing.T) {
+	chain := newFakeChain(100, 1_700_000_000)
+	primary := &flakySource{chain: chain}
+	primary.failing.Store(true)
+	s, now := newTestMultiSource(t, 0, primary)
+
+	// the backoff doubles on each consecutive failure
+	for _, backoff := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		_, err := s.GetBlockCount(context.Background())
+		require.Error(t, err)
+		require.Equal(t, now.Add(backoff), s.endpoints[0].health.UnhealthyUntil)
+	}
+
+	// an unhealthy endpoint is still queried if no endpoint is healthy, and recovers on success
+	primary.failing.Store(false)
+	_, err := s.GetBlockCount(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 0, s.endpoints[0].health.Failures)
+}
+
+func TestMultiSourceTimeout(t *testing.T) {
+	chain := newFakeChain(100, 1_700_000_000)
+	primary, backup := &flakySource{chain: chain}, &flakySource{chain: chain}
+	primary.stalled.Store(true)
+	s, _ := newTestMultiSource(t, 0, primary, backup)
+
+	blockHash, err := s.GetBlockHashByHeight(context.Background(), 42)
+	require.NoError(t, err)
+	require.Equal(t, chain.headers[42].BlockHash(), *blockHash)
+	require.Equal(t, 1, s.endpoints[0].health.Failures)
+
+	// the endpoint is not blamed if the caller gives up
+	s.endpoints[0].health.UnhealthyUntil = time.Time{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = s.GetBlockHashByHeight(ctx, 42)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 1, s.endpoints[0].health.Failures)
+}
+
+func TestMultiSourceForgedHeader(t *testing.T) {
+	chain := newFakeChain(100, 1_700_000_000)
+	primary, backup := &flakySource{chain: chain}, &flakySource{chain: chain}
+	primary.forgeHeaders.Store(true)
+	s, _ := newTestMultiSource(t, 0, primary, backup)
+
+	blockHash := chain.headers[42].BlockHash()
+	header, err := s.GetBlockHeaderByHash(context.Background(), &blockHash)
+	require.NoError(t, err)
+	require.Equal(t, blockHash, header.BlockHash())
+	require.Equal(t, int64(1), backup.queries.Load())
+}
+
+func TestMultiSourceHashQuorum(t *testing.T) {
+	chain := newFakeChain(100, 1_700_000_000)
+	fork := newFakeChain(100, 1_700_000_000)
+	fork.reorg(90, 10)
+	honest1, honest2, forked := &flakySource{chain: chain}, &flakySource{chain: chain}, &flakySource{chain: fork}
+
+	// 2 of 3 endpoints agree
+	s, _ := newTestMultiSource(t, 2, forked, honest1, honest2)
+	blockHash, err := s.GetBlockHashByHeight(context.Background(), 95)
+	require.NoError(t, err)
+	require.Equal(t, chain.headers[95].BlockHash(), *blockHash)
+
+	// the endpoints agree below the fork
+	s, _ = newTestMultiSource(t, 3, forked, honest1, honest2)
+	blockHash, err = s.GetBlockHashByHeight(context.Background(), 50)
+	require.NoError(t, err)
+	require.Equal(t, chain.headers[50].BlockHash(), *blockHash)
+
+	// not all the endpoints agree above the fork
+	_, err = s.GetBlockHashByHeight(context.Background(), 95)
+	require.ErrorIs(t, err, ErrNoHashQuorum)
+
+	// a failing endpoint does not count towards the quorum
+	honest2.failing.Store(true)
+	s, _ = newTestMultiSource(t, 2, forked, honest1, honest2)
+	_, err = s.GetBlockHashByHeight(context.Background(), 95)
+	require.ErrorIs(t, err, ErrNoHashQuorum)
+}
+
+func TestNewMultiSourceInvalidQuorum(t *testing.T) {
+	chain := newFakeChain(10, 1_700_000_000)
+	_, err := newMultiSource([]string{"a"}, []HeaderSource{chain}, 2, time.Second, time.Second, zap.NewNop())
+	require.Error(t, err)
+	_, err = newMultiSource(nil, nil, 0, time.Second, time.Second, zap.NewNop())
+	require.Error(t, err)
+}
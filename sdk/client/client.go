@@ -1,9 +1,10 @@
 package client
 
 import (
+	"context"
 	"fmt"
+	"sync/atomic"
 
-	bbncfg "github.com/babylonchain/babylon/client/config"
 	"go.uber.org/zap"
 
 	"github.com/babylonchain/babylon-finality-gadget/sdk/bbnclient"
@@ -11,8 +12,6 @@ import (
 	sdkconfig "github.com/babylonchain/babylon-finality-gadget/sdk/config"
 	"github.com/babylonchain/babylon-finality-gadget/testutil"
 
-	babylonClient "github.com/babylonchain/babylon/client/client"
-
 	"github.com/babylonchain/babylon-finality-gadget/sdk/cwclient"
 )
 
@@ -23,51 +22,129 @@ type SdkClient struct {
 	bbnClient IBabylonClient
 	cwClient  ICosmWasmClient
 	btcClient IBitcoinClient
+	// rpcPool holds the Babylon RPC endpoints shared by the Babylon and CosmWasm clients, it is closed by Close
+	rpcPool *bbnclient.RPCPool
+	// the L2 block is finalized if voted power / total power >= quorumNumerator / quorumDenominator
+	quorumNumerator   uint64
+	quorumDenominator uint64
+	// the strategy used to find the last finalized block of a block range, see sdkconfig.RangeSearch*
+	rangeSearch string
+	// maxConcurrency bounds the contract queries in flight of the per-FP queries, see bbnclient.BBNConfig
+	maxConcurrency int
+	logger         *zap.Logger
+	// tracker is set by StartTracker
+	tracker atomic.Pointer[Tracker]
 }
 
 // NewClient creates a new BabylonFinalityGadgetClient according to the given config
-func NewClient(config *sdkconfig.Config) (*SdkClient, error) {
-	rpcAddr, err := config.GetRpcAddr()
+func NewClient(config *sdkconfig.Config) (_ *SdkClient, err error) {
+	rpcAddrs, err := config.GetRpcAddrs()
 	if err != nil {
 		return nil, err
 	}
 
-	bbnConfig := bbncfg.DefaultBabylonConfig()
-	bbnConfig.RPCAddr = rpcAddr
+	quorumNumerator, quorumDenominator, err := config.GetQuorum()
+	if err != nil {
+		return nil, err
+	}
+
+	rangeSearch, err := config.GetRangeSearch()
+	if err != nil {
+		return nil, err
+	}
+
+	btcBackend, err := config.GetBTCBackend()
+	if err != nil {
+		return nil, err
+	}
 
 	logger, err := zap.NewProduction()
 	if err != nil {
 		return nil, err
 	}
 
-	// Note: We can just ignore the below info which is printed by bbnclient.New
-	// service injective.evm.v1beta1.Msg does not have cosmos.msg.v1.service proto annotation
-	babylonClient, err := babylonClient.New(
-		&bbnConfig,
-		logger,
-	)
+	// the Babylon RPC endpoints are shared by the Babylon and CosmWasm clients, with failover
+	rpcPool, err := bbnclient.NewRPCPool(rpcAddrs, config.GetBBNConfig(), logger)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Babylon client: %w", err)
+		return nil, fmt.Errorf("failed to create Babylon RPC client: %w", err)
 	}
+	defer func() {
+		if err != nil {
+			_ = rpcPool.Close(context.Background())
+		}
+	}()
 
 	var btcClient IBitcoinClient
 	// Create BTC client
-	switch config.ChainID {
+	switch {
+	case btcBackend == sdkconfig.BTCBackendBabylon:
+		lightClient, err := bbnclient.NewBTCLightClient(rpcPool, config.GetBBNConfig())
+		if err != nil {
+			return nil, err
+		}
+		btcClient, err = btcclient.NewBTCClientFromSource(lightClient, config.GetBTCConfig(), logger)
+		if err != nil {
+			return nil, err
+		}
+	case btcBackend == sdkconfig.BTCBackendEsplora:
+		btcClient, err = btcclient.NewEsploraBTCClient(config.GetBTCConfig(), logger)
 	// TODO: once we set up our own local BTC devnet, we don't need to use this mock BTC client
-	case sdkconfig.BabylonLocalnet:
-		btcClient, err = testutil.NewMockBTCClient(config.BTCConfig, logger)
+	case config.ChainID == sdkconfig.BabylonLocalnet:
+		btcClient, err = testutil.NewMockBTCClient(config.GetBTCConfig(), logger)
 	default:
-		btcClient, err = btcclient.NewBTCClient(config.BTCConfig, logger)
+		btcClient, err = btcclient.NewBTCClient(config.GetBTCConfig(), logger)
 	}
 	if err != nil {
 		return nil, err
 	}
 
-	cwClient := cwclient.NewClient(babylonClient.QueryClient.RPCClient, config.ContractAddr)
+	if config.ValidateOnStart {
+		// the BTC light client follows the BTC network of Babylon, and the mock BTC client has no network
+		var btcSrc btcclient.HeaderSource
+		if btcBackend != sdkconfig.BTCBackendBabylon && config.ChainID != sdkconfig.BabylonLocalnet {
+			btcSrc = btcClient
+		}
+		if err := config.Validate(context.Background(), rpcPool, btcSrc); err != nil {
+			return nil, fmt.Errorf("invalid config: %w", err)
+		}
+	}
+
+	bbnClient, err := bbnclient.NewClient(rpcPool, config.GetBBNConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	cwClient := cwclient.NewClient(rpcPool, config.ContractAddr)
 
 	return &SdkClient{
-		bbnClient: &bbnclient.Client{QueryClient: babylonClient.QueryClient},
-		cwClient:  cwClient,
-		btcClient: btcClient,
+		bbnClient:         bbnClient,
+		cwClient:          cwClient,
+		btcClient:         btcClient,
+		rpcPool:           rpcPool,
+		quorumNumerator:   quorumNumerator,
+		quorumDenominator: quorumDenominator,
+		rangeSearch:       rangeSearch,
+		maxConcurrency:    config.GetBBNConfig().MaxConcurrency,
+		logger:            logger,
 	}, nil
 }
+
+// CheckReadiness checks that the client can serve the queries, i.e. that one of its Babylon RPC endpoints answers
+func (sdkClient *SdkClient) CheckReadiness(ctx context.Context) error {
+	if sdkClient.rpcPool == nil {
+		return fmt.Errorf("no Babylon RPC endpoint is configured")
+	}
+	if _, err := sdkClient.rpcPool.Status(ctx); err != nil {
+		return fmt.Errorf("the Babylon RPC endpoints are not reachable: %w", err)
+	}
+	return nil
+}
+
+// Close stops the health checks and the websockets of the Babylon RPC endpoints, waiting for the running health checks
+// until the context is done. The client must not be used afterwards
+func (sdkClient *SdkClient) Close(ctx context.Context) error {
+	if sdkClient.rpcPool == nil {
+		return nil
+	}
+	return sdkClient.rpcPool.Close(ctx)
+}
@@ -0,0 +1,42 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/babylon-finality-gadget/sdk/bbnclient"
+	sdkconfig "github.com/babylonchain/babylon-finality-gadget/sdk/config"
+)
+
+func TestNewClientWithoutBTCConfig(t *testing.T) {
+	// the BTC clients connect lazily, so that no BTC backend is needed to create them
+	for _, chainID := range []string{sdkconfig.BabylonLocalnet, sdkconfig.BabylonDevnet} {
+		t.Run(chainID, func(t *testing.T) {
+			sdkClient, err := NewClient(&sdkconfig.Config{
+				ChainID:      chainID,
+				RPCAddr:      "http://127.0.0.1:1",
+				ContractAddr: "bbn1ghd753shjuwexxywmgs4xz7x2q732vcnkm6h2pyv9s6ah3hylvrqxxvh0f",
+				BTCBackend:   sdkconfig.BTCBackendBitcoind,
+			})
+			require.NoError(t, err)
+			require.NoError(t, sdkClient.Close(context.Background()))
+		})
+	}
+}
+
+func TestCheckReadiness(t *testing.T) {
+	require.Error(t, (&SdkClient{}).CheckReadiness(context.Background()))
+
+	// no Babylon RPC endpoint listens on the port
+	bbnCfg := bbnclient.DefaultBBNConfig()
+	bbnCfg.EndpointTimeout = time.Second
+	rpcPool, err := bbnclient.NewRPCPool([]string{"http://127.0.0.1:1"}, bbnCfg, zap.NewNop())
+	require.NoError(t, err)
+	sdkClient := &SdkClient{rpcPool: rpcPool}
+	defer func() { require.NoError(t, sdkClient.Close(context.Background())) }()
+	require.ErrorContains(t, sdkClient.CheckReadiness(context.Background()), "not reachable")
+}
@@ -1,8 +1,18 @@
 package client
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/babylonchain/babylon-finality-gadget/sdk/btcclient"
+)
 
 var (
 	ErrNoFpHasVotingPower     = fmt.Errorf("no FP has voting power for the consumer chain")
 	ErrBtcStakingNotActivated = fmt.Errorf("BTC staking is not activated for the consumer chain")
+	// ErrTimestampAheadOfBtcTip means that the L2 block timestamp is after the BTC tip, so the finality of the
+	// L2 block cannot be determined until Bitcoin catches up
+	ErrTimestampAheadOfBtcTip = btcclient.ErrTimestampAheadOfBtcTip
+	ErrTimestampBeforeGenesis = btcclient.ErrTimestampBeforeGenesis
+	// ErrTrackerNotStarted means that the finality events are subscribed to before SdkClient.StartTracker is called
+	ErrTrackerNotStarted = fmt.Errorf("the finality tracker is not started")
 )
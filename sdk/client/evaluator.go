@@ -0,0 +1,175 @@
+package client
+
+import (
+	"context"
+	"math/big"
+	"strings"
+
+	"github.com/babylonchain/babylon-finality-gadget/sdk/cwclient"
+	"github.com/babylonchain/babylon-finality-gadget/sdk/finality"
+)
+
+// finalityEvaluator evaluates the finality of L2 blocks against a snapshot of the lookups that do not
+// depend on the L2 block, so that they are shared when evaluating a range of blocks
+//
+//   - the contract state (enabled, consumer chain id), the FP set and the earliest activation height are queried
+//     once when the evaluator is created
+//   - the BTC height of each L2 timestamp and the FPs voting power at each BTC height are memoized, so blocks
+//     resolving to the same BTC height share a single voting power query
+//   - only the voted FPs are queried per block
+type finalityEvaluator struct {
+	sdkClient *SdkClient
+
+	enabled           bool
+	consumerId        string
+	allFpPks          []string
+	earliestDelHeight uint64
+
+	btcHeights  map[uint64]uint64            // L2 block timestamp -> BTC height
+	powerTables map[uint64]map[string]uint64 // BTC height -> FP voting power
+}
+
+// newFinalityEvaluator queries the lookups shared by all L2 blocks
+func (sdkClient *SdkClient) newFinalityEvaluator(ctx context.Context) (*finalityEvaluator, error) {
+	evaluator := &finalityEvaluator{
+		sdkClient:   sdkClient,
+		btcHeights:  make(map[uint64]uint64),
+		powerTables: make(map[uint64]map[string]uint64),
+	}
+
+	// check if the finality gadget is enabled
+	// if not, always return true to pass through op derivation pipeline
+	isEnabled, err := sdkClient.cwClient.QueryIsEnabled(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !isEnabled {
+		return evaluator, nil
+	}
+	evaluator.enabled = true
+
+	// get all FPs pubkey for the consumer chain
+	evaluator.consumerId, evaluator.allFpPks, err = sdkClient.queryAllFpBtcPubKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// get the earliest BTC height at which the btc staking is actived
+	evaluator.earliestDelHeight, err = sdkClient.bbnClient.QueryEarliestActiveDelBtcHeight(ctx, evaluator.allFpPks)
+	if err != nil {
+		return nil, err
+	}
+
+	return evaluator, nil
+}
+
+// evaluate returns the finality verdict of the given L2 block
+func (evaluator *finalityEvaluator) evaluate(
+	ctx context.Context,
+	queryParams cwclient.L2Block,
+) (*finality.Result, error) {
+	if !evaluator.enabled {
+		return &finality.Result{Enabled: false, Finalized: true}, nil
+	}
+
+	sdkClient := evaluator.sdkClient
+
+	// trim prefix 0x for the L2 block hash
+	queryParams.BlockHash = strings.TrimPrefix(queryParams.BlockHash, "0x")
+
+	// convert the L2 timestamp to BTC height
+	btcblockHeight, err := evaluator.btcHeight(ctx, queryParams.BlockTimestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	// check whether the btc staking is actived
+	if btcblockHeight < evaluator.earliestDelHeight {
+		return nil, ErrBtcStakingNotActivated
+	}
+
+	// get all FPs voting power at this BTC height
+	allFpPower, err := evaluator.powerTable(ctx, btcblockHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	// calculate total voting power
+	// the sum is accumulated as a big integer as it can overflow uint64 for large stake totals
+	totalPower := new(big.Int)
+	for _, power := range allFpPower {
+		addPower(totalPower, power)
+	}
+
+	// no FP has voting power for the consumer chain
+	if totalPower.Sign() == 0 {
+		return nil, ErrNoFpHasVotingPower
+	}
+
+	// get all FPs that voted this (L2 block height, L2 block hash) combination
+	votedFpPks, err := sdkClient.cwClient.QueryListOfVotedFinalityProviders(ctx, &queryParams)
+	if err != nil {
+		return nil, err
+	}
+	votedFps := make(map[string]struct{}, len(votedFpPks))
+	for _, key := range votedFpPks {
+		votedFps[key] = struct{}{}
+	}
+
+	// calculate voted voting power
+	votedPower := new(big.Int)
+	fpVotes := make([]finality.FpVote, 0, len(evaluator.allFpPks))
+	for _, key := range evaluator.allFpPks {
+		power := allFpPower[key]
+		_, voted := votedFps[key]
+		if voted {
+			addPower(votedPower, power)
+		}
+		fpVotes = append(fpVotes, finality.FpVote{FpBtcPkHex: key, Power: power, Voted: voted})
+	}
+
+	// quorom >= quorumNumerator / quorumDenominator (2/3 by default)
+	isFinalized := isQuorumReached(votedPower, totalPower, sdkClient.quorumNumerator, sdkClient.quorumDenominator)
+
+	return &finality.Result{
+		Enabled:           true,
+		Finalized:         isFinalized,
+		BtcHeight:         btcblockHeight,
+		TotalPower:        totalPower,
+		VotedPower:        votedPower,
+		FpVotes:           fpVotes,
+		QuorumNumerator:   sdkClient.quorumNumerator,
+		QuorumDenominator: sdkClient.quorumDenominator,
+	}, nil
+}
+
+// btcHeight converts the L2 timestamp to BTC height, memoizing the result
+func (evaluator *finalityEvaluator) btcHeight(ctx context.Context, timestamp uint64) (uint64, error) {
+	if height, ok := evaluator.btcHeights[timestamp]; ok {
+		return height, nil
+	}
+	height, err := evaluator.sdkClient.btcClient.GetBlockHeightByTimestamp(ctx, timestamp)
+	if err != nil {
+		return 0, err
+	}
+	evaluator.btcHeights[timestamp] = height
+	return height, nil
+}
+
+// powerTable returns all FPs voting power at the BTC height, memoizing the result
+func (evaluator *finalityEvaluator) powerTable(ctx context.Context, btcHeight uint64) (map[string]uint64, error) {
+	if powerTable, ok := evaluator.powerTables[btcHeight]; ok {
+		return powerTable, nil
+	}
+	powerTable, err := evaluator.sdkClient.bbnClient.QueryMultiFpPower(
+		ctx,
+		evaluator.consumerId,
+		evaluator.allFpPks,
+		btcHeight,
+	)
+	if err != nil {
+		return nil, err
+	}
+	evaluator.powerTables[btcHeight] = powerTable
+	return powerTable, nil
+}
@@ -1,28 +1,44 @@
 package client
 
 import (
+	"context"
+
 	"github.com/babylonchain/babylon-finality-gadget/sdk/cwclient"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
 )
 
 type IBabylonClient interface {
-	QueryAllFpBtcPubKeys(consumerId string) ([]string, error)
-	QueryFpPower(fpPubkeyHex string, btcHeight uint64) (uint64, error)
-	QueryMultiFpPower(fpPubkeyHexList []string, btcHeight uint64) (map[string]uint64, error)
-	QueryEarliestActiveDelBtcHeight(fpPubkeyHexList []string) (uint64, error)
+	QueryAllFpBtcPubKeys(ctx context.Context, consumerId string) ([]string, error)
+	QueryFpPower(ctx context.Context, fpPubkeyHex string, btcHeight uint64) (uint64, error)
+	QueryMultiFpPower(
+		ctx context.Context,
+		consumerId string,
+		fpPubkeyHexList []string,
+		btcHeight uint64,
+	) (map[string]uint64, error)
+	QueryEarliestActiveDelBtcHeight(ctx context.Context, fpPubkeyHexList []string) (uint64, error)
+	SubscribeNewBlocks(ctx context.Context) (<-chan uint64, error)
 }
 
 type IBitcoinClient interface {
-	GetBlockCount() (uint64, error)
-	GetBlockHashByHeight(height uint64) (*chainhash.Hash, error)
-	GetBlockHeaderByHash(blockHash *chainhash.Hash) (*wire.BlockHeader, error)
-	GetBlockHeightByTimestamp(targetTimestamp uint64) (uint64, error)
-	GetBlockTimestampByHeight(height uint64) (uint64, error)
+	GetBlockCount(ctx context.Context) (uint64, error)
+	GetBlockHashByHeight(ctx context.Context, height uint64) (*chainhash.Hash, error)
+	GetBlockHeaderByHash(ctx context.Context, blockHash *chainhash.Hash) (*wire.BlockHeader, error)
+	GetBlockHeightByTimestamp(ctx context.Context, targetTimestamp uint64) (uint64, error)
+	GetBlockTimestampByHeight(ctx context.Context, height uint64) (uint64, error)
 }
 
 type ICosmWasmClient interface {
-	QueryListOfVotedFinalityProviders(queryParams *cwclient.L2Block) ([]string, error)
-	QueryConsumerId() (string, error)
-	QueryIsEnabled() (bool, error)
+	QueryListOfVotedFinalityProviders(ctx context.Context, queryParams *cwclient.L2Block) ([]string, error)
+	QueryConsumerId(ctx context.Context) (string, error)
+	QueryIsEnabled(ctx context.Context) (bool, error)
+	QueryEvidence(ctx context.Context, fpPubkeyHex string, height uint64) (*cwclient.Evidence, error)
+}
+
+// IL2Client provides the blocks of the L2 chain whose finality is tracked
+type IL2Client interface {
+	// HeadBlock returns the latest L2 block to track
+	HeadBlock(ctx context.Context) (*cwclient.L2Block, error)
+	BlockByHeight(ctx context.Context, height uint64) (*cwclient.L2Block, error)
 }
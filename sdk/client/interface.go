@@ -1,6 +1,11 @@
 package client
 
-import "github.com/babylonchain/babylon-finality-gadget/sdk/cwclient"
+import (
+	"context"
+
+	"github.com/babylonchain/babylon-finality-gadget/sdk/cwclient"
+	"github.com/babylonchain/babylon-finality-gadget/sdk/finality"
+)
 
 type ISdkClient interface {
 	/* QueryIsBlockBabylonFinalized checks if the given L2 block is finalized by the Babylon finality gadget
@@ -17,9 +22,24 @@ type ISdkClient interface {
 	 *   - calculate total voting power
 	 *   - get all FPs that voted this L2 block with the same height and hash
 	 *   - calculate voted voting power
-	 *   - check if the voted voting power is more than 2/3 of the total voting power
+	 *   - check if the voted voting power reaches the quorum (2/3 of the total voting power by default)
+	 *
+	 * - returns ErrTimestampAheadOfBtcTip if the L2 block timestamp is after the BTC tip, i.e. the caller should
+	 *   retry once Bitcoin catches up, and ErrTimestampBeforeGenesis if it is before the BTC genesis block
+	 *
+	 * the given context is honored by every query issued to Babylon and Bitcoin
 	 */
-	QueryIsBlockBabylonFinalized(queryParams cwclient.L2Block) (bool, error)
+	QueryIsBlockBabylonFinalized(ctx context.Context, queryParams cwclient.L2Block) (bool, error)
+
+	/* QueryBlockFinalityResult returns the detailed finality verdict of the given L2 block
+	 *
+	 * - it runs the same checks as QueryIsBlockBabylonFinalized, and returns the BTC height, the total and voted
+	 *   voting power, the voting power and vote status of each FP, and the quorum threshold applied
+	 * - additionally, it queries the contract for FPs that have equivocation evidence at the L2 block height, i.e.
+	 *   FPs that also voted a conflicting block hash
+	 * - if the finality gadget is not enabled, return a result with Enabled = false and Finalized = true
+	 */
+	QueryBlockFinalityResult(ctx context.Context, queryParams cwclient.L2Block) (*finality.Result, error)
 
 	/* QueryBlockRangeBabylonFinalized searches for a row of consecutive finalized blocks in the block range, and returns
 	 * the last finalized block height
@@ -34,8 +54,11 @@ type ISdkClient interface {
 	 *
 	 * Note: caller needs to make sure the given queryBlocks are consecutive (we don't check hashes inside this method)
 	 * and start from low to high
+	 *
+	 * if the context is cancelled in the middle of the range, return the last found consecutive finalized block
+	 * height together with the context error
 	 */
-	QueryBlockRangeBabylonFinalized(queryBlocks []*cwclient.L2Block) (*uint64, error)
+	QueryBlockRangeBabylonFinalized(ctx context.Context, queryBlocks []*cwclient.L2Block) (*uint64, error)
 
 	/* QueryBtcStakingActivatedTimestamp returns the timestamp when the BTC staking is activated
 	 *
@@ -53,5 +76,5 @@ type ISdkClient interface {
 	 *
 	 * returns math.MaxUint64, ErrBtcStakingNotActivated if the BTC staking is not activated
 	 */
-	QueryBtcStakingActivatedTimestamp() (uint64, error)
+	QueryBtcStakingActivatedTimestamp(ctx context.Context) (uint64, error)
 }
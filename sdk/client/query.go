@@ -1,11 +1,14 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"math"
-	"strings"
 
+	sdkconfig "github.com/babylonchain/babylon-finality-gadget/sdk/config"
 	"github.com/babylonchain/babylon-finality-gadget/sdk/cwclient"
+	"github.com/babylonchain/babylon-finality-gadget/sdk/finality"
+	"github.com/babylonchain/babylon-finality-gadget/sdk/internal/concurrency"
 )
 
 /* QueryIsBlockBabylonFinalized checks if the given L2 block is finalized by the Babylon finality gadget
@@ -22,83 +25,62 @@ import (
  *   - calculate total voting power
  *   - get all FPs that voted this L2 block with the same height and hash
  *   - calculate voted voting power
- *   - check if the voted voting power is more than 2/3 of the total voting power
+ *   - check if the voted voting power reaches the quorum (2/3 of the total voting power by default)
+ *
+ * the given context is honored by every query issued to Babylon and Bitcoin
  */
 func (sdkClient *SdkClient) QueryIsBlockBabylonFinalized(
+	ctx context.Context,
 	queryParams cwclient.L2Block,
 ) (bool, error) {
-	// check if the finality gadget is enabled
-	// if not, always return true to pass through op derivation pipeline
-	isEnabled, err := sdkClient.cwClient.QueryIsEnabled()
-	if err != nil {
-		return false, err
-	}
-	if !isEnabled {
-		return true, nil
-	}
-
-	// trim prefix 0x for the L2 block hash
-	queryParams.BlockHash = strings.TrimPrefix(queryParams.BlockHash, "0x")
-
-	// get all FPs pubkey for the consumer chain
-	allFpPks, err := sdkClient.queryAllFpBtcPubKeys()
-	if err != nil {
-		return false, err
-	}
-
-	// convert the L2 timestamp to BTC height
-	btcblockHeight, err := sdkClient.btcClient.GetBlockHeightByTimestamp(queryParams.BlockTimestamp)
+	result, err := sdkClient.queryFinalityResult(ctx, queryParams)
 	if err != nil {
 		return false, err
 	}
+	return result.Finalized, nil
+}
 
-	// check whether the btc staking is actived
-	earliestDelHeight, err := sdkClient.bbnClient.QueryEarliestActiveDelBtcHeight(allFpPks)
+/* QueryBlockFinalityResult returns the detailed finality verdict of the given L2 block
+ *
+ * - it runs the same checks as QueryIsBlockBabylonFinalized, and returns the BTC height, the total and voted
+ *   voting power, the voting power and vote status of each FP, and the quorum threshold applied
+ * - additionally, it queries the contract for the FPs that have equivocation evidence at the L2 block height, i.e.
+ *   whose vote for a conflicting block hash was proven and recorded in the contract. The FPs are queried
+ *   concurrently, and a conflicting vote without evidence is not reported
+ * - if the finality gadget is not enabled, return a result with Enabled = false and Finalized = true
+ */
+func (sdkClient *SdkClient) QueryBlockFinalityResult(
+	ctx context.Context,
+	queryParams cwclient.L2Block,
+) (*finality.Result, error) {
+	result, err := sdkClient.queryFinalityResult(ctx, queryParams)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	if btcblockHeight < earliestDelHeight {
-		return false, ErrBtcStakingNotActivated
+	if !result.Enabled {
+		return result, nil
 	}
 
-	// get all FPs voting power at this BTC height
-	allFpPower, err := sdkClient.bbnClient.QueryMultiFpPower(allFpPks, btcblockHeight)
+	// get all FPs that have equivocation evidence at this L2 block height
+	conflictingFpPks, err := sdkClient.queryConflictingFps(ctx, result.FpVotes, queryParams.BlockHeight)
 	if err != nil {
-		return false, err
-	}
-
-	// calculate total voting power
-	var totalPower uint64 = 0
-	for _, power := range allFpPower {
-		totalPower += power
+		return nil, err
 	}
+	result.ConflictingFps = conflictingFpPks
 
-	// no FP has voting power for the consumer chain
-	if totalPower == 0 {
-		return false, ErrNoFpHasVotingPower
-	}
+	return result, nil
+}
 
-	// get all FPs that voted this (L2 block height, L2 block hash) combination
-	votedFpPks, err := sdkClient.cwClient.QueryListOfVotedFinalityProviders(&queryParams)
+// queryFinalityResult runs the finality check of QueryIsBlockBabylonFinalized and keeps the intermediate results
+func (sdkClient *SdkClient) queryFinalityResult(
+	ctx context.Context,
+	queryParams cwclient.L2Block,
+) (*finality.Result, error) {
+	evaluator, err := sdkClient.newFinalityEvaluator(ctx)
 	if err != nil {
-		return false, err
-	}
-	if votedFpPks == nil {
-		return false, nil
-	}
-	// calculate voted voting power
-	var votedPower uint64 = 0
-	for _, key := range votedFpPks {
-		if power, exists := allFpPower[key]; exists {
-			votedPower += power
-		}
-	}
-
-	// quorom < 2/3
-	if votedPower*3 < totalPower*2 {
-		return false, nil
+		return nil, err
 	}
-	return true, nil
+	return evaluator.evaluate(ctx, queryParams)
 }
 
 /* QueryBlockRangeBabylonFinalized searches for a row of consecutive finalized blocks in the block range, and returns
@@ -114,8 +96,20 @@ func (sdkClient *SdkClient) QueryIsBlockBabylonFinalized(
  *
  * Note: caller needs to make sure the given queryBlocks are consecutive (we don't check hashes inside this method)
  * and start from low to high
+ *
+ * if the context is cancelled in the middle of the range, return the last found consecutive finalized block
+ * height together with the context error
+ *
+ * the contract state, the FP set and the BTC staking activation height are queried once for the whole range, and
+ * blocks resolving to the same BTC height share the FPs voting power. Only the voted FPs are queried per block
+ *
+ * with the sdkconfig.RangeSearchBisect strategy, the range is bisected instead of scanned, assuming that finality
+ * is monotone along the range, i.e. no block is finalized after a block that is not finalized. It checks
+ * O(log n) blocks instead of up to n blocks. On error, it returns the highest block height verified as finalized
+ * so far, which may be lower than the height the linear scan would return
  */
 func (sdkClient *SdkClient) QueryBlockRangeBabylonFinalized(
+	ctx context.Context,
 	queryBlocks []*cwclient.L2Block,
 ) (*uint64, error) {
 	if len(queryBlocks) == 0 {
@@ -127,19 +121,14 @@ func (sdkClient *SdkClient) QueryBlockRangeBabylonFinalized(
 			return nil, fmt.Errorf("blocks are not consecutive")
 		}
 	}
-	var finalizedBlockHeight *uint64
-	for _, block := range queryBlocks {
-		isFinalized, err := sdkClient.QueryIsBlockBabylonFinalized(*block)
-		if err != nil {
-			return finalizedBlockHeight, err
-		}
-		if isFinalized {
-			finalizedBlockHeight = &block.BlockHeight
-		} else {
-			break
-		}
+	evaluator, err := sdkClient.newFinalityEvaluator(ctx)
+	if err != nil {
+		return nil, err
 	}
-	return finalizedBlockHeight, nil
+	if sdkClient.rangeSearch == sdkconfig.RangeSearchBisect {
+		return evaluator.searchBisect(ctx, queryBlocks)
+	}
+	return evaluator.searchLinear(ctx, queryBlocks)
 }
 
 /* QueryBtcStakingActivatedTimestamp returns the timestamp when the BTC staking is activated
@@ -158,14 +147,14 @@ func (sdkClient *SdkClient) QueryBlockRangeBabylonFinalized(
  *
  * returns math.MaxUint64, ErrBtcStakingNotActivated if the BTC staking is not activated
  */
-func (sdkClient *SdkClient) QueryBtcStakingActivatedTimestamp() (uint64, error) {
-	allFpPks, err := sdkClient.queryAllFpBtcPubKeys()
+func (sdkClient *SdkClient) QueryBtcStakingActivatedTimestamp(ctx context.Context) (uint64, error) {
+	_, allFpPks, err := sdkClient.queryAllFpBtcPubKeys(ctx)
 	if err != nil {
 		return math.MaxUint64, err
 	}
 
 	// check whether the btc staking is actived
-	earliestDelHeight, err := sdkClient.bbnClient.QueryEarliestActiveDelBtcHeight(allFpPks)
+	earliestDelHeight, err := sdkClient.bbnClient.QueryEarliestActiveDelBtcHeight(ctx, allFpPks)
 	if err != nil {
 		return math.MaxUint64, err
 	}
@@ -176,24 +165,57 @@ func (sdkClient *SdkClient) QueryBtcStakingActivatedTimestamp() (uint64, error)
 	}
 
 	// get the timestamp of the BTC height
-	btcBlockTimestamp, err := sdkClient.btcClient.GetBlockTimestampByHeight(earliestDelHeight)
+	btcBlockTimestamp, err := sdkClient.btcClient.GetBlockTimestampByHeight(ctx, earliestDelHeight)
 	if err != nil {
 		return math.MaxUint64, err
 	}
 	return btcBlockTimestamp, nil
 }
 
-func (sdkClient *SdkClient) queryAllFpBtcPubKeys() ([]string, error) {
+// queryAllFpBtcPubKeys returns the consumer chain id and all the FPs pubkey for the consumer chain
+func (sdkClient *SdkClient) queryAllFpBtcPubKeys(ctx context.Context) (string, []string, error) {
 	// get the consumer chain id
-	consumerId, err := sdkClient.cwClient.QueryConsumerId()
+	consumerId, err := sdkClient.cwClient.QueryConsumerId(ctx)
 	if err != nil {
-		return nil, err
+		return "", nil, err
 	}
 
 	// get all the FPs pubkey for the consumer chain
-	allFpPks, err := sdkClient.bbnClient.QueryAllFpBtcPubKeys(consumerId)
+	allFpPks, err := sdkClient.bbnClient.QueryAllFpBtcPubKeys(ctx, consumerId)
+	if err != nil {
+		return "", nil, err
+	}
+	return consumerId, allFpPks, nil
+}
+
+// queryConflictingFps returns the FPs that have equivocation evidence at the given L2 block height
+//
+// the FPs are queried concurrently, with at most BBNConfig.MaxConcurrency queries in flight
+func (sdkClient *SdkClient) queryConflictingFps(
+	ctx context.Context,
+	fpVotes []finality.FpVote,
+	blockHeight uint64,
+) ([]string, error) {
+	// each query writes to its own slot, so that the FPs are listed in the order of fpVotes
+	hasEvidence := make([]bool, len(fpVotes))
+	err := concurrency.ForEach(ctx, sdkClient.maxConcurrency, fpVotes,
+		func(ctx context.Context, i int, fpVote finality.FpVote) error {
+			evidence, err := sdkClient.cwClient.QueryEvidence(ctx, fpVote.FpBtcPkHex, blockHeight)
+			if err != nil {
+				return err
+			}
+			hasEvidence[i] = evidence != nil
+			return nil
+		})
 	if err != nil {
 		return nil, err
 	}
-	return allFpPks, nil
+
+	var conflictingFpPks []string
+	for i, fpVote := range fpVotes {
+		if hasEvidence[i] {
+			conflictingFpPks = append(conflictingFpPks, fpVote.FpBtcPkHex)
+		}
+	}
+	return conflictingFpPks, nil
 }
@@ -1,13 +1,20 @@
 package client
 
 import (
+	"context"
 	"fmt"
+	"math"
+	"math/big"
 	"math/rand"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/babylonchain/babylon-finality-gadget/sdk/btcclient"
+	sdkconfig "github.com/babylonchain/babylon-finality-gadget/sdk/config"
 	"github.com/babylonchain/babylon-finality-gadget/sdk/cwclient"
+	"github.com/babylonchain/babylon-finality-gadget/sdk/finality"
 	"github.com/babylonchain/babylon-finality-gadget/testutil"
 	"github.com/babylonchain/babylon-finality-gadget/testutil/mocks"
 	"github.com/stretchr/testify/require"
@@ -19,7 +26,7 @@ func TestFinalityGadgetDisabled(t *testing.T) {
 
 	// mock CwClient
 	mockCwClient := mocks.NewMockICosmWasmClient(ctl)
-	mockCwClient.EXPECT().QueryIsEnabled().Return(false, nil).Times(1)
+	mockCwClient.EXPECT().QueryIsEnabled(gomock.Any()).Return(false, nil).Times(1)
 
 	mockSdkClient := &SdkClient{
 		cwClient:  mockCwClient,
@@ -28,7 +35,7 @@ func TestFinalityGadgetDisabled(t *testing.T) {
 	}
 
 	// check QueryIsBlockBabylonFinalized always returns true when finality gadget is not enabled
-	res, err := mockSdkClient.QueryIsBlockBabylonFinalized(cwclient.L2Block{})
+	res, err := mockSdkClient.QueryIsBlockBabylonFinalized(context.Background(), cwclient.L2Block{})
 	require.NoError(t, err)
 	require.True(t, res)
 }
@@ -47,6 +54,8 @@ func TestQueryIsBlockBabylonFinalized(t *testing.T) {
 	const BTCHeight = uint64(111)
 	BTCActivatedHeight := BTCHeight - 1
 	BTCNotActivatedHeight := BTCHeight + 1
+	// QueryEarliestActiveDelBtcHeight returns math.MaxUint64 if no FP has an active delegation
+	BTCNoDelegationHeight := uint64(math.MaxUint64)
 
 	testCases := []struct {
 		name           string
@@ -136,62 +145,148 @@ func TestQueryIsBlockBabylonFinalized(t *testing.T) {
 			defer ctl.Finish()
 
 			mockCwClient := mocks.NewMockICosmWasmClient(ctl)
-			mockCwClient.EXPECT().QueryIsEnabled().Return(true, nil).Times(1)
-			mockCwClient.EXPECT().QueryConsumerId().Return(consumerChainID, nil).Times(1)
+			mockCwClient.EXPECT().QueryIsEnabled(gomock.Any()).Return(true, nil).Times(1)
+			mockCwClient.EXPECT().QueryConsumerId(gomock.Any()).Return(consumerChainID, nil).Times(1)
 			if tc.expectedErr != ErrBtcStakingNotActivated {
 				mockCwClient.EXPECT().
-					QueryListOfVotedFinalityProviders(&blockWithHashTrimmed).
+					QueryListOfVotedFinalityProviders(gomock.Any(), &blockWithHashTrimmed).
 					Return(tc.votedProviders, nil).
 					Times(1)
 			}
 
 			mockBTCClient := mocks.NewMockIBitcoinClient(ctl)
 			mockBTCClient.EXPECT().
-				GetBlockHeightByTimestamp(tc.queryParams.BlockTimestamp).
+				GetBlockHeightByTimestamp(gomock.Any(), tc.queryParams.BlockTimestamp).
 				Return(BTCHeight, nil).
 				Times(1)
 
 			mockBBNClient := mocks.NewMockIBabylonClient(ctl)
 			mockBBNClient.EXPECT().
-				QueryAllFpBtcPubKeys(consumerChainID).
+				QueryAllFpBtcPubKeys(gomock.Any(), consumerChainID).
 				Return(tc.allFpPks, nil).
 				Times(1)
 			if tc.expectedErr != ErrBtcStakingNotActivated {
 				mockBBNClient.EXPECT().
-					QueryMultiFpPower(tc.allFpPks, BTCHeight).
+					QueryMultiFpPower(gomock.Any(), consumerChainID, tc.allFpPks, BTCHeight).
 					Return(tc.fpPowers, nil).
 					Times(1)
 			}
 			if tc.name == "FP no delegation, 100% votes, expects false" {
 				mockBBNClient.EXPECT().
-					QueryEarliestActiveDelBtcHeight(tc.allFpPks).
-					Return(uint64(0), nil).
+					QueryEarliestActiveDelBtcHeight(gomock.Any(), tc.allFpPks).
+					Return(BTCNoDelegationHeight, nil).
 					Times(1)
 			} else if tc.name == "Btc staking not activated, 100% votes, expects false" {
 				mockBBNClient.EXPECT().
-					QueryEarliestActiveDelBtcHeight(tc.allFpPks).
+					QueryEarliestActiveDelBtcHeight(gomock.Any(), tc.allFpPks).
 					Return(BTCNotActivatedHeight, nil).
 					Times(1)
 			} else {
 				mockBBNClient.EXPECT().
-					QueryEarliestActiveDelBtcHeight(tc.allFpPks).
+					QueryEarliestActiveDelBtcHeight(gomock.Any(), tc.allFpPks).
 					Return(BTCActivatedHeight, nil).
 					Times(1)
 			}
 
 			mockSdkClient := &SdkClient{
-				cwClient:  mockCwClient,
-				bbnClient: mockBBNClient,
-				btcClient: mockBTCClient,
+				cwClient:          mockCwClient,
+				bbnClient:         mockBBNClient,
+				btcClient:         mockBTCClient,
+				quorumNumerator:   sdkconfig.DefaultQuorumNumerator,
+				quorumDenominator: sdkconfig.DefaultQuorumDenominator,
 			}
 
-			res, err := mockSdkClient.QueryIsBlockBabylonFinalized(*tc.queryParams)
+			res, err := mockSdkClient.QueryIsBlockBabylonFinalized(context.Background(), *tc.queryParams)
 			require.Equal(t, tc.expectResult, res)
 			require.Equal(t, tc.expectedErr, err)
 		})
 	}
 }
 
+func TestQueryIsBlockBabylonFinalizedSimpleMajority(t *testing.T) {
+	block := cwclient.L2Block{BlockHash: "abcd", BlockHeight: 123, BlockTimestamp: 12345}
+	allFpPks := []string{"pk1", "pk2"}
+
+	for _, tc := range []struct {
+		name           string
+		fpPowers       map[string]uint64
+		votedProviders []string
+		expectResult   bool
+	}{
+		{"50/50 split", map[string]uint64{"pk1": 100, "pk2": 100}, []string{"pk1"}, false},
+		{"majority", map[string]uint64{"pk1": 101, "pk2": 100}, []string{"pk1"}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			mockCwClient := mocks.NewMockICosmWasmClient(ctl)
+			mockCwClient.EXPECT().QueryIsEnabled(gomock.Any()).Return(true, nil).Times(1)
+			mockCwClient.EXPECT().QueryConsumerId(gomock.Any()).Return("consumer-chain-id", nil).Times(1)
+			mockCwClient.EXPECT().QueryListOfVotedFinalityProviders(gomock.Any(), &block).
+				Return(tc.votedProviders, nil).Times(1)
+			mockBTCClient := mocks.NewMockIBitcoinClient(ctl)
+			mockBTCClient.EXPECT().GetBlockHeightByTimestamp(gomock.Any(), block.BlockTimestamp).Return(uint64(111), nil)
+			mockBBNClient := mocks.NewMockIBabylonClient(ctl)
+			mockBBNClient.EXPECT().QueryAllFpBtcPubKeys(gomock.Any(), "consumer-chain-id").Return(allFpPks, nil)
+			mockBBNClient.EXPECT().QueryMultiFpPower(gomock.Any(), "consumer-chain-id", allFpPks, uint64(111)).
+				Return(tc.fpPowers, nil)
+			mockBBNClient.EXPECT().QueryEarliestActiveDelBtcHeight(gomock.Any(), allFpPks).Return(uint64(110), nil)
+
+			// the threshold of 1/2 requires more than half of the voting power
+			sdkClient := &SdkClient{
+				cwClient:          mockCwClient,
+				bbnClient:         mockBBNClient,
+				btcClient:         mockBTCClient,
+				quorumNumerator:   1,
+				quorumDenominator: 2,
+			}
+			res, err := sdkClient.QueryIsBlockBabylonFinalized(context.Background(), block)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectResult, res)
+		})
+	}
+}
+
+func TestQueryIsBlockBabylonFinalizedTimestampAheadOfBtcTip(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	block := cwclient.L2Block{
+		BlockHash:      "d4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3",
+		BlockHeight:    123,
+		BlockTimestamp: 12345,
+	}
+	const consumerChainID = "consumer-chain-id"
+	allFpPks := []string{"pk1", "pk2", "pk3"}
+
+	mockCwClient := mocks.NewMockICosmWasmClient(ctl)
+	mockCwClient.EXPECT().QueryIsEnabled(gomock.Any()).Return(true, nil).Times(1)
+	mockCwClient.EXPECT().QueryConsumerId(gomock.Any()).Return(consumerChainID, nil).Times(1)
+
+	mockBTCClient := mocks.NewMockIBitcoinClient(ctl)
+	mockBTCClient.EXPECT().
+		GetBlockHeightByTimestamp(gomock.Any(), block.BlockTimestamp).
+		Return(uint64(0), fmt.Errorf("%w: timestamp %d", btcclient.ErrTimestampAheadOfBtcTip, block.BlockTimestamp)).
+		Times(1)
+
+	mockBBNClient := mocks.NewMockIBabylonClient(ctl)
+	mockBBNClient.EXPECT().QueryAllFpBtcPubKeys(gomock.Any(), consumerChainID).Return(allFpPks, nil).Times(1)
+	mockBBNClient.EXPECT().QueryEarliestActiveDelBtcHeight(gomock.Any(), allFpPks).Return(uint64(100), nil).Times(1)
+
+	mockSdkClient := &SdkClient{
+		cwClient:          mockCwClient,
+		bbnClient:         mockBBNClient,
+		btcClient:         mockBTCClient,
+		quorumNumerator:   sdkconfig.DefaultQuorumNumerator,
+		quorumDenominator: sdkconfig.DefaultQuorumDenominator,
+	}
+
+	// the caller can tell waiting for BTC blocks apart from BTC staking not being activated
+	res, err := mockSdkClient.QueryIsBlockBabylonFinalized(context.Background(), block)
+	require.False(t, res)
+	require.ErrorIs(t, err, ErrTimestampAheadOfBtcTip)
+	require.NotErrorIs(t, err, ErrBtcStakingNotActivated)
+}
+
 func TestQueryBlockRangeBabylonFinalized(t *testing.T) {
 	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
 
@@ -220,45 +315,190 @@ func TestQueryBlockRangeBabylonFinalized(t *testing.T) {
 		{"none of the block is finalized and the second block has error", nil, nil, []*cwclient.L2Block{&blockF, &blockG}},
 	}
 
+	// the strategies agree on the test cases, as finality is monotone along the ranges
 	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			ctl := gomock.NewController(t)
-			defer ctl.Finish()
+		for _, rangeSearch := range []string{sdkconfig.RangeSearchLinear, sdkconfig.RangeSearchBisect} {
+			t.Run(tc.name+"/"+rangeSearch, func(t *testing.T) {
+				ctl := gomock.NewController(t)
+				defer ctl.Finish()
 
-			mockCwClient := mocks.NewMockICosmWasmClient(ctl)
-			mockBTCClient := mocks.NewMockIBitcoinClient(ctl)
-			mockBBNClient := mocks.NewMockIBabylonClient(ctl)
-			mockSdkClient := &SdkClient{
-				cwClient:  mockCwClient,
-				bbnClient: mockBBNClient,
-				btcClient: mockBTCClient,
-			}
+				mockCwClient := mocks.NewMockICosmWasmClient(ctl)
+				mockBTCClient := mocks.NewMockIBitcoinClient(ctl)
+				mockBBNClient := mocks.NewMockIBabylonClient(ctl)
+				mockSdkClient := &SdkClient{
+					cwClient:          mockCwClient,
+					bbnClient:         mockBBNClient,
+					btcClient:         mockBTCClient,
+					quorumNumerator:   sdkconfig.DefaultQuorumNumerator,
+					quorumDenominator: sdkconfig.DefaultQuorumDenominator,
+					rangeSearch:       rangeSearch,
+				}
 
-			mockCwClient.EXPECT().QueryIsEnabled().Return(true, nil).AnyTimes()
-			mockCwClient.EXPECT().QueryConsumerId().Return("consumer-chain-id", nil).AnyTimes()
-			mockCwClient.EXPECT().QueryListOfVotedFinalityProviders(&blockAWithHashTrimmed).Return([]string{"pk1", "pk2", "pk3"}, nil).AnyTimes()
-			mockCwClient.EXPECT().QueryListOfVotedFinalityProviders(&blockBWithHashTrimmed).Return([]string{"pk1", "pk2", "pk3"}, nil).AnyTimes()
-			mockCwClient.EXPECT().QueryListOfVotedFinalityProviders(&blockCWithHashTrimmed).Return([]string{"pk1", "pk2", "pk3"}, nil).AnyTimes()
-			mockCwClient.EXPECT().QueryListOfVotedFinalityProviders(&blockDWithHashTrimmed).Return([]string{"pk3"}, nil).AnyTimes()
-			mockCwClient.EXPECT().QueryListOfVotedFinalityProviders(&blockEWithHashTrimmed).Return([]string{"pk1"}, nil).AnyTimes()
-			mockCwClient.EXPECT().QueryListOfVotedFinalityProviders(&blockFWithHashTrimmed).Return([]string{"pk2"}, nil).AnyTimes()
-			mockCwClient.EXPECT().QueryListOfVotedFinalityProviders(&blockGWithHashTrimmed).Return([]string{"pk3"}, nil).AnyTimes()
-
-			mockBTCClient.EXPECT().GetBlockHeightByTimestamp(blockA.BlockTimestamp).Return(uint64(111), nil).AnyTimes()
-			mockBTCClient.EXPECT().GetBlockHeightByTimestamp(blockB.BlockTimestamp).Return(uint64(111), nil).AnyTimes()
-			mockBTCClient.EXPECT().GetBlockHeightByTimestamp(blockC.BlockTimestamp).Return(uint64(111), fmt.Errorf("RPC rate limit error")).AnyTimes()
-			mockBTCClient.EXPECT().GetBlockHeightByTimestamp(blockD.BlockTimestamp).Return(uint64(112), fmt.Errorf("RPC rate limit error")).AnyTimes()
-			mockBTCClient.EXPECT().GetBlockHeightByTimestamp(blockE.BlockTimestamp).Return(uint64(112), nil).AnyTimes()
-			mockBTCClient.EXPECT().GetBlockHeightByTimestamp(blockF.BlockTimestamp).Return(uint64(113), nil).AnyTimes()
-			mockBTCClient.EXPECT().GetBlockHeightByTimestamp(blockG.BlockTimestamp).Return(uint64(113), fmt.Errorf("RPC rate limit error")).AnyTimes()
-
-			mockBBNClient.EXPECT().QueryEarliestActiveDelBtcHeight(gomock.Any()).Return(uint64(1), nil).AnyTimes()
-			mockBBNClient.EXPECT().QueryAllFpBtcPubKeys("consumer-chain-id").Return([]string{"pk1", "pk2", "pk3"}, nil).AnyTimes()
-			mockBBNClient.EXPECT().QueryMultiFpPower([]string{"pk1", "pk2", "pk3"}, gomock.Any()).Return(map[string]uint64{"pk1": 100, "pk2": 200, "pk3": 300}, nil).AnyTimes()
-
-			res, err := mockSdkClient.QueryBlockRangeBabylonFinalized(tc.queryBlocks)
-			require.Equal(t, tc.expectResult, res)
-			require.Equal(t, tc.expectedErr, err)
-		})
+				mockCwClient.EXPECT().QueryIsEnabled(gomock.Any()).Return(true, nil).AnyTimes()
+				mockCwClient.EXPECT().QueryConsumerId(gomock.Any()).Return("consumer-chain-id", nil).AnyTimes()
+				mockCwClient.EXPECT().QueryListOfVotedFinalityProviders(gomock.Any(), &blockAWithHashTrimmed).Return([]string{"pk1", "pk2", "pk3"}, nil).AnyTimes()
+				mockCwClient.EXPECT().QueryListOfVotedFinalityProviders(gomock.Any(), &blockBWithHashTrimmed).Return([]string{"pk1", "pk2", "pk3"}, nil).AnyTimes()
+				mockCwClient.EXPECT().QueryListOfVotedFinalityProviders(gomock.Any(), &blockCWithHashTrimmed).Return([]string{"pk1", "pk2", "pk3"}, nil).AnyTimes()
+				mockCwClient.EXPECT().QueryListOfVotedFinalityProviders(gomock.Any(), &blockDWithHashTrimmed).Return([]string{"pk3"}, nil).AnyTimes()
+				mockCwClient.EXPECT().QueryListOfVotedFinalityProviders(gomock.Any(), &blockEWithHashTrimmed).Return([]string{"pk1"}, nil).AnyTimes()
+				mockCwClient.EXPECT().QueryListOfVotedFinalityProviders(gomock.Any(), &blockFWithHashTrimmed).Return([]string{"pk2"}, nil).AnyTimes()
+				mockCwClient.EXPECT().QueryListOfVotedFinalityProviders(gomock.Any(), &blockGWithHashTrimmed).Return([]string{"pk3"}, nil).AnyTimes()
+
+				mockBTCClient.EXPECT().GetBlockHeightByTimestamp(gomock.Any(), blockA.BlockTimestamp).Return(uint64(111), nil).AnyTimes()
+				mockBTCClient.EXPECT().GetBlockHeightByTimestamp(gomock.Any(), blockB.BlockTimestamp).Return(uint64(111), nil).AnyTimes()
+				mockBTCClient.EXPECT().GetBlockHeightByTimestamp(gomock.Any(), blockC.BlockTimestamp).Return(uint64(111), fmt.Errorf("RPC rate limit error")).AnyTimes()
+				mockBTCClient.EXPECT().GetBlockHeightByTimestamp(gomock.Any(), blockD.BlockTimestamp).Return(uint64(112), fmt.Errorf("RPC rate limit error")).AnyTimes()
+				mockBTCClient.EXPECT().GetBlockHeightByTimestamp(gomock.Any(), blockE.BlockTimestamp).Return(uint64(112), nil).AnyTimes()
+				mockBTCClient.EXPECT().GetBlockHeightByTimestamp(gomock.Any(), blockF.BlockTimestamp).Return(uint64(113), nil).AnyTimes()
+				mockBTCClient.EXPECT().GetBlockHeightByTimestamp(gomock.Any(), blockG.BlockTimestamp).Return(uint64(113), fmt.Errorf("RPC rate limit error")).AnyTimes()
+
+				mockBBNClient.EXPECT().QueryEarliestActiveDelBtcHeight(gomock.Any(), gomock.Any()).Return(uint64(1), nil).AnyTimes()
+				mockBBNClient.EXPECT().QueryAllFpBtcPubKeys(gomock.Any(), "consumer-chain-id").Return([]string{"pk1", "pk2", "pk3"}, nil).AnyTimes()
+				mockBBNClient.EXPECT().QueryMultiFpPower(gomock.Any(), "consumer-chain-id", []string{"pk1", "pk2", "pk3"}, gomock.Any()).Return(map[string]uint64{"pk1": 100, "pk2": 200, "pk3": 300}, nil).AnyTimes()
+
+				res, err := mockSdkClient.QueryBlockRangeBabylonFinalized(context.Background(), tc.queryBlocks)
+				require.Equal(t, tc.expectResult, res)
+				require.Equal(t, tc.expectedErr, err)
+			})
+		}
+	}
+}
+
+func TestQueryBlockRangeBabylonFinalizedSharesLookups(t *testing.T) {
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	l2BlockTime := uint64(2)
+	blockA, blockAWithHashTrimmed := testutil.RandomL2Block(rng)
+	blockB, blockBWithHashTrimmed := testutil.GenL2Block(rng, &blockA, l2BlockTime, 1)
+	blockC, blockCWithHashTrimmed := testutil.GenL2Block(rng, &blockB, l2BlockTime, 1)
+	blockD, blockDWithHashTrimmed := testutil.GenL2Block(rng, &blockC, l2BlockTime, 1)
+	blockE, blockEWithHashTrimmed := testutil.GenL2Block(rng, &blockD, l2BlockTime, 1)
+	blockF, _ := testutil.GenL2Block(rng, &blockE, l2BlockTime, 1)
+
+	const consumerChainID = "consumer-chain-id"
+	allFpPks := []string{"pk1", "pk2", "pk3"}
+	fpPowers := map[string]uint64{"pk1": 100, "pk2": 200, "pk3": 300}
+
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	// the lookups that do not depend on the L2 block are queried once for the whole range
+	mockCwClient := mocks.NewMockICosmWasmClient(ctl)
+	mockCwClient.EXPECT().QueryIsEnabled(gomock.Any()).Return(true, nil).Times(1)
+	mockCwClient.EXPECT().QueryConsumerId(gomock.Any()).Return(consumerChainID, nil).Times(1)
+
+	mockBBNClient := mocks.NewMockIBabylonClient(ctl)
+	mockBBNClient.EXPECT().QueryAllFpBtcPubKeys(gomock.Any(), consumerChainID).Return(allFpPks, nil).Times(1)
+	mockBBNClient.EXPECT().QueryEarliestActiveDelBtcHeight(gomock.Any(), allFpPks).Return(uint64(1), nil).Times(1)
+
+	// blocks A, B and C resolve to the same BTC height and share the voting power query
+	mockBTCClient := mocks.NewMockIBitcoinClient(ctl)
+	mockBTCClient.EXPECT().GetBlockHeightByTimestamp(gomock.Any(), blockA.BlockTimestamp).Return(uint64(111), nil).Times(1)
+	mockBTCClient.EXPECT().GetBlockHeightByTimestamp(gomock.Any(), blockB.BlockTimestamp).Return(uint64(111), nil).Times(1)
+	mockBTCClient.EXPECT().GetBlockHeightByTimestamp(gomock.Any(), blockC.BlockTimestamp).Return(uint64(111), nil).Times(1)
+	mockBTCClient.EXPECT().GetBlockHeightByTimestamp(gomock.Any(), blockD.BlockTimestamp).Return(uint64(112), nil).Times(1)
+	mockBTCClient.EXPECT().GetBlockHeightByTimestamp(gomock.Any(), blockE.BlockTimestamp).Return(uint64(112), nil).Times(1)
+	mockBBNClient.EXPECT().QueryMultiFpPower(gomock.Any(), consumerChainID, allFpPks, uint64(111)).Return(fpPowers, nil).Times(1)
+	mockBBNClient.EXPECT().QueryMultiFpPower(gomock.Any(), consumerChainID, allFpPks, uint64(112)).Return(fpPowers, nil).Times(1)
+
+	// the voted FPs are queried per block, until the first block that is not finalized
+	mockCwClient.EXPECT().QueryListOfVotedFinalityProviders(gomock.Any(), &blockAWithHashTrimmed).Return(allFpPks, nil).Times(1)
+	mockCwClient.EXPECT().QueryListOfVotedFinalityProviders(gomock.Any(), &blockBWithHashTrimmed).Return(allFpPks, nil).Times(1)
+	mockCwClient.EXPECT().QueryListOfVotedFinalityProviders(gomock.Any(), &blockCWithHashTrimmed).Return(allFpPks, nil).Times(1)
+	mockCwClient.EXPECT().QueryListOfVotedFinalityProviders(gomock.Any(), &blockDWithHashTrimmed).Return(allFpPks, nil).Times(1)
+	mockCwClient.EXPECT().QueryListOfVotedFinalityProviders(gomock.Any(), &blockEWithHashTrimmed).Return([]string{"pk1"}, nil).Times(1)
+
+	mockSdkClient := &SdkClient{
+		cwClient:          mockCwClient,
+		bbnClient:         mockBBNClient,
+		btcClient:         mockBTCClient,
+		quorumNumerator:   sdkconfig.DefaultQuorumNumerator,
+		quorumDenominator: sdkconfig.DefaultQuorumDenominator,
+	}
+
+	res, err := mockSdkClient.QueryBlockRangeBabylonFinalized(
+		context.Background(),
+		[]*cwclient.L2Block{&blockA, &blockB, &blockC, &blockD, &blockE, &blockF},
+	)
+	require.NoError(t, err)
+	require.Equal(t, &blockD.BlockHeight, res)
+}
+
+func TestQueryBlockFinalityResult(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	block := cwclient.L2Block{
+		BlockHash:      "0xd4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3",
+		BlockHeight:    123,
+		BlockTimestamp: 12345,
 	}
+	blockWithHashTrimmed := block
+	blockWithHashTrimmed.BlockHash = strings.TrimPrefix(block.BlockHash, "0x")
+
+	const consumerChainID = "consumer-chain-id"
+	const BTCHeight = uint64(111)
+	allFpPks := []string{"pk1", "pk2", "pk3"}
+
+	mockCwClient := mocks.NewMockICosmWasmClient(ctl)
+	mockCwClient.EXPECT().QueryIsEnabled(gomock.Any()).Return(true, nil).Times(1)
+	mockCwClient.EXPECT().QueryConsumerId(gomock.Any()).Return(consumerChainID, nil).Times(1)
+	mockCwClient.EXPECT().
+		QueryListOfVotedFinalityProviders(gomock.Any(), &blockWithHashTrimmed).
+		Return([]string{"pk2", "pk3"}, nil).
+		Times(1)
+	// the evidence queries are in flight at the same time, each one waiting for the others
+	var inFlight sync.WaitGroup
+	inFlight.Add(len(allFpPks))
+	evidences := map[string]*cwclient.Evidence{
+		"pk1": {FpBtcPkHex: "pk1", BlockHeight: block.BlockHeight},
+		"pk3": {FpBtcPkHex: "pk3", BlockHeight: block.BlockHeight},
+	}
+	mockCwClient.EXPECT().
+		QueryEvidence(gomock.Any(), gomock.Any(), block.BlockHeight).
+		DoAndReturn(func(_ context.Context, fpPubkeyHex string, _ uint64) (*cwclient.Evidence, error) {
+			inFlight.Done()
+			inFlight.Wait()
+			return evidences[fpPubkeyHex], nil
+		}).
+		Times(len(allFpPks))
+
+	mockBTCClient := mocks.NewMockIBitcoinClient(ctl)
+	mockBTCClient.EXPECT().GetBlockHeightByTimestamp(gomock.Any(), block.BlockTimestamp).Return(BTCHeight, nil).Times(1)
+
+	mockBBNClient := mocks.NewMockIBabylonClient(ctl)
+	mockBBNClient.EXPECT().QueryAllFpBtcPubKeys(gomock.Any(), consumerChainID).Return(allFpPks, nil).Times(1)
+	mockBBNClient.EXPECT().QueryEarliestActiveDelBtcHeight(gomock.Any(), allFpPks).Return(BTCHeight-1, nil).Times(1)
+	mockBBNClient.EXPECT().
+		QueryMultiFpPower(gomock.Any(), consumerChainID, allFpPks, BTCHeight).
+		Return(map[string]uint64{"pk1": 100, "pk2": 300, "pk3": 200}, nil).
+		Times(1)
+
+	mockSdkClient := &SdkClient{
+		cwClient:          mockCwClient,
+		bbnClient:         mockBBNClient,
+		btcClient:         mockBTCClient,
+		quorumNumerator:   sdkconfig.DefaultQuorumNumerator,
+		quorumDenominator: sdkconfig.DefaultQuorumDenominator,
+		maxConcurrency:    len(allFpPks),
+	}
+
+	res, err := mockSdkClient.QueryBlockFinalityResult(context.Background(), block)
+	require.NoError(t, err)
+	require.Equal(t, &finality.Result{
+		Enabled:    true,
+		Finalized:  true,
+		BtcHeight:  BTCHeight,
+		TotalPower: big.NewInt(600),
+		VotedPower: big.NewInt(500),
+		FpVotes: []finality.FpVote{
+			{FpBtcPkHex: "pk1", Power: 100, Voted: false},
+			{FpBtcPkHex: "pk2", Power: 300, Voted: true},
+			{FpBtcPkHex: "pk3", Power: 200, Voted: true},
+		},
+		// in the order of the FPs
+		ConflictingFps:    []string{"pk1", "pk3"},
+		QuorumNumerator:   2,
+		QuorumDenominator: 3,
+	}, res)
 }
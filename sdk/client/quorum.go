@@ -0,0 +1,26 @@
+package client
+
+import "math/big"
+
+// addPower adds the voting power of an FP to the accumulated voting power in place
+//
+// voting power is accumulated as a big integer, as the sum of satoshi amounts of all FPs
+// can overflow uint64 for large stake totals and silently flip the finality decision
+func addPower(accumulated *big.Int, power uint64) {
+	accumulated.Add(accumulated, new(big.Int).SetUint64(power))
+}
+
+// isQuorumReached checks votedPower / totalPower >= numerator / denominator, or votedPower / totalPower > 1/2 if the
+// threshold is at most 1/2, see config.GetQuorum
+//
+// the comparison is done as votedPower * denominator >= totalPower * numerator with big integers,
+// as the products can overflow uint64 for large stake totals
+func isQuorumReached(votedPower, totalPower *big.Int, numerator, denominator uint64) bool {
+	lhs := new(big.Int).Mul(votedPower, new(big.Int).SetUint64(denominator))
+	rhs := new(big.Int).Mul(totalPower, new(big.Int).SetUint64(numerator))
+	// numerator/denominator <= 1/2, compared without overflowing uint64
+	if numerator <= denominator-numerator {
+		return lhs.Cmp(rhs) > 0
+	}
+	return lhs.Cmp(rhs) >= 0
+}
@@ -0,0 +1,152 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/babylonchain/babylon-finality-gadget/sdk/cwclient"
+	"github.com/babylonchain/babylon-finality-gadget/testutil/mocks"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"pgregory.net/rapid"
+)
+
+func TestIsQuorumReached(t *testing.T) {
+	testCases := []struct {
+		name        string
+		votedPower  uint64
+		totalPower  uint64
+		numerator   uint64
+		denominator uint64
+		expected    bool
+	}{
+		{"exact 2/3", 200, 300, 2, 3, true},
+		{"just below 2/3", 199, 300, 2, 3, false},
+		{"majority reached", 51, 100, 51, 100, true},
+		{"majority not reached", 50, 100, 51, 100, false},
+		{"simple majority reached", 51, 100, 1, 2, true},
+		{"50/50 split is not a simple majority", 50, 100, 1, 2, false},
+		{"50/50 split is not a simple majority, unreduced", 50, 100, 50, 100, false},
+		{"simple majority of odd power reached", 51, 101, 1, 2, true},
+		{"exact 3/4", 300, 400, 3, 4, true},
+		{"just below 3/4", 299, 400, 3, 4, false},
+		{"unanimity", 100, 100, 1, 1, true},
+		// votedPower*3 and totalPower*2 both overflow uint64
+		{"2/3 of max uint64 reached", math.MaxUint64 / 3 * 2, math.MaxUint64 / 3 * 3, 2, 3, true},
+		{"2/3 of max uint64 not reached", math.MaxUint64/3*2 - 1, math.MaxUint64 / 3 * 3, 2, 3, false},
+		{"max uint64 power with max uint64 threshold", math.MaxUint64 - 1, math.MaxUint64, math.MaxUint64 - 1, math.MaxUint64, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			votedPower := new(big.Int).SetUint64(tc.votedPower)
+			totalPower := new(big.Int).SetUint64(tc.totalPower)
+			require.Equal(t, tc.expected, isQuorumReached(votedPower, totalPower, tc.numerator, tc.denominator))
+		})
+	}
+}
+
+// powerGen generates voting powers biased towards the extreme values of uint64
+var powerGen = rapid.OneOf(
+	rapid.Uint64(),
+	rapid.Uint64Range(math.MaxUint64-1000, math.MaxUint64),
+	rapid.Uint64Range(0, 1000),
+)
+
+func TestPropIsQuorumReachedMatchesRationalComparison(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		totalPower := new(big.Int)
+		for _, power := range rapid.SliceOfN(powerGen, 1, 20).Draw(t, "powers") {
+			addPower(totalPower, power)
+		}
+		if totalPower.Sign() == 0 {
+			t.Skip("total power must be positive")
+		}
+		// voted power is any fraction of the total power
+		votedPercent := rapid.Int64Range(0, 100).Draw(t, "votedPercent")
+		votedPower := new(big.Int).Div(new(big.Int).Mul(totalPower, big.NewInt(votedPercent)), big.NewInt(100))
+		denominator := rapid.Uint64Range(1, math.MaxUint64).Draw(t, "denominator")
+		numerator := rapid.Uint64Range(0, denominator).Draw(t, "numerator")
+
+		threshold := new(big.Rat).SetFrac(new(big.Int).SetUint64(numerator), new(big.Int).SetUint64(denominator))
+		cmp := new(big.Rat).SetFrac(votedPower, totalPower).Cmp(threshold)
+		// a threshold of at most 1/2 requires strictly more than half of the power
+		expected := cmp >= 0
+		if threshold.Cmp(big.NewRat(1, 2)) <= 0 {
+			expected = cmp > 0
+		}
+		require.Equal(t, expected, isQuorumReached(votedPower, totalPower, numerator, denominator))
+	})
+}
+
+func TestPropIsQuorumReachedIsMonotone(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		totalPower := new(big.Int).SetUint64(powerGen.Draw(t, "totalPower"))
+		addPower(totalPower, powerGen.Draw(t, "extraPower"))
+		votedPower := new(big.Int).SetUint64(powerGen.Draw(t, "votedPower"))
+		morePower := new(big.Int).Set(votedPower)
+		addPower(morePower, powerGen.Draw(t, "morePower"))
+
+		// more voted power never turns a finalized block back into a non-finalized one
+		if isQuorumReached(votedPower, totalPower, 2, 3) {
+			require.True(t, isQuorumReached(morePower, totalPower, 2, 3))
+		}
+	})
+}
+
+func TestPropFinalityResultWithExtremePowers(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		ctl := gomock.NewController(t)
+		defer ctl.Finish()
+
+		numFps := rapid.IntRange(1, 10).Draw(t, "numFps")
+		allFpPks := make([]string, numFps)
+		fpPowers := make(map[string]uint64, numFps)
+		var votedFpPks []string
+		expectedTotal, expectedVoted := new(big.Int), new(big.Int)
+		for i := 0; i < numFps; i++ {
+			fpPk := fmt.Sprintf("pk%d", i)
+			power := powerGen.Draw(t, fpPk)
+			allFpPks[i] = fpPk
+			fpPowers[fpPk] = power
+			expectedTotal.Add(expectedTotal, new(big.Int).SetUint64(power))
+			if rapid.Bool().Draw(t, fpPk+"Voted") {
+				votedFpPks = append(votedFpPks, fpPk)
+				expectedVoted.Add(expectedVoted, new(big.Int).SetUint64(power))
+			}
+		}
+		if expectedTotal.Sign() == 0 {
+			t.Skip("total power must be positive")
+		}
+
+		mockCwClient := mocks.NewMockICosmWasmClient(ctl)
+		mockCwClient.EXPECT().QueryIsEnabled(gomock.Any()).Return(true, nil).AnyTimes()
+		mockCwClient.EXPECT().QueryConsumerId(gomock.Any()).Return("consumer-chain-id", nil).AnyTimes()
+		mockCwClient.EXPECT().QueryListOfVotedFinalityProviders(gomock.Any(), gomock.Any()).Return(votedFpPks, nil).AnyTimes()
+		mockBTCClient := mocks.NewMockIBitcoinClient(ctl)
+		mockBTCClient.EXPECT().GetBlockHeightByTimestamp(gomock.Any(), gomock.Any()).Return(uint64(111), nil).AnyTimes()
+		mockBBNClient := mocks.NewMockIBabylonClient(ctl)
+		mockBBNClient.EXPECT().QueryAllFpBtcPubKeys(gomock.Any(), gomock.Any()).Return(allFpPks, nil).AnyTimes()
+		mockBBNClient.EXPECT().QueryEarliestActiveDelBtcHeight(gomock.Any(), gomock.Any()).Return(uint64(1), nil).AnyTimes()
+		mockBBNClient.EXPECT().QueryMultiFpPower(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fpPowers, nil).AnyTimes()
+
+		mockSdkClient := &SdkClient{
+			cwClient:          mockCwClient,
+			bbnClient:         mockBBNClient,
+			btcClient:         mockBTCClient,
+			quorumNumerator:   2,
+			quorumDenominator: 3,
+		}
+
+		res, err := mockSdkClient.queryFinalityResult(context.Background(), cwclient.L2Block{})
+		require.NoError(t, err)
+		require.Zero(t, expectedTotal.Cmp(res.TotalPower))
+		require.Zero(t, expectedVoted.Cmp(res.VotedPower))
+		// voted power >= 2/3 of the total voting power, i.e. 3 * voted >= 2 * total
+		expectedFinalized := new(big.Int).Mul(expectedVoted, big.NewInt(3)).Cmp(new(big.Int).Mul(expectedTotal, big.NewInt(2))) >= 0
+		require.Equal(t, expectedFinalized, res.Finalized)
+	})
+}
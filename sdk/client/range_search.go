@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+
+	"github.com/babylonchain/babylon-finality-gadget/sdk/cwclient"
+)
+
+// searchLinear checks the blocks from low to high, and returns the height of the last block of the row of
+// consecutive finalized blocks at the start of the range
+//
+// on error, it returns the height of the last finalized block checked before the error
+func (evaluator *finalityEvaluator) searchLinear(
+	ctx context.Context,
+	queryBlocks []*cwclient.L2Block,
+) (*uint64, error) {
+	var finalizedBlockHeight *uint64
+	for _, block := range queryBlocks {
+		result, err := evaluator.evaluate(ctx, *block)
+		if err != nil {
+			return finalizedBlockHeight, err
+		}
+		if !result.Finalized {
+			break
+		}
+		finalizedBlockHeight = &block.BlockHeight
+	}
+	return finalizedBlockHeight, nil
+}
+
+// searchBisect bisects the blocks to find the last finalized block, assuming that no block is finalized after
+// a block that is not finalized
+//
+// on error, it returns the height of the highest block verified as finalized before the error
+func (evaluator *finalityEvaluator) searchBisect(
+	ctx context.Context,
+	queryBlocks []*cwclient.L2Block,
+) (*uint64, error) {
+	// invariant: queryBlocks[lo] is finalized (or lo == -1), and queryBlocks[hi] is not (or hi == len)
+	lo, hi := -1, len(queryBlocks)
+	var finalizedBlockHeight *uint64
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		result, err := evaluator.evaluate(ctx, *queryBlocks[mid])
+		if err != nil {
+			return finalizedBlockHeight, err
+		}
+		if result.Finalized {
+			lo = mid
+			finalizedBlockHeight = &queryBlocks[mid].BlockHeight
+		} else {
+			hi = mid
+		}
+	}
+	return finalizedBlockHeight, nil
+}
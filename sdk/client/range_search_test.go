@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+	"math/bits"
+	"testing"
+
+	sdkconfig "github.com/babylonchain/babylon-finality-gadget/sdk/config"
+	"github.com/babylonchain/babylon-finality-gadget/sdk/cwclient"
+	"github.com/babylonchain/babylon-finality-gadget/testutil/mocks"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"pgregory.net/rapid"
+)
+
+// newRangeSearchTestClient returns a client where the blocks below finalizedBelow are finalized, and counts the
+// number of blocks checked
+func newRangeSearchTestClient(
+	t gomock.TestReporter,
+	rangeSearch string,
+	finalizedBelow uint64,
+	checkedBlocks *int,
+) *SdkClient {
+	ctl := gomock.NewController(t)
+	allFpPks := []string{"pk1", "pk2", "pk3"}
+
+	mockCwClient := mocks.NewMockICosmWasmClient(ctl)
+	mockCwClient.EXPECT().QueryIsEnabled(gomock.Any()).Return(true, nil).Times(1)
+	mockCwClient.EXPECT().QueryConsumerId(gomock.Any()).Return("consumer-chain-id", nil).Times(1)
+	mockCwClient.EXPECT().
+		QueryListOfVotedFinalityProviders(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, block *cwclient.L2Block) ([]string, error) {
+			*checkedBlocks++
+			if block.BlockHeight < finalizedBelow {
+				return allFpPks, nil
+			}
+			return nil, nil
+		}).
+		AnyTimes()
+	mockBTCClient := mocks.NewMockIBitcoinClient(ctl)
+	mockBTCClient.EXPECT().GetBlockHeightByTimestamp(gomock.Any(), gomock.Any()).Return(uint64(111), nil).AnyTimes()
+	mockBBNClient := mocks.NewMockIBabylonClient(ctl)
+	mockBBNClient.EXPECT().QueryAllFpBtcPubKeys(gomock.Any(), gomock.Any()).Return(allFpPks, nil).Times(1)
+	mockBBNClient.EXPECT().QueryEarliestActiveDelBtcHeight(gomock.Any(), gomock.Any()).Return(uint64(1), nil).Times(1)
+	mockBBNClient.EXPECT().
+		QueryMultiFpPower(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(map[string]uint64{"pk1": 100, "pk2": 200, "pk3": 300}, nil).
+		MaxTimes(1)
+
+	return &SdkClient{
+		cwClient:          mockCwClient,
+		bbnClient:         mockBBNClient,
+		btcClient:         mockBTCClient,
+		quorumNumerator:   sdkconfig.DefaultQuorumNumerator,
+		quorumDenominator: sdkconfig.DefaultQuorumDenominator,
+		rangeSearch:       rangeSearch,
+	}
+}
+
+func genConsecutiveBlocks(startHeight uint64, numBlocks int) []*cwclient.L2Block {
+	queryBlocks := make([]*cwclient.L2Block, numBlocks)
+	for i := range queryBlocks {
+		queryBlocks[i] = &cwclient.L2Block{
+			BlockHeight:    startHeight + uint64(i),
+			BlockHash:      "0xd4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3",
+			BlockTimestamp: 1_700_000_000 + 2*uint64(i),
+		}
+	}
+	return queryBlocks
+}
+
+func TestPropBisectRangeSearchAgreesWithLinearScan(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		numBlocks := rapid.IntRange(1, 5000).Draw(t, "numBlocks")
+		startHeight := rapid.Uint64Range(0, 1_000_000).Draw(t, "startHeight")
+		numFinalized := rapid.IntRange(0, numBlocks).Draw(t, "numFinalized")
+		finalizedBelow := startHeight + uint64(numFinalized)
+		queryBlocks := genConsecutiveBlocks(startHeight, numBlocks)
+
+		var linearChecks, bisectChecks int
+		linearClient := newRangeSearchTestClient(t, sdkconfig.RangeSearchLinear, finalizedBelow, &linearChecks)
+		bisectClient := newRangeSearchTestClient(t, sdkconfig.RangeSearchBisect, finalizedBelow, &bisectChecks)
+
+		linearRes, err := linearClient.QueryBlockRangeBabylonFinalized(context.Background(), queryBlocks)
+		require.NoError(t, err)
+		bisectRes, err := bisectClient.QueryBlockRangeBabylonFinalized(context.Background(), queryBlocks)
+		require.NoError(t, err)
+
+		require.Equal(t, linearRes, bisectRes)
+		if numFinalized == 0 {
+			require.Nil(t, bisectRes)
+		} else {
+			require.Equal(t, finalizedBelow-1, *bisectRes)
+		}
+		// bisection checks at most ceil(log2(n+1)) blocks
+		require.LessOrEqual(t, bisectChecks, bits.Len(uint(numBlocks)))
+	})
+}
@@ -0,0 +1,44 @@
+package remote
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	defaultRequestTimeout = 10 * time.Second
+	defaultMaxRetryTimes  = 3
+	defaultRetryInterval  = 500 * time.Millisecond
+)
+
+// Config defines configuration for the clients of the finality gadget server
+type Config struct {
+	// RequestTimeout bounds each attempt of a query. Set to 0 to only honor the context of the caller
+	RequestTimeout time.Duration `mapstructure:"request-timeout"`
+	// MaxRetryTimes is the max number of attempts of a query that fails in transit or on the server side. The
+	// verdicts of the server, e.g. ErrBtcStakingNotActivated, are not retried
+	MaxRetryTimes uint `mapstructure:"max-retry-times"`
+	// RetryInterval is the initial time between the attempts of a query, backed off on each retry
+	RetryInterval time.Duration `mapstructure:"retry-interval"`
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		RequestTimeout: defaultRequestTimeout,
+		MaxRetryTimes:  defaultMaxRetryTimes,
+		RetryInterval:  defaultRetryInterval,
+	}
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.RequestTimeout < 0 {
+		return errors.New("the request timeout must not be negative")
+	}
+	if cfg.MaxRetryTimes == 0 {
+		return errors.New("the max retry times must be positive")
+	}
+	if cfg.RetryInterval < 0 {
+		return errors.New("the retry interval must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package remote
+
+import (
+	"context"
+
+	"github.com/babylonchain/babylon-finality-gadget/sdk/client"
+	"github.com/babylonchain/babylon-finality-gadget/server/api"
+)
+
+// sdkErrors are the SDK errors of the error codes of the server
+var sdkErrors = map[string]error{
+	api.ErrCodeTimestampAheadOfBtcTip: client.ErrTimestampAheadOfBtcTip,
+	api.ErrCodeTimestampBeforeGenesis: client.ErrTimestampBeforeGenesis,
+	api.ErrCodeBtcStakingNotActivated: client.ErrBtcStakingNotActivated,
+	api.ErrCodeNoFpHasVotingPower:     client.ErrNoFpHasVotingPower,
+	api.ErrCodeTrackerNotStarted:      client.ErrTrackerNotStarted,
+	api.ErrCodeTimeout:                context.DeadlineExceeded,
+	api.ErrCodeCanceled:               context.Canceled,
+}
+
+// Error is an error returned by the finality gadget server. It unwraps to the matching SDK error, if any, so that
+// errors.Is works as with the SDK client
+type Error struct {
+	// Code is the error code of the server, see the api.ErrCode constants. It is empty if the request failed
+	// before reaching the server
+	Code    string
+	Message string
+
+	err error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
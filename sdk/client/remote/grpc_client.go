@@ -0,0 +1,271 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/babylonchain/babylon-finality-gadget/proto"
+	"github.com/babylonchain/babylon-finality-gadget/sdk/client"
+	"github.com/babylonchain/babylon-finality-gadget/sdk/cwclient"
+	"github.com/babylonchain/babylon-finality-gadget/sdk/finality"
+)
+
+// GRPCClient queries a finality gadget server over gRPC. It implements client.ISdkClient and returns the same errors
+// as the SDK client, so that it can replace an embedded SDK client
+type GRPCClient struct {
+	conn   *grpc.ClientConn
+	client proto.FinalityGadgetClient
+	cfg    *Config
+	logger *zap.Logger
+}
+
+var _ client.ISdkClient = (*GRPCClient)(nil)
+
+// NewGRPCClient creates a client of the finality gadget gRPC server at the target, e.g. localhost:9090. Without dial
+// options, the connection is not encrypted
+func NewGRPCClient(target string, cfg *Config, logger *zap.Logger, opts ...grpc.DialOption) (*GRPCClient, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if len(opts) == 0 {
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the gRPC client of %s: %w", target, err)
+	}
+	return &GRPCClient{
+		conn:   conn,
+		client: proto.NewFinalityGadgetClient(conn),
+		cfg:    cfg,
+		logger: logger,
+	}, nil
+}
+
+// Close closes the connection to the server
+func (c *GRPCClient) Close() error {
+	return c.conn.Close()
+}
+
+func (c *GRPCClient) QueryIsBlockBabylonFinalized(ctx context.Context, queryParams cwclient.L2Block) (bool, error) {
+	req := &proto.QueryIsBlockBabylonFinalizedRequest{Block: toProtoBlock(&queryParams)}
+	var resp *proto.QueryIsBlockBabylonFinalizedResponse
+	err := callWithRetry(ctx, "QueryIsBlockBabylonFinalized", func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.QueryIsBlockBabylonFinalized(ctx, req)
+		return fromStatusError(err)
+	}, c.logger, c.cfg)
+	if err != nil {
+		return false, err
+	}
+	return resp.IsFinalized, nil
+}
+
+func (c *GRPCClient) QueryBlockFinalityResult(
+	ctx context.Context,
+	queryParams cwclient.L2Block,
+) (*finality.Result, error) {
+	req := &proto.QueryBlockFinalityResultRequest{Block: toProtoBlock(&queryParams)}
+	var resp *proto.QueryBlockFinalityResultResponse
+	err := callWithRetry(ctx, "QueryBlockFinalityResult", func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.QueryBlockFinalityResult(ctx, req)
+		return fromStatusError(err)
+	}, c.logger, c.cfg)
+	if err != nil {
+		return nil, err
+	}
+	return fromProtoFinalityResult(resp)
+}
+
+func (c *GRPCClient) QueryBlockRangeBabylonFinalized(
+	ctx context.Context,
+	queryBlocks []*cwclient.L2Block,
+) (*uint64, error) {
+	req := &proto.QueryBlockRangeBabylonFinalizedRequest{Blocks: make([]*proto.Block, len(queryBlocks))}
+	for i, block := range queryBlocks {
+		req.Blocks[i] = toProtoBlock(block)
+	}
+	var lastFinalizedHeight *uint64
+	err := callWithRetry(ctx, "QueryBlockRangeBabylonFinalized", func(ctx context.Context) error {
+		resp, err := c.client.QueryBlockRangeBabylonFinalized(ctx, req)
+		if err != nil {
+			// the server returns the last finalized block found before the failure with the error
+			lastFinalizedHeight = nil
+			if detail := errorDetail(err); detail != nil {
+				lastFinalizedHeight = detail.LastFinalizedBlockHeight
+			}
+			return fromStatusError(err)
+		}
+		lastFinalizedHeight = resp.LastFinalizedBlockHeight
+		return nil
+	}, c.logger, c.cfg)
+	return lastFinalizedHeight, err
+}
+
+func (c *GRPCClient) QueryBtcStakingActivatedTimestamp(ctx context.Context) (uint64, error) {
+	var resp *proto.QueryBtcStakingActivatedTimestampResponse
+	err := callWithRetry(ctx, "QueryBtcStakingActivatedTimestamp", func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.QueryBtcStakingActivatedTimestamp(ctx, &proto.QueryBtcStakingActivatedTimestampRequest{})
+		return fromStatusError(err)
+	}, c.logger, c.cfg)
+	if err != nil {
+		return math.MaxUint64, err
+	}
+	return resp.ActivatedTimestamp, nil
+}
+
+// Subscribe streams the last finalized L2 block tracked by the server whenever it advances, starting with the
+// current one, as client.SdkClient.Subscribe does. The channel is closed once the context is done or the stream ends,
+// e.g. the server stops, in which case the caller subscribes again
+//
+// the subscription is not retried, and a subscriber that does not keep up only misses intermediate events
+func (c *GRPCClient) Subscribe(ctx context.Context) (<-chan client.FinalityEvent, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := c.client.SubscribeFinalizedBlocks(ctx, &proto.SubscribeFinalizedBlocksRequest{})
+	if err != nil {
+		cancel()
+		return nil, fromStatusError(err)
+	}
+	// the server sends the headers once subscribed, otherwise the stream ends with the error of the subscription
+	if md, _ := stream.Header(); md == nil {
+		_, err := stream.Recv()
+		cancel()
+		if err == nil || errors.Is(err, io.EOF) {
+			return nil, errors.New("the stream of the finalized blocks ended before the subscription")
+		}
+		return nil, fromStatusError(err)
+	}
+
+	events := make(chan client.FinalityEvent, 1)
+	go func() {
+		defer cancel()
+		defer close(events)
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				if ctx.Err() == nil && !errors.Is(err, io.EOF) {
+					c.logger.Warn("The stream of the finalized blocks failed", zap.Error(err))
+				}
+				return
+			}
+			if resp.Block == nil {
+				continue
+			}
+			// only the latest event matters, so the pending event of a slow subscriber is replaced
+			select {
+			case <-events:
+			default:
+			}
+			events <- client.FinalityEvent{Block: *fromProtoBlock(resp.Block)}
+		}
+	}()
+	return events, nil
+}
+
+// fromStatusError maps a gRPC status error to the matching SDK error
+func fromStatusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	remoteErr := &Error{Message: st.Message()}
+	if detail := errorDetail(err); detail != nil {
+		remoteErr.Code = detail.Code
+		remoteErr.err = sdkErrors[detail.Code]
+		return remoteErr
+	}
+	// the status is not set by the server, e.g. the context of the request is done or the server is unreachable
+	switch st.Code() {
+	case codes.DeadlineExceeded:
+		remoteErr.err = context.DeadlineExceeded
+	case codes.Canceled:
+		remoteErr.err = context.Canceled
+	default:
+		return err
+	}
+	return remoteErr
+}
+
+// errorDetail returns the error detail set by the server to the status error, or nil
+func errorDetail(err error) *proto.ErrorDetail {
+	st, ok := status.FromError(err)
+	if !ok {
+		return nil
+	}
+	for _, detail := range st.Details() {
+		if errDetail, ok := detail.(*proto.ErrorDetail); ok {
+			return errDetail
+		}
+	}
+	return nil
+}
+
+func toProtoBlock(block *cwclient.L2Block) *proto.Block {
+	return &proto.Block{
+		BlockHeight:    block.BlockHeight,
+		BlockHash:      block.BlockHash,
+		BlockTimestamp: block.BlockTimestamp,
+	}
+}
+
+func fromProtoBlock(block *proto.Block) *cwclient.L2Block {
+	return &cwclient.L2Block{
+		BlockHeight:    block.BlockHeight,
+		BlockHash:      block.BlockHash,
+		BlockTimestamp: block.BlockTimestamp,
+	}
+}
+
+func fromProtoFinalityResult(resp *proto.QueryBlockFinalityResultResponse) (*finality.Result, error) {
+	result := &finality.Result{
+		Enabled:           resp.Enabled,
+		Finalized:         resp.Finalized,
+		BtcHeight:         resp.BtcHeight,
+		QuorumNumerator:   resp.QuorumNumerator,
+		QuorumDenominator: resp.QuorumDenominator,
+	}
+	// the voting power is unset if the finality gadget is disabled
+	var err error
+	if result.TotalPower, err = parsePower(resp.TotalPower); err != nil {
+		return nil, err
+	}
+	if result.VotedPower, err = parsePower(resp.VotedPower); err != nil {
+		return nil, err
+	}
+	if len(resp.FpVotes) > 0 {
+		result.FpVotes = make([]finality.FpVote, len(resp.FpVotes))
+		for i, vote := range resp.FpVotes {
+			result.FpVotes[i] = finality.FpVote{FpBtcPkHex: vote.FpBtcPkHex, Power: vote.Power, Voted: vote.Voted}
+		}
+	}
+	if len(resp.ConflictingFps) > 0 {
+		result.ConflictingFps = resp.ConflictingFps
+	}
+	return result, nil
+}
+
+func parsePower(power string) (*big.Int, error) {
+	if power == "" {
+		return nil, nil
+	}
+	value, ok := new(big.Int).SetString(power, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid voting power %q", power)
+	}
+	return value, nil
+}
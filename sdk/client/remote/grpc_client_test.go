@@ -0,0 +1,273 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/babylonchain/babylon-finality-gadget/sdk/client"
+	"github.com/babylonchain/babylon-finality-gadget/sdk/cwclient"
+	"github.com/babylonchain/babylon-finality-gadget/sdk/finality"
+	"github.com/babylonchain/babylon-finality-gadget/server"
+	"github.com/babylonchain/babylon-finality-gadget/server/api"
+	"github.com/babylonchain/babylon-finality-gadget/testutil/mocks"
+)
+
+func testConfig() *Config {
+	return &Config{
+		RequestTimeout: time.Second,
+		MaxRetryTimes:  3,
+		RetryInterval:  10 * time.Millisecond,
+	}
+}
+
+// newTestGRPCClient serves a gRPC server over a mock SDK client in-process, and returns a client connected to it
+func newTestGRPCClient(t *testing.T) (*mocks.MockISdkClient, *GRPCClient) {
+	ctl := gomock.NewController(t)
+	sdkClient := mocks.NewMockISdkClient(ctl)
+	return sdkClient, serveTestGRPC(t, sdkClient, nil)
+}
+
+// serveTestGRPC serves a gRPC server over the SDK client and the finality events in-process, and returns a client
+// connected to it
+func serveTestGRPC(
+	t *testing.T,
+	sdkClient client.ISdkClient,
+	subscribe func(ctx context.Context) (<-chan client.FinalityEvent, error),
+) *GRPCClient {
+	listener := bufconn.Listen(1 << 20)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- server.NewGRPCServer(sdkClient, subscribe, server.DefaultConfig(), zap.NewNop()).Serve(ctx, listener)
+	}()
+
+	grpcClient, err := NewGRPCClient("passthrough:///bufconn", testConfig(), zap.NewNop(),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, grpcClient.Close())
+		cancel()
+		require.NoError(t, <-done)
+	})
+	return grpcClient
+}
+
+func TestGRPCBlockFinalized(t *testing.T) {
+	sdkClient, grpcClient := newTestGRPCClient(t)
+	block := cwclient.L2Block{BlockHeight: 100, BlockHash: "0x1234", BlockTimestamp: 1_700_000_000}
+
+	sdkClient.EXPECT().QueryIsBlockBabylonFinalized(gomock.Any(), block).Return(true, nil).Times(1)
+	finalized, err := grpcClient.QueryIsBlockBabylonFinalized(context.Background(), block)
+	require.NoError(t, err)
+	require.True(t, finalized)
+
+	sdkClient.EXPECT().QueryIsBlockBabylonFinalized(gomock.Any(), block).Return(false, nil).Times(1)
+	finalized, err = grpcClient.QueryIsBlockBabylonFinalized(context.Background(), block)
+	require.NoError(t, err)
+	require.False(t, finalized)
+}
+
+func TestGRPCErrors(t *testing.T) {
+	sdkClient, grpcClient := newTestGRPCClient(t)
+	block := cwclient.L2Block{BlockHeight: 100, BlockHash: "0x1234", BlockTimestamp: 1_700_000_000}
+
+	testCases := []struct {
+		name     string
+		err      error
+		code     string
+		expected error
+		// the verdicts of the server are final, the other errors are retried
+		attempts int
+	}{
+		{"ahead of btc tip", fmt.Errorf("%w: block 100", client.ErrTimestampAheadOfBtcTip),
+			api.ErrCodeTimestampAheadOfBtcTip, client.ErrTimestampAheadOfBtcTip, 1},
+		{"before genesis", client.ErrTimestampBeforeGenesis,
+			api.ErrCodeTimestampBeforeGenesis, client.ErrTimestampBeforeGenesis, 1},
+		{"no voting power", client.ErrNoFpHasVotingPower,
+			api.ErrCodeNoFpHasVotingPower, client.ErrNoFpHasVotingPower, 1},
+		{"timeout", fmt.Errorf("failed to query Babylon: %w", context.DeadlineExceeded),
+			api.ErrCodeTimeout, context.DeadlineExceeded, 3},
+		{"canceled", fmt.Errorf("failed to query Babylon: %w", context.Canceled),
+			api.ErrCodeCanceled, context.Canceled, 1},
+		{"internal", fmt.Errorf("connection refused"), api.ErrCodeInternal, nil, 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sdkClient.EXPECT().QueryIsBlockBabylonFinalized(gomock.Any(), block).Return(false, tc.err).Times(tc.attempts)
+			_, err := grpcClient.QueryIsBlockBabylonFinalized(context.Background(), block)
+			var remoteErr *Error
+			require.ErrorAs(t, err, &remoteErr)
+			require.Equal(t, tc.code, remoteErr.Code)
+			require.Equal(t, tc.err.Error(), err.Error())
+			if tc.expected != nil {
+				require.ErrorIs(t, err, tc.expected)
+			}
+		})
+	}
+
+	// a failure on the server side is retried
+	gomock.InOrder(
+		sdkClient.EXPECT().QueryIsBlockBabylonFinalized(gomock.Any(), block).
+			Return(false, fmt.Errorf("connection refused")).Times(1),
+		sdkClient.EXPECT().QueryIsBlockBabylonFinalized(gomock.Any(), block).Return(true, nil).Times(1),
+	)
+	finalized, err := grpcClient.QueryIsBlockBabylonFinalized(context.Background(), block)
+	require.NoError(t, err)
+	require.True(t, finalized)
+}
+
+func TestGRPCBlockRangeFinalized(t *testing.T) {
+	sdkClient, grpcClient := newTestGRPCClient(t)
+	blocks := []*cwclient.L2Block{
+		{BlockHeight: 100, BlockHash: "0x01", BlockTimestamp: 1_700_000_000},
+		{BlockHeight: 101, BlockHash: "0x02", BlockTimestamp: 1_700_000_002},
+		{BlockHeight: 102, BlockHash: "0x03", BlockTimestamp: 1_700_000_004},
+	}
+
+	lastFinalizedHeight := uint64(102)
+	sdkClient.EXPECT().QueryBlockRangeBabylonFinalized(gomock.Any(), blocks).Return(&lastFinalizedHeight, nil).Times(1)
+	height, err := grpcClient.QueryBlockRangeBabylonFinalized(context.Background(), blocks)
+	require.NoError(t, err)
+	require.Equal(t, &lastFinalizedHeight, height)
+
+	// no block is finalized
+	sdkClient.EXPECT().QueryBlockRangeBabylonFinalized(gomock.Any(), blocks).Return(nil, nil).Times(1)
+	height, err = grpcClient.QueryBlockRangeBabylonFinalized(context.Background(), blocks)
+	require.NoError(t, err)
+	require.Nil(t, height)
+
+	// the search fails midway, the last finalized block found goes with the error
+	lastFinalizedHeight = 101
+	sdkClient.EXPECT().QueryBlockRangeBabylonFinalized(gomock.Any(), blocks).
+		Return(&lastFinalizedHeight, client.ErrTimestampAheadOfBtcTip).Times(1)
+	height, err = grpcClient.QueryBlockRangeBabylonFinalized(context.Background(), blocks)
+	require.ErrorIs(t, err, client.ErrTimestampAheadOfBtcTip)
+	require.Equal(t, &lastFinalizedHeight, height)
+
+	// the blocks are not consecutive
+	_, err = grpcClient.QueryBlockRangeBabylonFinalized(context.Background(), []*cwclient.L2Block{blocks[0], blocks[2]})
+	var remoteErr *Error
+	require.ErrorAs(t, err, &remoteErr)
+	require.Equal(t, api.ErrCodeInvalidRequest, remoteErr.Code)
+	require.Equal(t, "block 102 does not follow block 100", remoteErr.Message)
+}
+
+func TestGRPCBtcStakingActivatedTimestamp(t *testing.T) {
+	sdkClient, grpcClient := newTestGRPCClient(t)
+
+	sdkClient.EXPECT().QueryBtcStakingActivatedTimestamp(gomock.Any()).Return(uint64(1_700_000_000), nil).Times(1)
+	timestamp, err := grpcClient.QueryBtcStakingActivatedTimestamp(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(1_700_000_000), timestamp)
+
+	sdkClient.EXPECT().QueryBtcStakingActivatedTimestamp(gomock.Any()).
+		Return(uint64(math.MaxUint64), client.ErrBtcStakingNotActivated).Times(1)
+	timestamp, err = grpcClient.QueryBtcStakingActivatedTimestamp(context.Background())
+	require.ErrorIs(t, err, client.ErrBtcStakingNotActivated)
+	require.Equal(t, uint64(math.MaxUint64), timestamp)
+}
+
+func TestGRPCBlockFinalityResult(t *testing.T) {
+	sdkClient, grpcClient := newTestGRPCClient(t)
+	block := cwclient.L2Block{BlockHeight: 100, BlockHash: "0x1234", BlockTimestamp: 1_700_000_000}
+
+	// the total voting power exceeds uint64
+	totalPower, ok := new(big.Int).SetString("36893488147419103232", 10)
+	require.True(t, ok)
+	result := &finality.Result{
+		Enabled:    true,
+		Finalized:  false,
+		BtcHeight:  1000,
+		TotalPower: totalPower,
+		VotedPower: big.NewInt(500),
+		FpVotes: []finality.FpVote{
+			{FpBtcPkHex: "fp1", Power: 500, Voted: true},
+			{FpBtcPkHex: "fp2", Power: math.MaxUint64, Voted: false},
+		},
+		ConflictingFps:    []string{"fp3"},
+		QuorumNumerator:   2,
+		QuorumDenominator: 3,
+	}
+	sdkClient.EXPECT().QueryBlockFinalityResult(gomock.Any(), block).Return(result, nil).Times(1)
+	resp, err := grpcClient.QueryBlockFinalityResult(context.Background(), block)
+	require.NoError(t, err)
+	require.Equal(t, result, resp)
+
+	// the finality gadget is disabled
+	disabled := &finality.Result{Enabled: false, Finalized: true}
+	sdkClient.EXPECT().QueryBlockFinalityResult(gomock.Any(), block).Return(disabled, nil).Times(1)
+	resp, err = grpcClient.QueryBlockFinalityResult(context.Background(), block)
+	require.NoError(t, err)
+	require.Equal(t, disabled, resp)
+}
+
+func TestGRPCContextDeadline(t *testing.T) {
+	sdkClient, grpcClient := newTestGRPCClient(t)
+	block := cwclient.L2Block{BlockHeight: 100, BlockHash: "0x1234", BlockTimestamp: 1_700_000_000}
+
+	// the deadline of the caller is propagated to the SDK client of the server
+	sdkClient.EXPECT().QueryIsBlockBabylonFinalized(gomock.Any(), block).
+		DoAndReturn(func(ctx context.Context, _ cwclient.L2Block) (bool, error) {
+			<-ctx.Done()
+			return false, ctx.Err()
+		}).Times(1)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := grpcClient.QueryIsBlockBabylonFinalized(ctx, block)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestGRPCSubscribe(t *testing.T) {
+	// the server does not track the L2 chain
+	_, grpcClient := newTestGRPCClient(t)
+	_, err := grpcClient.Subscribe(context.Background())
+	require.ErrorIs(t, err, client.ErrTrackerNotStarted)
+
+	finalityEvents := make(chan client.FinalityEvent)
+	subscribed := make(chan context.Context, 1)
+	grpcClient = serveTestGRPC(t, nil, func(ctx context.Context) (<-chan client.FinalityEvent, error) {
+		subscribed <- ctx
+		return finalityEvents, nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := grpcClient.Subscribe(ctx)
+	require.NoError(t, err)
+	serverCtx := <-subscribed
+
+	block := cwclient.L2Block{BlockHeight: 100, BlockHash: "0x1234", BlockTimestamp: 1_700_000_000}
+	finalityEvents <- client.FinalityEvent{Block: block}
+	select {
+	case event := <-events:
+		require.Equal(t, block, event.Block)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event")
+	}
+
+	// the subscription on the server ends with the one of the client
+	cancel()
+	select {
+	case <-serverCtx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("the subscription of the server is not done")
+	}
+	for range events {
+	}
+}
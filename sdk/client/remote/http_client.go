@@ -0,0 +1,170 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/avast/retry-go/v4"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/babylon-finality-gadget/sdk/client"
+	"github.com/babylonchain/babylon-finality-gadget/sdk/cwclient"
+	"github.com/babylonchain/babylon-finality-gadget/sdk/finality"
+	"github.com/babylonchain/babylon-finality-gadget/server/api"
+)
+
+// maxResponseSize bounds the body of a response of the server
+const maxResponseSize = 8 << 20
+
+// HTTPClient queries a finality gadget server over its HTTP/JSON API. It implements client.ISdkClient and returns the
+// same errors as the SDK client, so that it can replace an embedded SDK client
+type HTTPClient struct {
+	baseURL    string
+	httpClient *http.Client
+	cfg        *Config
+	logger     *zap.Logger
+}
+
+var _ client.ISdkClient = (*HTTPClient)(nil)
+
+// NewHTTPClient creates a client of the finality gadget server at the base URL, e.g. http://localhost:8080
+func NewHTTPClient(baseURL string, cfg *Config, logger *zap.Logger) (*HTTPClient, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid server URL %q", baseURL)
+	}
+	return &HTTPClient{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{},
+		cfg:        cfg,
+		logger:     logger,
+	}, nil
+}
+
+func (c *HTTPClient) QueryIsBlockBabylonFinalized(ctx context.Context, queryParams cwclient.L2Block) (bool, error) {
+	var resp api.BlockFinalizedResponse
+	err := callWithRetry(ctx, "QueryIsBlockBabylonFinalized", func(ctx context.Context) error {
+		return c.do(ctx, http.MethodGet, blockPath(&queryParams, "finalized"), nil, &resp)
+	}, c.logger, c.cfg)
+	if err != nil {
+		return false, err
+	}
+	return resp.Finalized, nil
+}
+
+func (c *HTTPClient) QueryBlockFinalityResult(
+	ctx context.Context,
+	queryParams cwclient.L2Block,
+) (*finality.Result, error) {
+	var result finality.Result
+	err := callWithRetry(ctx, "QueryBlockFinalityResult", func(ctx context.Context) error {
+		return c.do(ctx, http.MethodGet, blockPath(&queryParams, "finality-result"), nil, &result)
+	}, c.logger, c.cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *HTTPClient) QueryBlockRangeBabylonFinalized(
+	ctx context.Context,
+	queryBlocks []*cwclient.L2Block,
+) (*uint64, error) {
+	req := &api.RangeFinalizedRequest{Blocks: make([]api.Block, len(queryBlocks))}
+	for i, block := range queryBlocks {
+		req.Blocks[i] = api.Block{Height: block.BlockHeight, Hash: block.BlockHash, Timestamp: block.BlockTimestamp}
+	}
+	var resp api.RangeFinalizedResponse
+	err := callWithRetry(ctx, "QueryBlockRangeBabylonFinalized", func(ctx context.Context) error {
+		resp = api.RangeFinalizedResponse{}
+		return c.do(ctx, http.MethodPost, "/v1/blocks/range-finalized", req, &resp)
+	}, c.logger, c.cfg)
+	// the server returns the last finalized block found before the failure with the error
+	return resp.LastFinalizedHeight, err
+}
+
+func (c *HTTPClient) QueryBtcStakingActivatedTimestamp(ctx context.Context) (uint64, error) {
+	var resp api.ActivatedTimestampResponse
+	err := callWithRetry(ctx, "QueryBtcStakingActivatedTimestamp", func(ctx context.Context) error {
+		return c.do(ctx, http.MethodGet, "/v1/btc-staking/activated-timestamp", nil, &resp)
+	}, c.logger, c.cfg)
+	if err != nil {
+		return math.MaxUint64, err
+	}
+	return resp.ActivatedTimestamp, nil
+}
+
+// do sends a request to the server and decodes the JSON response body into respBody. The body of an error response
+// is decoded into respBody too, as the range query returns the last finalized block with the error
+func (c *HTTPClient) do(ctx context.Context, method string, path string, reqBody interface{}, respBody interface{}) error {
+	var body io.Reader
+	if reqBody != nil {
+		reqBytes, err := json.Marshal(reqBody)
+		if err != nil {
+			return retry.Unrecoverable(err)
+		}
+		body = bytes.NewReader(reqBytes)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return retry.Unrecoverable(err)
+	}
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBytes, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		if err := json.Unmarshal(respBytes, respBody); err != nil {
+			return fmt.Errorf("failed to decode the response of %s: %w", path, err)
+		}
+		return nil
+	}
+
+	var errResp api.ErrorResponse
+	if err := json.Unmarshal(respBytes, &errResp); err == nil && errResp.Error != nil && errResp.Error.Code != "" {
+		_ = json.Unmarshal(respBytes, respBody)
+		return &Error{
+			Code:    errResp.Error.Code,
+			Message: errResp.Error.Message,
+			err:     sdkErrors[errResp.Error.Code],
+		}
+	}
+	// the response does not come from the finality gadget server, e.g. a proxy in front of it failed
+	remoteErr := &Error{
+		Message: "server returned status " + strconv.Itoa(resp.StatusCode) + ": " + strings.TrimSpace(string(respBytes)),
+	}
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+		return retry.Unrecoverable(remoteErr)
+	}
+	return remoteErr
+}
+
+// blockPath returns the path /v1/blocks/{height}/{query}?hash=&ts= of the block
+func blockPath(block *cwclient.L2Block, query string) string {
+	params := url.Values{}
+	params.Set("hash", block.BlockHash)
+	params.Set("ts", strconv.FormatUint(block.BlockTimestamp, 10))
+	return fmt.Sprintf("/v1/blocks/%d/%s?%s", block.BlockHeight, query, params.Encode())
+}
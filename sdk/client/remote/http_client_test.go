@@ -0,0 +1,223 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/babylon-finality-gadget/sdk/client"
+	"github.com/babylonchain/babylon-finality-gadget/sdk/cwclient"
+	"github.com/babylonchain/babylon-finality-gadget/sdk/finality"
+	"github.com/babylonchain/babylon-finality-gadget/server"
+	"github.com/babylonchain/babylon-finality-gadget/server/api"
+	"github.com/babylonchain/babylon-finality-gadget/testutil/mocks"
+)
+
+// newTestHTTPClient serves the HTTP API over a mock SDK client, and returns a client of it. The given number of first
+// requests fail with failureStatus without reaching the API, as if a proxy in front of the server failed
+func newTestHTTPClient(t *testing.T, failures int32, failureStatus int) (*mocks.MockISdkClient, *HTTPClient) {
+	ctl := gomock.NewController(t)
+	sdkClient := mocks.NewMockISdkClient(ctl)
+	handler := server.New(sdkClient, nil, server.DefaultConfig(), zap.NewNop()).Handler()
+
+	var failed atomic.Int32
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failed.Add(1) <= failures {
+			http.Error(w, "upstream unavailable", failureStatus)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(httpServer.Close)
+
+	httpClient, err := NewHTTPClient(httpServer.URL+"/", testConfig(), zap.NewNop())
+	require.NoError(t, err)
+	return sdkClient, httpClient
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	_, err := NewHTTPClient("localhost:8080", DefaultConfig(), zap.NewNop())
+	require.ErrorContains(t, err, "invalid server URL")
+
+	cfg := DefaultConfig()
+	cfg.MaxRetryTimes = 0
+	_, err = NewHTTPClient("http://localhost:8080", cfg, zap.NewNop())
+	require.ErrorContains(t, err, "the max retry times must be positive")
+}
+
+func TestHTTPBlockFinalized(t *testing.T) {
+	sdkClient, httpClient := newTestHTTPClient(t, 0, 0)
+	block := cwclient.L2Block{BlockHeight: 100, BlockHash: "0x1234", BlockTimestamp: 1_700_000_000}
+
+	sdkClient.EXPECT().QueryIsBlockBabylonFinalized(gomock.Any(), block).Return(true, nil).Times(1)
+	finalized, err := httpClient.QueryIsBlockBabylonFinalized(context.Background(), block)
+	require.NoError(t, err)
+	require.True(t, finalized)
+
+	sdkClient.EXPECT().QueryIsBlockBabylonFinalized(gomock.Any(), block).Return(false, nil).Times(1)
+	finalized, err = httpClient.QueryIsBlockBabylonFinalized(context.Background(), block)
+	require.NoError(t, err)
+	require.False(t, finalized)
+}
+
+func TestHTTPErrors(t *testing.T) {
+	sdkClient, httpClient := newTestHTTPClient(t, 0, 0)
+	block := cwclient.L2Block{BlockHeight: 100, BlockHash: "0x1234", BlockTimestamp: 1_700_000_000}
+
+	testCases := []struct {
+		name     string
+		err      error
+		code     string
+		expected error
+		// the verdicts of the server are final, the other errors are retried
+		attempts int
+	}{
+		{"ahead of btc tip", fmt.Errorf("%w: block 100", client.ErrTimestampAheadOfBtcTip),
+			api.ErrCodeTimestampAheadOfBtcTip, client.ErrTimestampAheadOfBtcTip, 1},
+		{"before genesis", client.ErrTimestampBeforeGenesis,
+			api.ErrCodeTimestampBeforeGenesis, client.ErrTimestampBeforeGenesis, 1},
+		{"no voting power", client.ErrNoFpHasVotingPower,
+			api.ErrCodeNoFpHasVotingPower, client.ErrNoFpHasVotingPower, 1},
+		{"timeout", fmt.Errorf("failed to query Babylon: %w", context.DeadlineExceeded),
+			api.ErrCodeTimeout, context.DeadlineExceeded, 3},
+		{"canceled", fmt.Errorf("failed to query Babylon: %w", context.Canceled),
+			api.ErrCodeCanceled, context.Canceled, 1},
+		{"internal", fmt.Errorf("connection refused"), api.ErrCodeInternal, nil, 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sdkClient.EXPECT().QueryIsBlockBabylonFinalized(gomock.Any(), block).Return(false, tc.err).Times(tc.attempts)
+			_, err := httpClient.QueryIsBlockBabylonFinalized(context.Background(), block)
+			var remoteErr *Error
+			require.ErrorAs(t, err, &remoteErr)
+			require.Equal(t, tc.code, remoteErr.Code)
+			require.Equal(t, tc.err.Error(), err.Error())
+			if tc.expected != nil {
+				require.ErrorIs(t, err, tc.expected)
+			}
+		})
+	}
+}
+
+func TestHTTPRetry(t *testing.T) {
+	block := cwclient.L2Block{BlockHeight: 100, BlockHash: "0x1234", BlockTimestamp: 1_700_000_000}
+
+	// the failures of a proxy are retried
+	sdkClient, httpClient := newTestHTTPClient(t, 2, http.StatusBadGateway)
+	sdkClient.EXPECT().QueryIsBlockBabylonFinalized(gomock.Any(), block).Return(true, nil).Times(1)
+	finalized, err := httpClient.QueryIsBlockBabylonFinalized(context.Background(), block)
+	require.NoError(t, err)
+	require.True(t, finalized)
+
+	// until the attempts are exhausted
+	_, httpClient = newTestHTTPClient(t, 3, http.StatusBadGateway)
+	_, err = httpClient.QueryIsBlockBabylonFinalized(context.Background(), block)
+	require.ErrorContains(t, err, "server returned status 502: upstream unavailable")
+
+	// the requests rejected by a proxy are not retried
+	_, httpClient = newTestHTTPClient(t, 1, http.StatusForbidden)
+	_, err = httpClient.QueryIsBlockBabylonFinalized(context.Background(), block)
+	require.ErrorContains(t, err, "server returned status 403")
+}
+
+func TestHTTPBlockRangeFinalized(t *testing.T) {
+	sdkClient, httpClient := newTestHTTPClient(t, 0, 0)
+	blocks := []*cwclient.L2Block{
+		{BlockHeight: 100, BlockHash: "0x01", BlockTimestamp: 1_700_000_000},
+		{BlockHeight: 101, BlockHash: "0x02", BlockTimestamp: 1_700_000_002},
+		{BlockHeight: 102, BlockHash: "0x03", BlockTimestamp: 1_700_000_004},
+	}
+
+	lastFinalizedHeight := uint64(102)
+	sdkClient.EXPECT().QueryBlockRangeBabylonFinalized(gomock.Any(), blocks).Return(&lastFinalizedHeight, nil).Times(1)
+	height, err := httpClient.QueryBlockRangeBabylonFinalized(context.Background(), blocks)
+	require.NoError(t, err)
+	require.Equal(t, &lastFinalizedHeight, height)
+
+	// no block is finalized
+	sdkClient.EXPECT().QueryBlockRangeBabylonFinalized(gomock.Any(), blocks).Return(nil, nil).Times(1)
+	height, err = httpClient.QueryBlockRangeBabylonFinalized(context.Background(), blocks)
+	require.NoError(t, err)
+	require.Nil(t, height)
+
+	// the search fails midway, the last finalized block found goes with the error
+	lastFinalizedHeight = 101
+	sdkClient.EXPECT().QueryBlockRangeBabylonFinalized(gomock.Any(), blocks).
+		Return(&lastFinalizedHeight, client.ErrTimestampAheadOfBtcTip).Times(1)
+	height, err = httpClient.QueryBlockRangeBabylonFinalized(context.Background(), blocks)
+	require.ErrorIs(t, err, client.ErrTimestampAheadOfBtcTip)
+	require.Equal(t, &lastFinalizedHeight, height)
+
+	// the blocks are not consecutive
+	_, err = httpClient.QueryBlockRangeBabylonFinalized(context.Background(), []*cwclient.L2Block{blocks[0], blocks[2]})
+	var remoteErr *Error
+	require.ErrorAs(t, err, &remoteErr)
+	require.Equal(t, api.ErrCodeInvalidRequest, remoteErr.Code)
+}
+
+func TestHTTPBtcStakingActivatedTimestamp(t *testing.T) {
+	sdkClient, httpClient := newTestHTTPClient(t, 0, 0)
+
+	sdkClient.EXPECT().QueryBtcStakingActivatedTimestamp(gomock.Any()).Return(uint64(1_700_000_000), nil).Times(1)
+	timestamp, err := httpClient.QueryBtcStakingActivatedTimestamp(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(1_700_000_000), timestamp)
+
+	sdkClient.EXPECT().QueryBtcStakingActivatedTimestamp(gomock.Any()).
+		Return(uint64(math.MaxUint64), client.ErrBtcStakingNotActivated).Times(1)
+	timestamp, err = httpClient.QueryBtcStakingActivatedTimestamp(context.Background())
+	require.ErrorIs(t, err, client.ErrBtcStakingNotActivated)
+	require.Equal(t, uint64(math.MaxUint64), timestamp)
+}
+
+func TestHTTPBlockFinalityResult(t *testing.T) {
+	sdkClient, httpClient := newTestHTTPClient(t, 0, 0)
+	block := cwclient.L2Block{BlockHeight: 100, BlockHash: "0x1234", BlockTimestamp: 1_700_000_000}
+
+	// the total voting power exceeds uint64
+	totalPower, ok := new(big.Int).SetString("36893488147419103232", 10)
+	require.True(t, ok)
+	result := &finality.Result{
+		Enabled:    true,
+		BtcHeight:  1000,
+		TotalPower: totalPower,
+		VotedPower: big.NewInt(500),
+		FpVotes: []finality.FpVote{
+			{FpBtcPkHex: "fp1", Power: 500, Voted: true},
+			{FpBtcPkHex: "fp2", Power: math.MaxUint64, Voted: false},
+		},
+		ConflictingFps:    []string{"fp3"},
+		QuorumNumerator:   2,
+		QuorumDenominator: 3,
+	}
+	sdkClient.EXPECT().QueryBlockFinalityResult(gomock.Any(), block).Return(result, nil).Times(1)
+	resp, err := httpClient.QueryBlockFinalityResult(context.Background(), block)
+	require.NoError(t, err)
+	require.Equal(t, result, resp)
+}
+
+func TestHTTPRequestTimeout(t *testing.T) {
+	sdkClient, httpClient := newTestHTTPClient(t, 0, 0)
+	httpClient.cfg.RequestTimeout = 50 * time.Millisecond
+	block := cwclient.L2Block{BlockHeight: 100, BlockHash: "0x1234", BlockTimestamp: 1_700_000_000}
+
+	// each attempt is bounded by the request timeout
+	sdkClient.EXPECT().QueryIsBlockBabylonFinalized(gomock.Any(), block).
+		DoAndReturn(func(ctx context.Context, _ cwclient.L2Block) (bool, error) {
+			<-ctx.Done()
+			return false, ctx.Err()
+		}).Times(3)
+	_, err := httpClient.QueryIsBlockBabylonFinalized(context.Background(), block)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,74 @@
+package remote
+
+import (
+	"context"
+	"errors"
+
+	"github.com/avast/retry-go/v4"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/babylon-finality-gadget/server/api"
+)
+
+// callWithRetry calls the query until it succeeds, fails with a final error, or the attempts are exhausted. Each
+// attempt is bounded by Config.RequestTimeout
+func callWithRetry(
+	ctx context.Context,
+	method string,
+	call func(ctx context.Context) error,
+	logger *zap.Logger,
+	cfg *Config,
+) error {
+	return retry.Do(
+		func() error {
+			attemptCtx, cancel := attemptContext(ctx, cfg)
+			defer cancel()
+			return call(attemptCtx)
+		},
+		retry.Context(ctx),
+		retry.Attempts(cfg.MaxRetryTimes),
+		retry.Delay(cfg.RetryInterval),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(func(err error) bool {
+			return retry.IsRecoverable(err) && retryable(ctx, err)
+		}),
+		retry.OnRetry(func(n uint, err error) {
+			logger.Debug(
+				"failed to query the finality gadget server",
+				zap.String("method", method),
+				zap.Uint("attempt", n+1),
+				zap.Uint("max_attempts", cfg.MaxRetryTimes),
+				zap.Error(err),
+			)
+		}),
+	)
+}
+
+func attemptContext(ctx context.Context, cfg *Config) (context.Context, context.CancelFunc) {
+	if cfg.RequestTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, cfg.RequestTimeout)
+}
+
+// retryable returns whether a failed query may succeed if retried, i.e. it failed in transit, timed out, or failed
+// on the server side. The verdicts of the server, e.g. that the BTC staking is not activated, are final
+func retryable(ctx context.Context, err error) bool {
+	// the caller gave up
+	if ctx.Err() != nil {
+		return false
+	}
+	var remoteErr *Error
+	if !errors.As(err, &remoteErr) {
+		return true
+	}
+	switch remoteErr.Code {
+	case "", api.ErrCodeTimeout, api.ErrCodeNotReady, api.ErrCodeInternal:
+		return true
+	// the server saw the request canceled, e.g. by a proxy that closed the connection
+	case api.ErrCodeCanceled:
+		return false
+	default:
+		return false
+	}
+}
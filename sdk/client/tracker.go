@@ -0,0 +1,400 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/babylon-finality-gadget/sdk/cwclient"
+)
+
+const (
+	defaultTrackerPollInterval = 5 * time.Second
+	defaultTrackerMaxRangeSize = 100
+	// staleSubscriptionIntervals is the number of poll intervals without a new Babylon block after which the
+	// subscription is considered stale, e.g. its endpoint stopped sending events, and is made again
+	staleSubscriptionIntervals = 12
+	// finalizedHistorySize is the number of the latest finalized L2 blocks kept by the tracker, to roll back to the
+	// last one still in the canonical L2 chain after an L2 reorg
+	finalizedHistorySize = 256
+)
+
+// TrackerConfig defines configuration for the finality Tracker
+type TrackerConfig struct {
+	// StartHeight is the first L2 block tracked. Set to 0 to start at the L2 head
+	StartHeight uint64 `mapstructure:"start-height"`
+	// PollInterval is the interval the L2 head is polled at. The finality is also checked on each new Babylon block,
+	// as the finality votes land in Babylon blocks
+	PollInterval time.Duration `mapstructure:"poll-interval"`
+	// MaxRangeSize bounds the number of L2 blocks checked by one range query, so that a tracker far behind the L2
+	// head catches up in batches
+	MaxRangeSize uint64 `mapstructure:"max-range-size"`
+}
+
+func DefaultTrackerConfig() *TrackerConfig {
+	return &TrackerConfig{
+		PollInterval: defaultTrackerPollInterval,
+		MaxRangeSize: defaultTrackerMaxRangeSize,
+	}
+}
+
+func (cfg *TrackerConfig) Validate() error {
+	if cfg.PollInterval <= 0 {
+		return errors.New("the tracker poll interval must be positive")
+	}
+	if cfg.MaxRangeSize == 0 {
+		return errors.New("the tracker max range size must be positive")
+	}
+	return nil
+}
+
+// FinalityEvent is emitted when the last finalized L2 block advances, or is rolled back by an L2 reorg
+type FinalityEvent struct {
+	// Block is the new last finalized L2 block
+	Block cwclient.L2Block
+}
+
+// Tracker follows the L2 head and the new Babylon blocks, and notifies its subscribers whenever the last finalized
+// L2 block advances, so that the L2 node does not need to poll QueryBlockRangeBabylonFinalized
+//
+//   - the L2 blocks from the last finalized block up to the L2 head are checked with QueryBlockRangeBabylonFinalized
+//     on each new Babylon block, received with a CometBFT event subscription, and every PollInterval, which also
+//     covers the Babylon blocks missed while the subscription is down. The subscription is made again once it ends,
+//     or once no Babylon block is received for staleSubscriptionIntervals poll intervals
+//   - the last finalized L2 block is checked against the canonical L2 chain before each check. If an L2 reorg
+//     replaced it, the tracker rolls back to the last finalized block still in the chain, notifies the subscribers,
+//     and checks the blocks of the new chain from there
+//   - a subscriber that does not keep up only misses intermediate events, it always receives the latest one
+type Tracker struct {
+	sdkClient ISdkClient
+	bbnClient IBabylonClient
+	l2Client  IL2Client
+	cfg       *TrackerConfig
+	logger    *zap.Logger
+
+	mu sync.Mutex
+	// startHeight is the first L2 block tracked, pinned on the first L2 head if StartHeight is 0
+	startHeight   uint64
+	lastFinalized *cwclient.L2Block
+	// finalized holds the latest finalized L2 blocks, consecutive and sorted from low to high, up to lastFinalized
+	finalized   []*cwclient.L2Block
+	subscribers map[chan FinalityEvent]struct{}
+	// done is closed once Run returns
+	done chan struct{}
+}
+
+// NewTracker creates a tracker of the finality of the L2 blocks of l2Client. It starts tracking once Run is called
+func NewTracker(
+	sdkClient ISdkClient,
+	bbnClient IBabylonClient,
+	l2Client IL2Client,
+	cfg *TrackerConfig,
+	logger *zap.Logger,
+) *Tracker {
+	return &Tracker{
+		sdkClient:   sdkClient,
+		bbnClient:   bbnClient,
+		l2Client:    l2Client,
+		cfg:         cfg,
+		logger:      logger,
+		startHeight: cfg.StartHeight,
+		subscribers: make(map[chan FinalityEvent]struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// Run tracks the finality until the context is done, then closes the channels of the subscribers
+func (t *Tracker) Run(ctx context.Context) {
+	defer t.stop()
+
+	newBlocks, unsubscribe := t.subscribeNewBlocks(ctx)
+	defer func() { unsubscribe() }()
+	lastNewBlock := time.Now()
+	ticker := time.NewTicker(t.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		t.update(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-newBlocks:
+			if ok {
+				lastNewBlock = time.Now()
+			} else {
+				newBlocks = nil
+			}
+		case <-ticker.C:
+			if newBlocks != nil && time.Since(lastNewBlock) >= staleSubscriptionIntervals*t.cfg.PollInterval {
+				t.logger.Warn("No new Babylon block received, subscribing again",
+					zap.Duration("since", time.Since(lastNewBlock)))
+				newBlocks = nil
+			}
+			if newBlocks == nil {
+				unsubscribe()
+				newBlocks, unsubscribe = t.subscribeNewBlocks(ctx)
+				lastNewBlock = time.Now()
+			}
+		}
+	}
+}
+
+// Subscribe returns a channel of the events of the tracker, starting with the current last finalized block if any.
+// The channel is closed once the context is done or the tracker stops
+func (t *Tracker) Subscribe(ctx context.Context) <-chan FinalityEvent {
+	ch := make(chan FinalityEvent, 1)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	select {
+	case <-t.done:
+		close(ch)
+		return ch
+	default:
+	}
+	if t.lastFinalized != nil {
+		ch <- FinalityEvent{Block: *t.lastFinalized}
+	}
+	t.subscribers[ch] = struct{}{}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-t.done:
+		}
+		t.unsubscribe(ch)
+	}()
+	return ch
+}
+
+// LastFinalizedBlock returns the last finalized L2 block found by the tracker, or nil if none is found yet
+func (t *Tracker) LastFinalizedBlock() *cwclient.L2Block {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.lastFinalized
+}
+
+// subscribeNewBlocks subscribes to the new Babylon blocks, and returns the channel of the new blocks and the function
+// that ends the subscription. The channel is nil if the subscription fails, in which case the tracker polls until the
+// next attempt
+func (t *Tracker) subscribeNewBlocks(ctx context.Context) (<-chan uint64, context.CancelFunc) {
+	subCtx, cancel := context.WithCancel(ctx)
+	newBlocks, err := t.bbnClient.SubscribeNewBlocks(subCtx)
+	if err != nil {
+		t.logger.Warn("Failed to subscribe to the new Babylon blocks, polling instead", zap.Error(err))
+		return nil, cancel
+	}
+	return newBlocks, cancel
+}
+
+// update checks the L2 blocks from the last finalized block up to the L2 head, in ranges of MaxRangeSize blocks
+func (t *Tracker) update(ctx context.Context) {
+	for ctx.Err() == nil {
+		more, err := t.checkNextRange(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				t.logger.Warn("Failed to track the L2 finality", zap.Error(err))
+			}
+			return
+		}
+		if !more {
+			return
+		}
+	}
+}
+
+// checkNextRange checks the finality of the next range of L2 blocks, and returns whether the following blocks may
+// be finalized too, i.e. the whole range is finalized and the L2 head is beyond it
+func (t *Tracker) checkNextRange(ctx context.Context) (bool, error) {
+	head, err := t.l2Client.HeadBlock(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get the L2 head: %w", err)
+	}
+	if err := t.checkReorg(ctx, head); err != nil {
+		return false, err
+	}
+	start := t.nextHeight(head)
+	if start > head.BlockHeight {
+		return false, nil
+	}
+	end := head.BlockHeight
+	if end-start >= t.cfg.MaxRangeSize {
+		end = start + t.cfg.MaxRangeSize - 1
+	}
+
+	blocks := make([]*cwclient.L2Block, 0, end-start+1)
+	for height := start; height < end; height++ {
+		block, err := t.l2Client.BlockByHeight(ctx, height)
+		if err != nil {
+			return false, fmt.Errorf("failed to get L2 block %d: %w", height, err)
+		}
+		blocks = append(blocks, block)
+	}
+	if end == head.BlockHeight {
+		blocks = append(blocks, head)
+	} else {
+		block, err := t.l2Client.BlockByHeight(ctx, end)
+		if err != nil {
+			return false, fmt.Errorf("failed to get L2 block %d: %w", end, err)
+		}
+		blocks = append(blocks, block)
+	}
+
+	lastFinalizedHeight, err := t.sdkClient.QueryBlockRangeBabylonFinalized(ctx, blocks)
+	// the blocks found finalized before an error are published too
+	if lastFinalizedHeight != nil {
+		t.publish(blocks[:*lastFinalizedHeight-start+1])
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check the finality of L2 blocks %d-%d: %w", start, end, err)
+	}
+	return lastFinalizedHeight != nil && *lastFinalizedHeight == end && end < head.BlockHeight, nil
+}
+
+// nextHeight returns the height of the first L2 block to check
+func (t *Tracker) nextHeight(head *cwclient.L2Block) uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.lastFinalized != nil {
+		return t.lastFinalized.BlockHeight + 1
+	}
+	if t.startHeight == 0 {
+		t.startHeight = head.BlockHeight
+	}
+	return t.startHeight
+}
+
+// checkReorg rolls back the finalized blocks that are no longer in the canonical L2 chain, e.g. after an L2 reorg
+func (t *Tracker) checkReorg(ctx context.Context, head *cwclient.L2Block) error {
+	t.mu.Lock()
+	finalized := t.finalized
+	t.mu.Unlock()
+	if len(finalized) == 0 {
+		return nil
+	}
+
+	// the blocks of a chain are linked, so the canonical blocks are below the replaced ones
+	var err error
+	canonical := func(block *cwclient.L2Block) bool {
+		if err != nil || block.BlockHeight > head.BlockHeight {
+			return false
+		}
+		var canonicalBlock *cwclient.L2Block
+		if canonicalBlock, err = t.l2Client.BlockByHeight(ctx, block.BlockHeight); err != nil {
+			return false
+		}
+		return canonicalBlock.BlockHash == block.BlockHash
+	}
+	if canonical(finalized[len(finalized)-1]) {
+		return nil
+	}
+	i := sort.Search(len(finalized)-1, func(i int) bool { return !canonical(finalized[i]) })
+	if err != nil {
+		return fmt.Errorf("failed to check the finalized L2 blocks against the L2 chain: %w", err)
+	}
+	t.rollback(finalized[i])
+	return nil
+}
+
+// rollback removes the finalized blocks from the block on, and notifies the subscribers of the new last finalized
+// block, if any. The blocks are checked again from the height of the block
+func (t *Tracker) rollback(block *cwclient.L2Block) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	i := sort.Search(len(t.finalized), func(i int) bool { return t.finalized[i].BlockHeight >= block.BlockHeight })
+	if i == len(t.finalized) {
+		return
+	}
+	t.logger.Warn("The finalized L2 blocks were replaced by an L2 reorg, rolling back",
+		zap.Uint64("from_height", block.BlockHeight),
+		zap.String("from_hash", block.BlockHash),
+		zap.Uint64("last_finalized_height", t.lastFinalized.BlockHeight))
+
+	t.finalized = t.finalized[:i]
+	if len(t.finalized) == 0 {
+		t.lastFinalized = nil
+		t.startHeight = block.BlockHeight
+		return
+	}
+	t.lastFinalized = t.finalized[len(t.finalized)-1]
+	t.notify(FinalityEvent{Block: *t.lastFinalized})
+}
+
+// publish records the finalized blocks, consecutive and sorted from low to high, and notifies the subscribers if the
+// last finalized block advanced
+func (t *Tracker) publish(blocks []*cwclient.L2Block) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	block := blocks[len(blocks)-1]
+	if t.lastFinalized != nil && block.BlockHeight <= t.lastFinalized.BlockHeight {
+		return
+	}
+	t.lastFinalized = block
+	t.finalized = append(t.finalized, blocks...)
+	// the history is trimmed once it doubled, so that the blocks are not moved on each publish
+	if len(t.finalized) > 2*finalizedHistorySize {
+		t.finalized = append([]*cwclient.L2Block(nil), t.finalized[len(t.finalized)-finalizedHistorySize:]...)
+	}
+	t.logger.Debug("Last finalized L2 block advanced",
+		zap.Uint64("height", block.BlockHeight), zap.String("hash", block.BlockHash))
+	t.notify(FinalityEvent{Block: *block})
+}
+
+// notify sends the event to the subscribers. It must be called with t.mu held
+func (t *Tracker) notify(event FinalityEvent) {
+	for ch := range t.subscribers {
+		// only the latest event matters, so the pending event of a slow subscriber is replaced
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- event
+	}
+}
+
+func (t *Tracker) unsubscribe(ch chan FinalityEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.subscribers[ch]; ok {
+		delete(t.subscribers, ch)
+		close(ch)
+	}
+}
+
+func (t *Tracker) stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	close(t.done)
+	for ch := range t.subscribers {
+		delete(t.subscribers, ch)
+		close(ch)
+	}
+}
+
+// StartTracker starts a Tracker of the L2 blocks of l2Client in the background until the context is done, so that
+// the last finalized L2 block can be followed with Subscribe
+func (sdkClient *SdkClient) StartTracker(ctx context.Context, l2Client IL2Client, cfg *TrackerConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	tracker := NewTracker(sdkClient, sdkClient.bbnClient, l2Client, cfg, sdkClient.logger)
+	if !sdkClient.tracker.CompareAndSwap(nil, tracker) {
+		return errors.New("the tracker is already started")
+	}
+	go tracker.Run(ctx)
+	return nil
+}
+
+// Subscribe returns a channel of the events of the tracker started with StartTracker, starting with the current
+// last finalized block if any. The channel is closed once the context is done or the tracker stops
+func (sdkClient *SdkClient) Subscribe(ctx context.Context) (<-chan FinalityEvent, error) {
+	tracker := sdkClient.tracker.Load()
+	if tracker == nil {
+		return nil, ErrTrackerNotStarted
+	}
+	return tracker.Subscribe(ctx), nil
+}
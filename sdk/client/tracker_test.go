@@ -0,0 +1,360 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/babylon-finality-gadget/sdk/cwclient"
+	"github.com/babylonchain/babylon-finality-gadget/testutil/mocks"
+)
+
+// fakeL2Chain is an L2 chain whose blocks up to finalizedHeight are finalized, the blocks from forkHeight on being
+// the ones of the fork if it is set
+type fakeL2Chain struct {
+	ISdkClient
+
+	mu              sync.Mutex
+	head            uint64
+	finalizedHeight uint64
+	fork            uint64
+	forkHeight      uint64
+	// rangeQueries records the first and last heights of the range queries
+	rangeQueries [][2]uint64
+}
+
+func newTestL2Block(height uint64) *cwclient.L2Block {
+	return &cwclient.L2Block{
+		BlockHeight:    height,
+		BlockHash:      fmt.Sprintf("0x%x", height),
+		BlockTimestamp: 1_700_000_000 + 2*height,
+	}
+}
+
+// newTestForkBlock returns the L2 block at the height of the chain of the fork, or of the main chain if fork is 0
+func newTestForkBlock(height uint64, fork uint64) *cwclient.L2Block {
+	block := newTestL2Block(height)
+	if fork > 0 {
+		block.BlockHash = fmt.Sprintf("%s-%d", block.BlockHash, fork)
+	}
+	return block
+}
+
+// block returns the canonical block at the height. It must be called with c.mu held
+func (c *fakeL2Chain) block(height uint64) *cwclient.L2Block {
+	if c.fork > 0 && height >= c.forkHeight {
+		return newTestForkBlock(height, c.fork)
+	}
+	return newTestL2Block(height)
+}
+
+// reorg replaces the blocks from the height on with the ones of the fork
+func (c *fakeL2Chain) reorg(height uint64, fork uint64, head uint64, finalizedHeight uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.forkHeight, c.fork, c.head, c.finalizedHeight = height, fork, head, finalizedHeight
+}
+
+func (c *fakeL2Chain) set(head uint64, finalizedHeight uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.head, c.finalizedHeight = head, finalizedHeight
+}
+
+func (c *fakeL2Chain) HeadBlock(_ context.Context) (*cwclient.L2Block, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.block(c.head), nil
+}
+
+func (c *fakeL2Chain) BlockByHeight(_ context.Context, height uint64) (*cwclient.L2Block, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if height > c.head {
+		return nil, fmt.Errorf("block %d not found", height)
+	}
+	return c.block(height), nil
+}
+
+func (c *fakeL2Chain) QueryBlockRangeBabylonFinalized(
+	_ context.Context,
+	queryBlocks []*cwclient.L2Block,
+) (*uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	first, last := queryBlocks[0].BlockHeight, queryBlocks[len(queryBlocks)-1].BlockHeight
+	c.rangeQueries = append(c.rangeQueries, [2]uint64{first, last})
+	if c.finalizedHeight < first {
+		return nil, nil
+	}
+	if c.finalizedHeight < last {
+		last = c.finalizedHeight
+	}
+	return &last, nil
+}
+
+// newTestTracker runs a tracker over the chain, and returns the channel of the new Babylon blocks
+func newTestTracker(
+	t *testing.T,
+	chain *fakeL2Chain,
+	cfg *TrackerConfig,
+	subscriptionErr error,
+) (*Tracker, chan uint64) {
+	ctl := gomock.NewController(t)
+	bbnClient := mocks.NewMockIBabylonClient(ctl)
+	newBlocks := make(chan uint64)
+	if subscriptionErr != nil {
+		bbnClient.EXPECT().SubscribeNewBlocks(gomock.Any()).Return(nil, subscriptionErr).AnyTimes()
+	} else {
+		bbnClient.EXPECT().SubscribeNewBlocks(gomock.Any()).Return(newBlocks, nil).Times(1)
+	}
+
+	tracker := NewTracker(chain, bbnClient, chain, cfg, zap.NewNop())
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		tracker.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return tracker, newBlocks
+}
+
+func requireEvent(t *testing.T, events <-chan FinalityEvent, height uint64) {
+	select {
+	case event, ok := <-events:
+		require.True(t, ok)
+		require.Equal(t, *newTestL2Block(height), event.Block)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no event for block %d", height)
+	}
+}
+
+func TestTrackerFollowsBabylonBlocks(t *testing.T) {
+	chain := &fakeL2Chain{head: 250, finalizedHeight: 180}
+	cfg := &TrackerConfig{StartHeight: 1, PollInterval: time.Hour, MaxRangeSize: 100}
+	tracker, newBlocks := newTestTracker(t, chain, cfg, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := tracker.Subscribe(ctx)
+	// the tracker catches up in ranges of MaxRangeSize blocks, and the first event may be missed by the subscriber
+	require.Eventually(t, func() bool {
+		block := tracker.LastFinalizedBlock()
+		return block != nil && block.BlockHeight == 180
+	}, 5*time.Second, 10*time.Millisecond)
+	requireEvent(t, events, 180)
+	chain.mu.Lock()
+	require.Equal(t, [][2]uint64{{1, 100}, {101, 200}}, chain.rangeQueries)
+	chain.mu.Unlock()
+
+	// a new Babylon block finalizes more L2 blocks
+	chain.set(260, 255)
+	newBlocks <- 1000
+	requireEvent(t, events, 255)
+
+	// a late subscriber gets the current last finalized block
+	requireEvent(t, tracker.Subscribe(ctx), 255)
+}
+
+func TestTrackerRollsBackReorg(t *testing.T) {
+	chain := &fakeL2Chain{head: 20, finalizedHeight: 15}
+	cfg := &TrackerConfig{StartHeight: 1, PollInterval: time.Hour, MaxRangeSize: 100}
+	tracker, newBlocks := newTestTracker(t, chain, cfg, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := tracker.Subscribe(ctx)
+	require.Eventually(t, func() bool {
+		block := tracker.LastFinalizedBlock()
+		return block != nil && block.BlockHeight == 15
+	}, 5*time.Second, 10*time.Millisecond)
+	requireEvent(t, events, 15)
+
+	// the finalized blocks from 12 on are replaced, so the tracker rolls back to block 11
+	chain.reorg(12, 1, 22, 11)
+	newBlocks <- 1000
+	requireEvent(t, events, 11)
+
+	// and checks the blocks of the new chain from there
+	chain.mu.Lock()
+	chain.rangeQueries = nil
+	chain.finalizedHeight = 18
+	chain.mu.Unlock()
+	newBlocks <- 1001
+	select {
+	case event := <-events:
+		require.Equal(t, *newTestForkBlock(18, 1), event.Block)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event for block 18 of the fork")
+	}
+	chain.mu.Lock()
+	require.Equal(t, uint64(12), chain.rangeQueries[0][0])
+	chain.mu.Unlock()
+
+	// the reorg is deeper than the finalized blocks, so the tracker starts over from the first one
+	chain.reorg(1, 2, 30, 0)
+	newBlocks <- 1002
+	require.Eventually(t, func() bool { return tracker.LastFinalizedBlock() == nil }, 5*time.Second, 10*time.Millisecond)
+	chain.set(30, 5)
+	newBlocks <- 1003
+	select {
+	case event := <-events:
+		require.Equal(t, *newTestForkBlock(5, 2), event.Block)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event for block 5 of the second fork")
+	}
+}
+
+func TestTrackerSlowSubscriber(t *testing.T) {
+	chain := &fakeL2Chain{head: 10, finalizedHeight: 0}
+	cfg := &TrackerConfig{StartHeight: 1, PollInterval: time.Hour, MaxRangeSize: 100}
+	tracker, newBlocks := newTestTracker(t, chain, cfg, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := tracker.Subscribe(ctx)
+	for height := uint64(1); height <= 5; height++ {
+		chain.set(10, height)
+		newBlocks <- 1000 + height
+	}
+	// the channel of the next Babylon block is unbuffered, so the previous update is done once it is received
+	newBlocks <- 2000
+	// the subscriber only gets the latest event
+	requireEvent(t, events, 5)
+	select {
+	case event := <-events:
+		t.Fatalf("unexpected event for block %d", event.Block.BlockHeight)
+	default:
+	}
+}
+
+func TestTrackerPollsWithoutSubscription(t *testing.T) {
+	// the tracker starts at the L2 head
+	chain := &fakeL2Chain{head: 50, finalizedHeight: 40}
+	cfg := &TrackerConfig{PollInterval: 10 * time.Millisecond, MaxRangeSize: 100}
+	tracker, _ := newTestTracker(t, chain, cfg, fmt.Errorf("websocket unavailable"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := tracker.Subscribe(ctx)
+	require.Eventually(t, func() bool {
+		chain.mu.Lock()
+		defer chain.mu.Unlock()
+		return len(chain.rangeQueries) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	// the blocks before the head are not tracked, and the head keeps being checked as the L2 chain advances
+	chain.set(60, 50)
+	requireEvent(t, events, 50)
+	chain.set(70, 65)
+	requireEvent(t, events, 65)
+}
+
+func TestTrackerResubscribesStaleSubscription(t *testing.T) {
+	chain := &fakeL2Chain{head: 10, finalizedHeight: 10}
+	cfg := &TrackerConfig{StartHeight: 1, PollInterval: 10 * time.Millisecond, MaxRangeSize: 100}
+	ctl := gomock.NewController(t)
+	bbnClient := mocks.NewMockIBabylonClient(ctl)
+	// no Babylon block is received on the subscriptions
+	subscriptions := make(chan context.Context, 2)
+	bbnClient.EXPECT().SubscribeNewBlocks(gomock.Any()).DoAndReturn(func(ctx context.Context) (<-chan uint64, error) {
+		select {
+		case subscriptions <- ctx:
+		default:
+		}
+		return make(chan uint64), nil
+	}).MinTimes(2)
+	tracker := NewTracker(chain, bbnClient, chain, cfg, zap.NewNop())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		tracker.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	// the stale subscription is ended, and the tracker subscribes again
+	var subCtxs []context.Context
+	for len(subCtxs) < 2 {
+		select {
+		case subCtx := <-subscriptions:
+			subCtxs = append(subCtxs, subCtx)
+		case <-time.After(5 * time.Second):
+			t.Fatal("the tracker did not subscribe again")
+		}
+	}
+	require.ErrorIs(t, subCtxs[0].Err(), context.Canceled)
+	require.NoError(t, subCtxs[1].Err())
+}
+
+func TestTrackerUnsubscribe(t *testing.T) {
+	chain := &fakeL2Chain{head: 10, finalizedHeight: 10}
+	cfg := &TrackerConfig{StartHeight: 1, PollInterval: time.Hour, MaxRangeSize: 100}
+	ctl := gomock.NewController(t)
+	bbnClient := mocks.NewMockIBabylonClient(ctl)
+	bbnClient.EXPECT().SubscribeNewBlocks(gomock.Any()).Return(make(chan uint64), nil).Times(1)
+	tracker := NewTracker(chain, bbnClient, chain, cfg, zap.NewNop())
+
+	trackerCtx, stopTracker := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		tracker.Run(trackerCtx)
+		close(done)
+	}()
+
+	// the channel is closed once the context of the subscription is done
+	ctx, cancel := context.WithCancel(context.Background())
+	events := tracker.Subscribe(ctx)
+	requireEvent(t, events, 10)
+	cancel()
+	require.Eventually(t, func() bool {
+		_, ok := <-events
+		return !ok
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// or once the tracker stops
+	events = tracker.Subscribe(context.Background())
+	stopTracker()
+	<-done
+	for range events {
+	}
+	_, ok := <-tracker.Subscribe(context.Background())
+	require.False(t, ok)
+}
+
+func TestSdkClientSubscribe(t *testing.T) {
+	ctl := gomock.NewController(t)
+	bbnClient := mocks.NewMockIBabylonClient(ctl)
+	bbnClient.EXPECT().SubscribeNewBlocks(gomock.Any()).Return(make(chan uint64), nil).AnyTimes()
+	// every block is finalized while the finality gadget is disabled
+	cwClient := mocks.NewMockICosmWasmClient(ctl)
+	cwClient.EXPECT().QueryIsEnabled(gomock.Any()).Return(false, nil).AnyTimes()
+	sdkClient := &SdkClient{bbnClient: bbnClient, cwClient: cwClient, logger: zap.NewNop()}
+
+	_, err := sdkClient.Subscribe(context.Background())
+	require.ErrorIs(t, err, ErrTrackerNotStarted)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	chain := &fakeL2Chain{head: 10}
+	require.ErrorContains(t, sdkClient.StartTracker(ctx, chain, &TrackerConfig{}), "poll interval must be positive")
+	require.NoError(t, sdkClient.StartTracker(ctx, chain, DefaultTrackerConfig()))
+	require.ErrorContains(t, sdkClient.StartTracker(ctx, chain, DefaultTrackerConfig()), "already started")
+
+	events, err := sdkClient.Subscribe(ctx)
+	require.NoError(t, err)
+	requireEvent(t, events, 10)
+}
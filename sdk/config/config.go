@@ -3,6 +3,9 @@ package config
 import (
 	"fmt"
 
+	"github.com/btcsuite/btcd/chaincfg"
+
+	"github.com/babylonchain/babylon-finality-gadget/sdk/bbnclient"
 	"github.com/babylonchain/babylon-finality-gadget/sdk/btcclient"
 )
 
@@ -11,13 +14,65 @@ const (
 	BabylonDevnet   = "euphrates-0.2.0"
 )
 
+const (
+	// by default, an L2 block is finalized once FPs with 2/3 of the total voting power voted for it
+	DefaultQuorumNumerator   = 2
+	DefaultQuorumDenominator = 3
+)
+
+const (
+	// RangeSearchLinear scans a block range from low to high and stops at the first block that is not finalized
+	RangeSearchLinear = "linear"
+	// RangeSearchBisect bisects a block range to find the last finalized block in O(log n) block checks,
+	// assuming that finality is monotone along the range
+	RangeSearchBisect = "bisect"
+)
+
+const (
+	// BTCBackendBitcoind fetches the BTC block headers from the bitcoind RPC of BTCConfig
+	BTCBackendBitcoind = "bitcoind"
+	// BTCBackendBabylon fetches the BTC block headers from the BTC light client of Babylon, so that no
+	// Bitcoin node is needed
+	BTCBackendBabylon = "babylon"
+	// BTCBackendEsplora fetches the BTC block headers from the Esplora HTTP API at BTCConfig.EsploraURL
+	BTCBackendEsplora = "esplora"
+)
+
+// the BTC networks, named as the btcd chain parameters except for testnet3
+const (
+	BTCNetworkMainnet = "mainnet"
+	BTCNetworkTestnet = "testnet"
+	BTCNetworkSignet  = "signet"
+	BTCNetworkRegtest = "regtest"
+)
+
 // Config defines configuration for the Babylon query client
 type Config struct {
-	BTCConfig    *btcclient.BTCConfig
-	ContractAddr string // CosmWasm contract address
-	// TODO: add Config.Validate() to query chain ID (i.e. /status) from RPCAddr and compare
-	ChainID string // Chain ID of the Babylon chain (e.g. devnet, testnet, mainnet)
-	RPCAddr string // RPC address of the Babylon chain
+	// optional, btcclient.DefaultBTCConfig() is used if nil
+	BTCConfig *btcclient.BTCConfig `mapstructure:"btc"`
+	// optional, bbnclient.DefaultBBNConfig() is used if nil
+	BBNConfig    *bbnclient.BBNConfig `mapstructure:"babylon"`
+	ContractAddr string               `mapstructure:"contract-addr"` // CosmWasm contract address
+	ChainID      string               `mapstructure:"chain-id"`      // Chain ID of the Babylon chain (e.g. devnet, testnet, mainnet)
+	RPCAddr      string               `mapstructure:"rpc-addr"`      // RPC address of the Babylon chain
+	// RPCAddrs are the RPC addresses of the Babylon chain, queried with failover. RPCAddr is ignored if set
+	RPCAddrs []string `mapstructure:"rpc-addrs"`
+	// An L2 block is finalized if the voted voting power is at least QuorumNumerator/QuorumDenominator
+	// of the total voting power, or more than half of it if the threshold is 1/2. Leave both unset to use
+	// the default 2/3
+	QuorumNumerator   uint64 `mapstructure:"quorum-numerator"`
+	QuorumDenominator uint64 `mapstructure:"quorum-denominator"`
+	// RangeSearch is the strategy used to find the last finalized block of a block range, either
+	// RangeSearchLinear or RangeSearchBisect. Leave unset to use RangeSearchLinear
+	RangeSearch string `mapstructure:"range-search"`
+	// BTCBackend is the source of the BTC block headers, one of BTCBackendBitcoind, BTCBackendBabylon or
+	// BTCBackendEsplora. Leave unset to use BTCBackendBitcoind
+	BTCBackend string `mapstructure:"btc-backend"`
+	// BTCNetwork is the network the BTC backend is expected to follow, one of BTCNetworkMainnet,
+	// BTCNetworkTestnet, BTCNetworkSignet or BTCNetworkRegtest. Leave unset to skip the check of Validate
+	BTCNetwork string `mapstructure:"btc-network"`
+	// ValidateOnStart makes NewClient check the config against the live Babylon node and BTC backend with Validate
+	ValidateOnStart bool `mapstructure:"validate-on-start"`
 }
 
 func (config *Config) GetRpcAddr() (string, error) {
@@ -27,6 +82,19 @@ func (config *Config) GetRpcAddr() (string, error) {
 	return config.getDefaultRpcAddr()
 }
 
+// GetRpcAddrs returns the RPC addresses of the Babylon chain, i.e. RPCAddrs if set, otherwise the single address
+// of GetRpcAddr
+func (config *Config) GetRpcAddrs() ([]string, error) {
+	if len(config.RPCAddrs) > 0 {
+		return config.RPCAddrs, nil
+	}
+	rpcAddr, err := config.GetRpcAddr()
+	if err != nil {
+		return nil, err
+	}
+	return []string{rpcAddr}, nil
+}
+
 func (config *Config) getDefaultRpcAddr() (string, error) {
 	switch config.ChainID {
 	case BabylonLocalnet:
@@ -39,3 +107,85 @@ func (config *Config) getDefaultRpcAddr() (string, error) {
 		return "", fmt.Errorf("unrecognized chain id: %s", config.ChainID)
 	}
 }
+
+// GetQuorum returns the finality quorum threshold as a fraction numerator/denominator of the total voting power
+//
+// the threshold must be within [1/2, 1]. A threshold of 1/2 is a simple majority, i.e. the voted power must be
+// strictly more than half of the total power, so that two conflicting L2 blocks at the same height cannot both reach
+// the threshold, e.g. with a 50/50 split of the voting power
+func (config *Config) GetQuorum() (uint64, uint64, error) {
+	if config.QuorumNumerator == 0 && config.QuorumDenominator == 0 {
+		return DefaultQuorumNumerator, DefaultQuorumDenominator, nil
+	}
+	if config.QuorumNumerator == 0 || config.QuorumDenominator == 0 {
+		return 0, 0, fmt.Errorf("invalid quorum %d/%d: numerator and denominator must be positive",
+			config.QuorumNumerator, config.QuorumDenominator)
+	}
+	if config.QuorumNumerator > config.QuorumDenominator {
+		return 0, 0, fmt.Errorf("invalid quorum %d/%d: must not exceed 1",
+			config.QuorumNumerator, config.QuorumDenominator)
+	}
+	// numerator/denominator < 1/2, compared without overflowing uint64
+	if config.QuorumNumerator < config.QuorumDenominator-config.QuorumNumerator {
+		return 0, 0, fmt.Errorf("invalid quorum %d/%d: must be at least 1/2",
+			config.QuorumNumerator, config.QuorumDenominator)
+	}
+	return config.QuorumNumerator, config.QuorumDenominator, nil
+}
+
+// GetRangeSearch returns the strategy used to find the last finalized block of a block range
+func (config *Config) GetRangeSearch() (string, error) {
+	switch config.RangeSearch {
+	case "":
+		return RangeSearchLinear, nil
+	case RangeSearchLinear, RangeSearchBisect:
+		return config.RangeSearch, nil
+	default:
+		return "", fmt.Errorf("unrecognized range search strategy: %s", config.RangeSearch)
+	}
+}
+
+// GetBTCBackend returns the source of the BTC block headers
+func (config *Config) GetBTCBackend() (string, error) {
+	switch config.BTCBackend {
+	case "":
+		return BTCBackendBitcoind, nil
+	case BTCBackendBitcoind, BTCBackendBabylon, BTCBackendEsplora:
+		return config.BTCBackend, nil
+	default:
+		return "", fmt.Errorf("unrecognized BTC backend: %s", config.BTCBackend)
+	}
+}
+
+// GetBTCNetParams returns the parameters of the network the BTC backend is expected to follow, or nil if
+// BTCNetwork is not set
+func (config *Config) GetBTCNetParams() (*chaincfg.Params, error) {
+	switch config.BTCNetwork {
+	case "":
+		return nil, nil
+	case BTCNetworkMainnet:
+		return &chaincfg.MainNetParams, nil
+	case BTCNetworkTestnet:
+		return &chaincfg.TestNet3Params, nil
+	case BTCNetworkSignet:
+		return &chaincfg.SigNetParams, nil
+	case BTCNetworkRegtest:
+		return &chaincfg.RegressionNetParams, nil
+	default:
+		return nil, fmt.Errorf("unrecognized BTC network: %s", config.BTCNetwork)
+	}
+}
+
+func (config *Config) GetBTCConfig() *btcclient.BTCConfig {
+	if config.BTCConfig != nil {
+		return config.BTCConfig
+	}
+	return btcclient.DefaultBTCConfig()
+}
+
+func (config *Config) GetBBNConfig() *bbnclient.BBNConfig {
+	if config.BBNConfig != nil {
+		return config.BBNConfig
+	}
+	return bbnclient.DefaultBBNConfig()
+}
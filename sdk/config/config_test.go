@@ -0,0 +1,159 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetQuorum(t *testing.T) {
+	testCases := []struct {
+		name                string
+		numerator           uint64
+		denominator         uint64
+		expectedNumerator   uint64
+		expectedDenominator uint64
+		expectErr           bool
+	}{
+		{"unset uses default 2/3", 0, 0, 2, 3, false},
+		{"just above 1/2", 51, 100, 51, 100, false},
+		{"3/4", 3, 4, 3, 4, false},
+		{"unanimity", 1, 1, 1, 1, false},
+		{"zero numerator", 0, 3, 0, 0, true},
+		{"zero denominator", 2, 0, 0, 0, true},
+		{"above 1", 4, 3, 0, 0, true},
+		{"below 1/2", 1, 3, 0, 0, true},
+		{"simple majority", 1, 2, 1, 2, false},
+		{"simple majority unreduced", 50, 100, 50, 100, false},
+		{"just below 1/2", 49, 100, 0, 0, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &Config{QuorumNumerator: tc.numerator, QuorumDenominator: tc.denominator}
+			numerator, denominator, err := config.GetQuorum()
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedNumerator, numerator)
+			require.Equal(t, tc.expectedDenominator, denominator)
+		})
+	}
+}
+
+func TestGetRangeSearch(t *testing.T) {
+	testCases := []struct {
+		name                string
+		rangeSearch         string
+		expectedRangeSearch string
+		expectErr           bool
+	}{
+		{"unset uses linear", "", RangeSearchLinear, false},
+		{"linear", RangeSearchLinear, RangeSearchLinear, false},
+		{"bisect", RangeSearchBisect, RangeSearchBisect, false},
+		{"unrecognized", "binary", "", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &Config{RangeSearch: tc.rangeSearch}
+			rangeSearch, err := config.GetRangeSearch()
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedRangeSearch, rangeSearch)
+		})
+	}
+}
+
+func TestGetBTCBackend(t *testing.T) {
+	testCases := []struct {
+		name               string
+		btcBackend         string
+		expectedBTCBackend string
+		expectErr          bool
+	}{
+		{"unset uses bitcoind", "", BTCBackendBitcoind, false},
+		{"bitcoind", BTCBackendBitcoind, BTCBackendBitcoind, false},
+		{"babylon", BTCBackendBabylon, BTCBackendBabylon, false},
+		{"esplora", BTCBackendEsplora, BTCBackendEsplora, false},
+		{"unrecognized", "electrum", "", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &Config{BTCBackend: tc.btcBackend}
+			btcBackend, err := config.GetBTCBackend()
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedBTCBackend, btcBackend)
+		})
+	}
+}
+
+func TestGetRpcAddrs(t *testing.T) {
+	testCases := []struct {
+		name          string
+		config        *Config
+		expectedAddrs []string
+		expectErr     bool
+	}{
+		{"RPCAddrs", &Config{RPCAddrs: []string{"http://a:26657", "http://b:26657"}, RPCAddr: "http://c:26657"},
+			[]string{"http://a:26657", "http://b:26657"}, false},
+		{"RPCAddr", &Config{RPCAddr: "http://c:26657"}, []string{"http://c:26657"}, false},
+		{"default of the chain", &Config{ChainID: BabylonLocalnet}, []string{"http://127.0.0.1:26657"}, false},
+		{"unrecognized chain", &Config{ChainID: "unknown"}, nil, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			addrs, err := tc.config.GetRpcAddrs()
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedAddrs, addrs)
+		})
+	}
+}
+
+func TestGetBTCNetParams(t *testing.T) {
+	testCases := []struct {
+		name            string
+		btcNetwork      string
+		expectedNetwork string
+		expectErr       bool
+	}{
+		{"unset is not checked", "", "", false},
+		{"mainnet", BTCNetworkMainnet, "mainnet", false},
+		{"testnet", BTCNetworkTestnet, "testnet3", false},
+		{"signet", BTCNetworkSignet, "signet", false},
+		{"regtest", BTCNetworkRegtest, "regtest", false},
+		{"unrecognized", "testnet4", "", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &Config{BTCNetwork: tc.btcNetwork}
+			params, err := config.GetBTCNetParams()
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tc.expectedNetwork == "" {
+				require.Nil(t, params)
+				return
+			}
+			require.Equal(t, tc.expectedNetwork, params.Name)
+		})
+	}
+}
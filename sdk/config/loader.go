@@ -0,0 +1,122 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/babylonchain/babylon-finality-gadget/sdk/bbnclient"
+	"github.com/babylonchain/babylon-finality-gadget/sdk/btcclient"
+)
+
+// EnvPrefix is the prefix of the environment variables read by LoadConfig
+const EnvPrefix = "BFG"
+
+// DefaultConfig returns the config used for the settings that are not set, which still needs the contract address
+// and the chain ID
+func DefaultConfig() *Config {
+	return &Config{
+		BTCConfig:         btcclient.DefaultBTCConfig(),
+		BBNConfig:         bbnclient.DefaultBBNConfig(),
+		QuorumNumerator:   DefaultQuorumNumerator,
+		QuorumDenominator: DefaultQuorumDenominator,
+		RangeSearch:       RangeSearchLinear,
+		BTCBackend:        BTCBackendBitcoind,
+	}
+}
+
+// LoadConfig loads the config from the file at path, if not empty, and from the environment variables, on top of
+// DefaultConfig, and checks the settings
+//
+//   - the file format, i.e. TOML or YAML, is inferred from the file extension
+//   - the environment variables take precedence over the file. The variable of a setting is EnvPrefix followed by
+//     its key in upper case, with "." and "-" replaced by "_", e.g. BFG_CHAIN_ID or BFG_BTC_RPCHOST. Lists are
+//     comma-separated, e.g. BFG_RPC_ADDRS=http://a:26657,http://b:26657
+func LoadConfig(path string) (*Config, error) {
+	v, err := newViper()
+	if err != nil {
+		return nil, err
+	}
+	if path != "" {
+		v.SetConfigFile(path)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+		}
+	}
+
+	config := &Config{}
+	if err := v.Unmarshal(config); err != nil {
+		return nil, fmt.Errorf("failed to decode config: %w", err)
+	}
+	if err := config.validateSettings(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	return config, nil
+}
+
+// WriteSampleConfig writes DefaultConfig to a new file at path, in the format inferred from the file extension,
+// i.e. TOML or YAML. It does not overwrite an existing file
+func WriteSampleConfig(path string) error {
+	v, err := newViper()
+	if err != nil {
+		return err
+	}
+	if err := v.SafeWriteConfigAs(path); err != nil {
+		return fmt.Errorf("failed to write sample config %s: %w", path, err)
+	}
+	return nil
+}
+
+// newViper returns a viper instance that knows all the keys of Config, with the values of DefaultConfig as
+// defaults, and reads them from the environment variables
+func newViper() (*viper.Viper, error) {
+	v := viper.New()
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
+	v.AutomaticEnv()
+	// viper only reads the environment variables of the keys it knows, so every key gets a default
+	if err := setDefaults(v, "", reflect.ValueOf(DefaultConfig())); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// setDefaults sets the value of each field of the struct as the default of its mapstructure key, recursing into
+// the nested structs
+func setDefaults(v *viper.Viper, prefix string, value reflect.Value) error {
+	if value.Kind() == reflect.Pointer {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return fmt.Errorf("cannot set the defaults of %s: not a struct", value.Type())
+	}
+
+	for i := 0; i < value.NumField(); i++ {
+		key := value.Type().Field(i).Tag.Get("mapstructure")
+		if key == "" {
+			continue
+		}
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		field := value.Field(i)
+		switch {
+		case field.Kind() == reflect.Struct || field.Kind() == reflect.Pointer && field.Type().Elem().Kind() == reflect.Struct:
+			if err := setDefaults(v, key, field); err != nil {
+				return err
+			}
+		case field.Type() == reflect.TypeOf(time.Duration(0)):
+			// durations are written in their human readable form, e.g. 30s
+			v.SetDefault(key, field.Interface().(time.Duration).String())
+		case field.Kind() == reflect.Slice && field.IsNil():
+			v.SetDefault(key, reflect.MakeSlice(field.Type(), 0, 0).Interface())
+		default:
+			v.SetDefault(key, field.Interface())
+		}
+	}
+	return nil
+}
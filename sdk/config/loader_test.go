@@ -0,0 +1,147 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadConfigFromFile(t *testing.T) {
+	testCases := []struct {
+		name     string
+		fileName string
+		content  string
+	}{
+		{"toml", "config.toml", `
+contract-addr = "bbn1contract"
+chain-id = "euphrates-0.2.0"
+rpc-addrs = ["http://a:26657", "http://b:26657"]
+quorum-numerator = 3
+quorum-denominator = 4
+btc-backend = "esplora"
+
+[btc]
+esplora-url = "https://mempool.space/api"
+endpoint-timeout = "3s"
+
+[babylon]
+page-size = 50
+`},
+		{"yaml", "config.yaml", `
+contract-addr: bbn1contract
+chain-id: euphrates-0.2.0
+rpc-addrs:
+  - http://a:26657
+  - http://b:26657
+quorum-numerator: 3
+quorum-denominator: 4
+btc-backend: esplora
+btc:
+  esplora-url: https://mempool.space/api
+  endpoint-timeout: 3s
+babylon:
+  page-size: 50
+`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config, err := LoadConfig(writeConfigFile(t, tc.fileName, tc.content))
+			require.NoError(t, err)
+
+			expected := DefaultConfig()
+			expected.ContractAddr = "bbn1contract"
+			expected.ChainID = BabylonDevnet
+			expected.RPCAddrs = []string{"http://a:26657", "http://b:26657"}
+			expected.QuorumNumerator, expected.QuorumDenominator = 3, 4
+			expected.BTCBackend = BTCBackendEsplora
+			expected.BTCConfig.EsploraURL = "https://mempool.space/api"
+			expected.BTCConfig.EndpointTimeout = 3 * time.Second
+			expected.BTCConfig.RPCHosts = []string{}
+			expected.BBNConfig.PageSize = 50
+			require.Equal(t, expected, config)
+		})
+	}
+}
+
+func TestLoadConfigFromEnv(t *testing.T) {
+	path := writeConfigFile(t, "config.toml", `
+contract-addr = "bbn1contract"
+chain-id = "euphrates-0.2.0"
+
+[btc]
+rpchost = "localhost:38332"
+`)
+	t.Setenv("BFG_CHAIN_ID", BabylonLocalnet)
+	t.Setenv("BFG_RPC_ADDRS", "http://a:26657,http://b:26657")
+	t.Setenv("BFG_RANGE_SEARCH", RangeSearchBisect)
+	t.Setenv("BFG_BTC_RPCHOST", "localhost:18443")
+	t.Setenv("BFG_BTC_RETRY_INTERVAL", "2s")
+	t.Setenv("BFG_BABYLON_MAX_CONCURRENCY", "4")
+
+	config, err := LoadConfig(path)
+	require.NoError(t, err)
+	// the environment variables take precedence over the file
+	require.Equal(t, "bbn1contract", config.ContractAddr)
+	require.Equal(t, BabylonLocalnet, config.ChainID)
+	require.Equal(t, []string{"http://a:26657", "http://b:26657"}, config.RPCAddrs)
+	require.Equal(t, RangeSearchBisect, config.RangeSearch)
+	require.Equal(t, "localhost:18443", config.BTCConfig.RPCHost)
+	require.Equal(t, 2*time.Second, config.BTCConfig.RetryInterval)
+	require.Equal(t, 4, config.BBNConfig.MaxConcurrency)
+	// the other settings keep their defaults
+	require.Equal(t, BTCBackendBitcoind, config.BTCBackend)
+	require.Equal(t, DefaultConfig().BBNConfig.PageSize, config.BBNConfig.PageSize)
+}
+
+func TestLoadConfigInvalid(t *testing.T) {
+	path := writeConfigFile(t, "config.toml", `
+quorum-numerator = 1
+quorum-denominator = 3
+range-search = "binary"
+btc-network = "testnet4"
+`)
+
+	_, err := LoadConfig(path)
+	require.ErrorContains(t, err, "the contract address is not set")
+	require.ErrorContains(t, err, "unrecognized chain id")
+	require.ErrorContains(t, err, "invalid quorum 1/3")
+	require.ErrorContains(t, err, "unrecognized range search strategy: binary")
+	require.ErrorContains(t, err, "unrecognized BTC network: testnet4")
+
+	_, err = LoadConfig(filepath.Join(t.TempDir(), "missing.toml"))
+	require.ErrorContains(t, err, "failed to read config file")
+}
+
+func TestWriteSampleConfig(t *testing.T) {
+	for _, fileName := range []string{"sample.toml", "sample.yaml"} {
+		t.Run(fileName, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), fileName)
+			require.NoError(t, WriteSampleConfig(path))
+			// an existing file is not overwritten
+			require.Error(t, WriteSampleConfig(path))
+
+			// the sample loads back into the default config once the required settings are set
+			t.Setenv("BFG_CONTRACT_ADDR", "bbn1contract")
+			t.Setenv("BFG_CHAIN_ID", BabylonDevnet)
+			config, err := LoadConfig(path)
+			require.NoError(t, err)
+
+			expected := DefaultConfig()
+			expected.ContractAddr = "bbn1contract"
+			expected.ChainID = BabylonDevnet
+			expected.RPCAddrs = []string{}
+			expected.BTCConfig.RPCHosts = []string{}
+			require.Equal(t, expected, config)
+		})
+	}
+}
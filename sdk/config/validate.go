@@ -0,0 +1,126 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+
+	"github.com/babylonchain/babylon-finality-gadget/sdk/bbnclient"
+	"github.com/babylonchain/babylon-finality-gadget/sdk/btcclient"
+	"github.com/babylonchain/babylon-finality-gadget/sdk/cwclient"
+)
+
+// BabylonAddressPrefix is the bech32 prefix of the Babylon addresses
+const BabylonAddressPrefix = "bbn"
+
+// Validate checks the settings of the config, and checks them against the live Babylon node and BTC backend.
+// It runs all the checks, and returns the failed ones joined into one error
+//
+//   - the network of the Babylon node, i.e. its /status, matches ChainID
+//   - ContractAddr is a Babylon bech32 address with wasm code deployed, and the contract answers the config query
+//   - if BTCNetwork is set and btcClient is not nil, the genesis block of the BTC backend is the one of BTCNetwork
+func (config *Config) Validate(ctx context.Context, rpcClient bbnclient.RPCClient, btcClient btcclient.HeaderSource) error {
+	var errs []error
+	if err := config.validateSettings(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := config.validateChainID(ctx, rpcClient); err != nil {
+		errs = append(errs, err)
+	}
+	if err := config.validateContract(ctx, rpcClient); err != nil {
+		errs = append(errs, err)
+	}
+	if err := config.validateBTCNetwork(ctx, btcClient); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// validateSettings checks the settings that do not need the live Babylon node or BTC backend, and returns the
+// failed checks joined into one error
+func (config *Config) validateSettings() error {
+	var errs []error
+	if config.ContractAddr == "" {
+		errs = append(errs, fmt.Errorf("the contract address is not set"))
+	}
+	if _, err := config.GetRpcAddrs(); err != nil {
+		errs = append(errs, err)
+	}
+	if _, _, err := config.GetQuorum(); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := config.GetRangeSearch(); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := config.GetBTCBackend(); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := config.GetBTCNetParams(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// validateChainID checks that the Babylon node is on the network of ChainID
+func (config *Config) validateChainID(ctx context.Context, rpcClient bbnclient.RPCClient) error {
+	if config.ChainID == "" {
+		return fmt.Errorf("the chain ID is not set")
+	}
+	status, err := rpcClient.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to query the status of the Babylon node: %w", err)
+	}
+	if status.NodeInfo.Network != config.ChainID {
+		return fmt.Errorf("the Babylon node is on chain %s, expected %s", status.NodeInfo.Network, config.ChainID)
+	}
+	return nil
+}
+
+// validateContract checks that a contract answering the config query is deployed at ContractAddr
+func (config *Config) validateContract(ctx context.Context, rpcClient bbnclient.RPCClient) error {
+	// an unset contract address is reported by validateSettings
+	if config.ContractAddr == "" {
+		return nil
+	}
+	prefix, _, err := bech32.DecodeAndConvert(config.ContractAddr)
+	if err != nil {
+		return fmt.Errorf("invalid contract address %q: %w", config.ContractAddr, err)
+	}
+	if prefix != BabylonAddressPrefix {
+		return fmt.Errorf("invalid contract address %s: prefix %s, expected %s",
+			config.ContractAddr, prefix, BabylonAddressPrefix)
+	}
+
+	cwClient := cwclient.NewClient(rpcClient, config.ContractAddr)
+	if _, err := cwClient.QueryCodeId(ctx); err != nil {
+		return fmt.Errorf("failed to query the contract %s: %w", config.ContractAddr, err)
+	}
+	consumerId, err := cwClient.QueryConsumerId(ctx)
+	if err != nil {
+		return fmt.Errorf("the contract %s does not answer the config query: %w", config.ContractAddr, err)
+	}
+	if consumerId == "" {
+		return fmt.Errorf("the contract %s has no consumer ID", config.ContractAddr)
+	}
+	return nil
+}
+
+// validateBTCNetwork checks that the BTC backend follows BTCNetwork, by comparing the genesis block hashes
+func (config *Config) validateBTCNetwork(ctx context.Context, btcClient btcclient.HeaderSource) error {
+	// an invalid BTCNetwork is reported by validateSettings
+	params, err := config.GetBTCNetParams()
+	if err != nil || params == nil || btcClient == nil {
+		return nil
+	}
+	genesisHash, err := btcClient.GetBlockHashByHeight(ctx, 0)
+	if err != nil {
+		return fmt.Errorf("failed to query the BTC genesis block: %w", err)
+	}
+	if !genesisHash.IsEqual(params.GenesisHash) {
+		return fmt.Errorf("the BTC backend has genesis block %s, expected the one of %s %s",
+			genesisHash.String(), config.BTCNetwork, params.GenesisHash.String())
+	}
+	return nil
+}
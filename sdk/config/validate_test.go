@@ -0,0 +1,153 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/libs/bytes"
+	"github.com/cometbft/cometbft/p2p"
+	rpcclient "github.com/cometbft/cometbft/rpc/client"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonchain/babylon-finality-gadget/sdk/btcclient"
+)
+
+// fakeBabylonNode serves the status of a Babylon node and the queries of a finality contract
+type fakeBabylonNode struct {
+	rpcclient.Client
+	network    string
+	codeId     uint64
+	consumerId string
+}
+
+func (n *fakeBabylonNode) Status(context.Context) (*ctypes.ResultStatus, error) {
+	return &ctypes.ResultStatus{NodeInfo: p2p.DefaultNodeInfo{Network: n.network}}, nil
+}
+
+func (n *fakeBabylonNode) ABCIQueryWithOptions(
+	_ context.Context,
+	path string,
+	_ bytes.HexBytes,
+	_ rpcclient.ABCIQueryOptions,
+) (*ctypes.ResultABCIQuery, error) {
+	var value []byte
+	var err error
+	switch path {
+	case "/cosmwasm.wasm.v1.Query/ContractInfo":
+		if n.codeId == 0 {
+			return &ctypes.ResultABCIQuery{Response: abci.ResponseQuery{Code: 1, Log: "not found"}}, nil
+		}
+		value, err = (&wasmtypes.QueryContractInfoResponse{ContractInfo: wasmtypes.ContractInfo{CodeID: n.codeId}}).Marshal()
+	case "/cosmwasm.wasm.v1.Query/SmartContractState":
+		data := []byte(fmt.Sprintf(`{"consumer_id":%q,"activated_height":1}`, n.consumerId))
+		value, err = (&wasmtypes.QuerySmartContractStateResponse{Data: data}).Marshal()
+	default:
+		return nil, fmt.Errorf("unexpected query %s", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ctypes.ResultABCIQuery{Response: abci.ResponseQuery{Value: value}}, nil
+}
+
+// fakeBTCNode serves the genesis block hash of a BTC network
+type fakeBTCNode struct {
+	genesisHash *chainhash.Hash
+}
+
+func (n *fakeBTCNode) GetBlockCount(context.Context) (uint64, error) {
+	return 0, nil
+}
+
+func (n *fakeBTCNode) GetBlockHashByHeight(_ context.Context, height uint64) (*chainhash.Hash, error) {
+	if height != 0 {
+		return nil, fmt.Errorf("unexpected height %d", height)
+	}
+	return n.genesisHash, nil
+}
+
+func (n *fakeBTCNode) GetBlockHeaderByHash(context.Context, *chainhash.Hash) (*wire.BlockHeader, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func TestValidate(t *testing.T) {
+	contractAddr, err := bech32.ConvertAndEncode(BabylonAddressPrefix, make([]byte, 32))
+	require.NoError(t, err)
+	otherAddr, err := bech32.ConvertAndEncode("cosmos", make([]byte, 32))
+	require.NoError(t, err)
+
+	validConfig := func() *Config {
+		return &Config{ChainID: BabylonDevnet, ContractAddr: contractAddr, BTCNetwork: BTCNetworkSignet}
+	}
+	validNode := func() *fakeBabylonNode {
+		return &fakeBabylonNode{network: BabylonDevnet, codeId: 1, consumerId: "op-stack-l2-706114"}
+	}
+	signet := &fakeBTCNode{genesisHash: chaincfg.SigNetParams.GenesisHash}
+
+	testCases := []struct {
+		name         string
+		modify       func(config *Config, node *fakeBabylonNode)
+		btcNode      *fakeBTCNode
+		expectedErrs []string
+	}{
+		{"valid", func(*Config, *fakeBabylonNode) {}, signet, nil},
+		{"BTC network unchecked", func(config *Config, _ *fakeBabylonNode) { config.BTCNetwork = "" },
+			&fakeBTCNode{genesisHash: chaincfg.MainNetParams.GenesisHash}, nil},
+		{"no BTC client", func(*Config, *fakeBabylonNode) {}, nil, nil},
+		{"chain ID mismatch", func(_ *Config, node *fakeBabylonNode) { node.network = "bbn-test-5" }, signet,
+			[]string{"the Babylon node is on chain bbn-test-5, expected euphrates-0.2.0"}},
+		{"chain ID unset", func(config *Config, _ *fakeBabylonNode) { config.ChainID = "" }, signet,
+			[]string{"the chain ID is not set"}},
+		{"invalid contract address", func(config *Config, _ *fakeBabylonNode) { config.ContractAddr = "bbn1xyz" },
+			signet, []string{"invalid contract address"}},
+		{"contract address of another chain", func(config *Config, _ *fakeBabylonNode) { config.ContractAddr = otherAddr },
+			signet, []string{"prefix cosmos, expected bbn"}},
+		{"no contract", func(_ *Config, node *fakeBabylonNode) { node.codeId = 0 }, signet,
+			[]string{"failed to query the contract"}},
+		{"no consumer ID", func(_ *Config, node *fakeBabylonNode) { node.consumerId = "" }, signet,
+			[]string{"has no consumer ID"}},
+		{"BTC network mismatch", func(*Config, *fakeBabylonNode) {},
+			&fakeBTCNode{genesisHash: chaincfg.MainNetParams.GenesisHash},
+			[]string{"expected the one of signet"}},
+		{"unrecognized BTC network", func(config *Config, _ *fakeBabylonNode) { config.BTCNetwork = "testnet4" },
+			signet, []string{"unrecognized BTC network: testnet4"}},
+		{"all failed checks are reported", func(config *Config, node *fakeBabylonNode) {
+			config.RangeSearch = "binary"
+			node.network = "bbn-test-5"
+			node.codeId = 0
+		}, &fakeBTCNode{genesisHash: chaincfg.MainNetParams.GenesisHash}, []string{
+			"unrecognized range search strategy: binary",
+			"the Babylon node is on chain bbn-test-5",
+			"failed to query the contract",
+			"expected the one of signet",
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config, node := validConfig(), validNode()
+			tc.modify(config, node)
+			var btcClient btcclient.HeaderSource
+			if tc.btcNode != nil {
+				btcClient = tc.btcNode
+			}
+
+			err := config.Validate(context.Background(), node, btcClient)
+			if len(tc.expectedErrs) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			for _, expectedErr := range tc.expectedErrs {
+				require.ErrorContains(t, err, expectedErr)
+			}
+		})
+	}
+}
@@ -1,24 +1,42 @@
 package cwclient
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	"github.com/cometbft/cometbft/libs/bytes"
 	rpcclient "github.com/cometbft/cometbft/rpc/client"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+
+	"github.com/babylonchain/babylon-finality-gadget/sdk/internal/abciquery"
 )
 
+// RPCClient is the part of the CometBFT RPC client the contract queries are sent over
+type RPCClient interface {
+	ABCIQueryWithOptions(
+		ctx context.Context,
+		path string,
+		data bytes.HexBytes,
+		opts rpcclient.ABCIQueryOptions,
+	) (*ctypes.ResultABCIQuery, error)
+}
+
 type Client struct {
-	rpcclient.Client
+	RPCClient
 	contractAddr string
 }
 
-func NewClient(rpcClient rpcclient.Client, contractAddr string) *Client {
+func NewClient(rpcClient RPCClient, contractAddr string) *Client {
 	return &Client{
-		Client:       rpcClient,
+		RPCClient:    rpcClient,
 		contractAddr: contractAddr,
 	}
 }
 
 func (cwClient *Client) QueryListOfVotedFinalityProviders(
+	ctx context.Context,
 	queryParams *L2Block,
 ) ([]string, error) {
 	queryData, err := createBlockVotersQueryData(queryParams)
@@ -26,7 +44,7 @@ func (cwClient *Client) QueryListOfVotedFinalityProviders(
 		return nil, err
 	}
 
-	resp, err := cwClient.querySmartContractState(queryData)
+	resp, err := cwClient.querySmartContractState(ctx, queryData)
 	if err != nil {
 		return nil, err
 	}
@@ -39,13 +57,13 @@ func (cwClient *Client) QueryListOfVotedFinalityProviders(
 	return *votedFpPkHexList, nil
 }
 
-func (cwClient *Client) QueryConsumerId() (string, error) {
+func (cwClient *Client) QueryConsumerId(ctx context.Context) (string, error) {
 	queryData, err := createConfigQueryData()
 	if err != nil {
 		return "", err
 	}
 
-	resp, err := cwClient.querySmartContractState(queryData)
+	resp, err := cwClient.querySmartContractState(ctx, queryData)
 	if err != nil {
 		return "", err
 	}
@@ -58,13 +76,13 @@ func (cwClient *Client) QueryConsumerId() (string, error) {
 	return data.ConsumerId, nil
 }
 
-func (cwClient *Client) QueryIsEnabled() (bool, error) {
+func (cwClient *Client) QueryIsEnabled(ctx context.Context) (bool, error) {
 	queryData, err := createIsEnabledQueryData()
 	if err != nil {
 		return false, err
 	}
 
-	resp, err := cwClient.querySmartContractState(queryData)
+	resp, err := cwClient.querySmartContractState(ctx, queryData)
 	if err != nil {
 		return false, err
 	}
@@ -76,3 +94,38 @@ func (cwClient *Client) QueryIsEnabled() (bool, error) {
 
 	return isEnabled, nil
 }
+
+// QueryEvidence returns the equivocation evidence of the FP at the given L2 block height,
+// or nil if the FP has not voted conflicting block hashes at this height
+func (cwClient *Client) QueryEvidence(ctx context.Context, fpPubkeyHex string, height uint64) (*Evidence, error) {
+	queryData, err := createEvidenceQueryData(fpPubkeyHex, height)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := cwClient.querySmartContractState(ctx, queryData)
+	if err != nil {
+		return nil, err
+	}
+
+	var evidence *Evidence
+	if err := json.Unmarshal(resp.Data, &evidence); err != nil {
+		return nil, err
+	}
+
+	return evidence, nil
+}
+
+// QueryCodeId returns the ID of the wasm code of the contract, i.e. it fails if no contract is deployed at the
+// contract address
+func (cwClient *Client) QueryCodeId(ctx context.Context) (uint64, error) {
+	req := &wasmtypes.QueryContractInfoRequest{Address: cwClient.contractAddr}
+	resp := &wasmtypes.QueryContractInfoResponse{}
+	if err := abciquery.Query(ctx, cwClient.RPCClient, "/cosmwasm.wasm.v1.Query/ContractInfo", req, resp); err != nil {
+		return 0, err
+	}
+	if resp.CodeID == 0 {
+		return 0, fmt.Errorf("no wasm code is deployed at %s", cwClient.contractAddr)
+	}
+	return resp.CodeID, nil
+}
@@ -0,0 +1,51 @@
+package cwclient
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cometbft/cometbft/libs/bytes"
+	rpcclient "github.com/cometbft/cometbft/rpc/client"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// stalledRPCClient never answers the ABCI queries, until their context is done
+type stalledRPCClient struct {
+	rpcclient.Client
+	queries atomic.Int64
+}
+
+func (c *stalledRPCClient) ABCIQueryWithOptions(
+	ctx context.Context,
+	_ string,
+	_ bytes.HexBytes,
+	_ rpcclient.ABCIQueryOptions,
+) (*ctypes.ResultABCIQuery, error) {
+	c.queries.Add(1)
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestQueryCancel(t *testing.T) {
+	rpcClient := &stalledRPCClient{}
+	cwClient := NewClient(rpcClient, "bbn1contract")
+
+	// the cancellation of the caller reaches the in-flight RPC call
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := cwClient.QueryIsEnabled(ctx)
+		errCh <- err
+	}()
+	require.Eventually(t, func() bool { return rpcClient.queries.Load() == 1 }, 5*time.Second, time.Millisecond)
+	cancel()
+	select {
+	case err := <-errCh:
+		require.ErrorIs(t, err, context.Canceled)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the query is not aborted")
+	}
+}
@@ -3,14 +3,15 @@ package cwclient
 import (
 	"context"
 	"encoding/json"
-	"time"
 
 	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
-	cosmosclient "github.com/cosmos/cosmos-sdk/client"
+
+	"github.com/babylonchain/babylon-finality-gadget/sdk/internal/abciquery"
 )
 
-// hardcode the timeout to 20 seconds. We can expose it to the params once needed
-const DefaultTimeout = 20 * time.Second
+// DefaultTimeout bounds a contract query when the caller's context has no deadline.
+// We can expose it to the params once needed
+const DefaultTimeout = abciquery.DefaultTimeout
 
 func createBlockVotersQueryData(queryParams *L2Block) ([]byte, error) {
 	queryData := ContractQueryMsgs{
@@ -34,6 +35,7 @@ type ContractQueryMsgs struct {
 	Config      *contractConfig   `json:"config,omitempty"`
 	BlockVoters *blockVotersQuery `json:"block_voters,omitempty"`
 	IsEnabled   *isEnabledQuery   `json:"is_enabled,omitempty"`
+	Evidence    *evidenceQuery    `json:"evidence,omitempty"`
 }
 
 type blockVotersQuery struct {
@@ -43,6 +45,11 @@ type blockVotersQuery struct {
 
 type isEnabledQuery struct{}
 
+type evidenceQuery struct {
+	FpPubkeyHex string `json:"fp_pubkey_hex"`
+	Height      uint64 `json:"height"`
+}
+
 type contractConfig struct{}
 
 func createConfigQueryData() ([]byte, error) {
@@ -67,19 +74,32 @@ func createIsEnabledQueryData() ([]byte, error) {
 	return data, nil
 }
 
+func createEvidenceQueryData(fpPubkeyHex string, height uint64) ([]byte, error) {
+	queryData := ContractQueryMsgs{
+		Evidence: &evidenceQuery{
+			FpPubkeyHex: fpPubkeyHex,
+			Height:      height,
+		},
+	}
+	data, err := json.Marshal(queryData)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
 // querySmartContractState queries the smart contract state given the contract address and query data
 func (cwClient *Client) querySmartContractState(
+	ctx context.Context,
 	queryData []byte,
 ) (*wasmtypes.QuerySmartContractStateResponse, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
-	defer cancel()
-
-	sdkClientCtx := cosmosclient.Context{Client: cwClient.Client}
-	wasmQueryClient := wasmtypes.NewQueryClient(sdkClientCtx)
-
 	req := &wasmtypes.QuerySmartContractStateRequest{
 		Address:   cwClient.contractAddr,
 		QueryData: queryData,
 	}
-	return wasmQueryClient.SmartContractState(ctx, req)
+	resp := &wasmtypes.QuerySmartContractStateResponse{}
+	if err := abciquery.Query(ctx, cwClient.RPCClient, "/cosmwasm.wasm.v1.Query/SmartContractState", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
 }
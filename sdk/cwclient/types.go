@@ -5,3 +5,11 @@ type L2Block struct {
 	BlockHeight    uint64 `mapstructure:"block-height"`
 	BlockTimestamp uint64 `mapstructure:"block-timestamp"`
 }
+
+// Evidence is the proof that a finality provider voted two different block hashes at the same L2 block height
+type Evidence struct {
+	FpBtcPkHex       string `json:"fp_btc_pk_hex"`
+	BlockHeight      uint64 `json:"block_height"`
+	CanonicalAppHash []byte `json:"canonical_app_hash"`
+	ForkAppHash      []byte `json:"fork_app_hash"`
+}
@@ -0,0 +1,32 @@
+// Package finality holds the finality verdicts returned by the SDK client
+package finality
+
+import "math/big"
+
+// FpVote is the voting power and vote status of a finality provider for an L2 block
+type FpVote struct {
+	FpBtcPkHex string `json:"fp_btc_pk_hex"`
+	Power      uint64 `json:"power"`
+	Voted      bool   `json:"voted"`
+}
+
+// Result is the detailed finality verdict of an L2 block
+type Result struct {
+	// Enabled is false if the finality gadget is disabled, in which case every block is finalized
+	Enabled   bool `json:"enabled"`
+	Finalized bool `json:"finalized"`
+	// BtcHeight is the BTC height the L2 block timestamp is mapped to
+	BtcHeight uint64 `json:"btc_height"`
+	// the sum of voting power can exceed uint64 for large stake totals
+	TotalPower *big.Int `json:"total_power"`
+	VotedPower *big.Int `json:"voted_power"`
+	// FpVotes lists all FPs of the consumer chain in the order returned by Babylon
+	FpVotes []FpVote `json:"fp_votes"`
+	// ConflictingFps lists the FPs that have equivocation evidence in the contract at the L2 block height, i.e. whose
+	// vote for a conflicting hash was proven and recorded. A conflicting vote without evidence is not listed
+	ConflictingFps []string `json:"conflicting_fps"`
+	// the block is finalized if VotedPower / TotalPower >= QuorumNumerator / QuorumDenominator, or if
+	// VotedPower / TotalPower > 1/2 for a threshold of 1/2
+	QuorumNumerator   uint64 `json:"quorum_numerator"`
+	QuorumDenominator uint64 `json:"quorum_denominator"`
+}
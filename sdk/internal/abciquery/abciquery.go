@@ -0,0 +1,80 @@
+// Package abciquery sends the gRPC queries of the Babylon and CosmWasm clients as ABCI queries over the CometBFT RPC
+// client, with the caller's context
+package abciquery
+
+import (
+	"context"
+	"time"
+
+	abcitypes "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/libs/bytes"
+	rpcclient "github.com/cometbft/cometbft/rpc/client"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultTimeout bounds a query when the caller's context has no deadline.
+// It matches the default timeout of the Babylon query client
+const DefaultTimeout = 20 * time.Second
+
+// Client is the part of the CometBFT RPC client the queries are sent over
+type Client interface {
+	ABCIQueryWithOptions(
+		ctx context.Context,
+		path string,
+		data bytes.HexBytes,
+		opts rpcclient.ABCIQueryOptions,
+	) (*ctypes.ResultABCIQuery, error)
+}
+
+// Message is a gogoproto message, as generated for the Babylon and wasm queries
+type Message interface {
+	Marshal() ([]byte, error)
+	Unmarshal(data []byte) error
+}
+
+// Query sends the gRPC query at the path as an ABCI query, and decodes the response into resp
+//
+// the query goes straight to ABCIQueryWithOptions, as the gRPC client of cosmos-sdk does not pass the context
+// on to the RPC client, so that the deadline and the cancellation of the caller would be lost
+func Query(ctx context.Context, client Client, path string, req Message, resp Message) error {
+	ctx, cancel := withDefaultTimeout(ctx)
+	defer cancel()
+
+	data, err := req.Marshal()
+	if err != nil {
+		return err
+	}
+	result, err := client.ABCIQueryWithOptions(ctx, path, data, rpcclient.DefaultABCIQueryOptions)
+	if err != nil {
+		return err
+	}
+	if !result.Response.IsOK() {
+		return queryError(result.Response)
+	}
+	return resp.Unmarshal(result.Response.Value)
+}
+
+// queryError maps a failed ABCI query to a gRPC status error, as the gRPC client of cosmos-sdk does
+func queryError(resp abcitypes.ResponseQuery) error {
+	switch resp.Code {
+	case sdkerrors.ErrInvalidRequest.ABCICode():
+		return status.Error(codes.InvalidArgument, resp.Log)
+	case sdkerrors.ErrUnauthorized.ABCICode():
+		return status.Error(codes.Unauthenticated, resp.Log)
+	case sdkerrors.ErrKeyNotFound.ABCICode():
+		return status.Error(codes.NotFound, resp.Log)
+	default:
+		return status.Error(codes.Unknown, resp.Log)
+	}
+}
+
+// withDefaultTimeout applies DefaultTimeout to the context unless the caller already set a deadline
+func withDefaultTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, DefaultTimeout)
+}
@@ -0,0 +1,76 @@
+package abciquery
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	abcitypes "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/libs/bytes"
+	rpcclient "github.com/cometbft/cometbft/rpc/client"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeClient answers every query with the response, recording the path and the deadline of the query
+type fakeClient struct {
+	response abcitypes.ResponseQuery
+	path     string
+	deadline time.Time
+}
+
+func (c *fakeClient) ABCIQueryWithOptions(
+	ctx context.Context,
+	path string,
+	_ bytes.HexBytes,
+	_ rpcclient.ABCIQueryOptions,
+) (*ctypes.ResultABCIQuery, error) {
+	c.path = path
+	c.deadline, _ = ctx.Deadline()
+	return &ctypes.ResultABCIQuery{Response: c.response}, nil
+}
+
+func TestQuery(t *testing.T) {
+	value, err := (&wasmtypes.QuerySmartContractStateResponse{Data: []byte(`{}`)}).Marshal()
+	require.NoError(t, err)
+	client := &fakeClient{response: abcitypes.ResponseQuery{Value: value}}
+
+	resp := &wasmtypes.QuerySmartContractStateResponse{}
+	start := time.Now()
+	err = Query(context.Background(), client, "/path", &wasmtypes.QuerySmartContractStateRequest{}, resp)
+	require.NoError(t, err)
+	require.Equal(t, "/path", client.path)
+	require.Equal(t, wasmtypes.RawContractMessage(`{}`), resp.Data)
+	// DefaultTimeout applies if the caller set no deadline
+	require.WithinDuration(t, start.Add(DefaultTimeout), client.deadline, time.Second)
+
+	// the deadline of the caller is kept
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	callerDeadline, _ := ctx.Deadline()
+	require.NoError(t, Query(ctx, client, "/path", &wasmtypes.QuerySmartContractStateRequest{}, resp))
+	require.Equal(t, callerDeadline, client.deadline)
+}
+
+func TestQueryError(t *testing.T) {
+	testCases := []struct {
+		code     uint32
+		expected codes.Code
+	}{
+		{sdkerrors.ErrInvalidRequest.ABCICode(), codes.InvalidArgument},
+		{sdkerrors.ErrUnauthorized.ABCICode(), codes.Unauthenticated},
+		{sdkerrors.ErrKeyNotFound.ABCICode(), codes.NotFound},
+		{sdkerrors.ErrInsufficientFunds.ABCICode(), codes.Unknown},
+	}
+	for _, tc := range testCases {
+		client := &fakeClient{response: abcitypes.ResponseQuery{Code: tc.code, Log: "failed"}}
+		err := Query(context.Background(), client, "/path",
+			&wasmtypes.QuerySmartContractStateRequest{}, &wasmtypes.QuerySmartContractStateResponse{})
+		require.Equal(t, tc.expected, status.Code(err))
+		require.ErrorContains(t, err, "failed")
+	}
+}
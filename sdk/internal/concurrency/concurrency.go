@@ -0,0 +1,43 @@
+// Package concurrency runs the per-FP queries of the SDK concurrently, with a bounded number of queries in flight
+package concurrency
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// ForEach calls fn for each item with at most limit calls in flight, a limit below 1 calling fn for one item at a
+// time
+//
+// fn receives the index of the item, so that results can be stored in a slice without locking and assembled in a
+// deterministic order. The context passed to fn is cancelled as soon as one call fails, and the first error is
+// returned
+func ForEach[T any](ctx context.Context, limit int, items []T, fn func(ctx context.Context, i int, item T) error) error {
+	if limit < 1 {
+		limit = 1
+	}
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(limit)
+
+	for i, item := range items {
+		i, item := i, item
+		// stop scheduling new calls once a call has failed
+		if gctx.Err() != nil {
+			break
+		}
+		g.Go(func() error {
+			// a call may have failed while waiting for a free slot
+			if err := gctx.Err(); err != nil {
+				return err
+			}
+			return fn(gctx, i, item)
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	// the parent context may be cancelled after all scheduled calls succeeded
+	return ctx.Err()
+}
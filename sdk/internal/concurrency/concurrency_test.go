@@ -0,0 +1,54 @@
+package concurrency
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestForEach(t *testing.T) {
+	items := make([]int, 20)
+	results := make([]int, len(items))
+	var inFlight, maxInFlight atomic.Int64
+	err := ForEach(context.Background(), 3, items, func(_ context.Context, i int, _ int) error {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			max := maxInFlight.Load()
+			if n <= max || maxInFlight.CompareAndSwap(max, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		results[i] = i
+		return nil
+	})
+	require.NoError(t, err)
+	require.LessOrEqual(t, maxInFlight.Load(), int64(3))
+	for i, result := range results {
+		require.Equal(t, i, result)
+	}
+}
+
+func TestForEachFailsFast(t *testing.T) {
+	var calls atomic.Int64
+	// a limit below 1 calls fn for one item at a time, so the items after the failed one are not called
+	err := ForEach(context.Background(), 0, []string{"a", "b", "c"}, func(_ context.Context, _ int, item string) error {
+		calls.Add(1)
+		if item == "a" {
+			return fmt.Errorf("query of %s failed", item)
+		}
+		return nil
+	})
+	require.ErrorContains(t, err, "query of a failed")
+	require.Equal(t, int64(1), calls.Load())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, ForEach(ctx, 1, []string{"a"}, func(context.Context, int, string) error { return nil }),
+		context.Canceled)
+}
@@ -0,0 +1,80 @@
+// Package failover tracks the health of the endpoints of the clients that fail over between endpoints, i.e. the
+// Babylon RPC pool and the BTC multi-source client, so that a single flaky endpoint does not stall the queries
+package failover
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// MaxBackoff caps the time a failing endpoint is skipped for
+	MaxBackoff = 5 * time.Minute
+	// latencyWeight is the weight of the latest call in the moving average of the latency of an endpoint
+	latencyWeight = 0.3
+)
+
+// Health is the health of an endpoint, updated with the outcome of each call to it. It is not safe for concurrent
+// use, the client guards it with its own lock
+type Health struct {
+	// Latency is the moving average of the duration of the successful calls, MeasuredAt is the time of the last
+	// successful call. Both are zero until the endpoint is measured
+	Latency    time.Duration
+	MeasuredAt time.Time
+	// Failures is the number of consecutive failed calls, the endpoint is skipped until UnhealthyUntil
+	Failures       int
+	UnhealthyUntil time.Time
+}
+
+// Succeeded records a successful call of the given duration, and returns true if the endpoint was failing
+func (h *Health) Succeeded(now time.Time, latency time.Duration) bool {
+	recovered := h.Failures > 0
+	h.Failures = 0
+	h.UnhealthyUntil = time.Time{}
+	if h.MeasuredAt.IsZero() {
+		h.Latency = latency
+	} else {
+		h.Latency = time.Duration(latencyWeight*float64(latency) + (1-latencyWeight)*float64(h.Latency))
+	}
+	h.MeasuredAt = now
+	return recovered
+}
+
+// Failed records a failed call, skipping the endpoint for the backoff doubled on each consecutive failure, up to
+// MaxBackoff. It returns the applied backoff
+func (h *Health) Failed(now time.Time, backoff time.Duration) time.Duration {
+	h.Failures++
+	for i := 1; i < h.Failures && backoff < MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > MaxBackoff {
+		backoff = MaxBackoff
+	}
+	h.UnhealthyUntil = now.Add(backoff)
+	return backoff
+}
+
+// Call runs the query within the endpoint timeout, unless it is 0, and reports the outcome and the duration of the
+// query. Nothing is reported if the caller gave up, as the endpoint is not to blame
+func Call(
+	ctx context.Context,
+	timeout time.Duration,
+	now func() time.Time,
+	query func(ctx context.Context) error,
+	report func(latency time.Duration, err error),
+) error {
+	callCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	start := now()
+	err := query(callCtx)
+	if ctx.Err() != nil {
+		return err
+	}
+	report(now().Sub(start), err)
+	return err
+}
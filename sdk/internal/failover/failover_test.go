@@ -0,0 +1,90 @@
+package failover
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthBackoff(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	var h Health
+
+	// the backoff doubles on each consecutive failure, up to MaxBackoff
+	expectedBackoff := time.Second
+	for i := 1; i <= 12; i++ {
+		require.Equal(t, expectedBackoff, h.Failed(now, time.Second))
+		require.Equal(t, i, h.Failures)
+		require.Equal(t, now.Add(expectedBackoff), h.UnhealthyUntil)
+		expectedBackoff *= 2
+		if expectedBackoff > MaxBackoff {
+			expectedBackoff = MaxBackoff
+		}
+	}
+
+	// a success resets the backoff
+	require.True(t, h.Succeeded(now, time.Millisecond))
+	require.Zero(t, h.Failures)
+	require.True(t, h.UnhealthyUntil.IsZero())
+	require.Equal(t, time.Second, h.Failed(now, time.Second))
+}
+
+func TestHealthLatency(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	var h Health
+
+	// the first call sets the latency, the next ones are averaged
+	require.False(t, h.Succeeded(now, 100*time.Millisecond))
+	require.Equal(t, 100*time.Millisecond, h.Latency)
+	require.Equal(t, now, h.MeasuredAt)
+	require.False(t, h.Succeeded(now.Add(time.Second), 200*time.Millisecond))
+	require.Equal(t, 130*time.Millisecond, h.Latency)
+	require.Equal(t, now.Add(time.Second), h.MeasuredAt)
+
+	// a failure keeps the measured latency
+	h.Failed(now, time.Second)
+	require.Equal(t, 130*time.Millisecond, h.Latency)
+}
+
+func TestCall(t *testing.T) {
+	type outcome struct {
+		latency time.Duration
+		err     error
+	}
+	var reported []outcome
+	report := func(latency time.Duration, err error) {
+		reported = append(reported, outcome{latency: latency, err: err})
+	}
+	clock := time.Unix(1_700_000_000, 0)
+	now := func() time.Time { return clock }
+
+	// the outcome and the duration of the query are reported
+	queryErr := errors.New("connection refused")
+	err := Call(context.Background(), time.Second, now, func(ctx context.Context) error {
+		clock = clock.Add(10 * time.Millisecond)
+		return queryErr
+	}, report)
+	require.ErrorIs(t, err, queryErr)
+	require.Equal(t, []outcome{{10 * time.Millisecond, queryErr}}, reported)
+
+	// a query running past the timeout is cancelled, and reported as failed
+	err = Call(context.Background(), 10*time.Millisecond, now, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, report)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Len(t, reported, 2)
+	require.ErrorIs(t, reported[1].err, context.DeadlineExceeded)
+
+	// nothing is reported if the caller gave up
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = Call(ctx, time.Second, now, func(ctx context.Context) error {
+		return ctx.Err()
+	}, report)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Len(t, reported, 2)
+}
@@ -0,0 +1,218 @@
+package l2client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/avast/retry-go/v4"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/babylon-finality-gadget/sdk/cwclient"
+)
+
+const (
+	// maxResponseSize bounds the body of a JSON-RPC response, which only holds a block without its transactions
+	maxResponseSize = 1 << 20
+
+	// the block tags of eth_getBlockByNumber
+	latestBlockTag = "latest"
+	safeBlockTag   = "safe"
+)
+
+// Block is an L2 block with the hash of its parent, which links it to the L2 chain
+type Block struct {
+	cwclient.L2Block
+	ParentHash string
+}
+
+// Client reads the L2 blocks from the JSON-RPC of an L2 execution client, e.g. op-geth, with eth_getBlockByNumber,
+// retrying the calls that fail in transit
+type Client struct {
+	rpcAddr    string
+	httpClient *http.Client
+	cfg        *Config
+	logger     *zap.Logger
+
+	requestID atomic.Uint64
+}
+
+// NewClient creates a client of the L2 execution client at Config.RPCAddr
+func NewClient(cfg *Config, logger *zap.Logger) (*Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &Client{
+		rpcAddr:    cfg.RPCAddr,
+		httpClient: &http.Client{},
+		cfg:        cfg,
+		logger:     logger,
+	}, nil
+}
+
+// HeadBlock returns the unsafe L2 head, i.e. the latest L2 block
+func (c *Client) HeadBlock(ctx context.Context) (*Block, error) {
+	return c.blockByNumber(ctx, latestBlockTag)
+}
+
+// SafeBlock returns the safe L2 head, i.e. the latest L2 block derived from the data posted to the L1. The execution
+// clients that do not know the safe head return an RPCError
+func (c *Client) SafeBlock(ctx context.Context) (*Block, error) {
+	return c.blockByNumber(ctx, safeBlockTag)
+}
+
+// BlockByHeight returns the L2 block at the height, or ErrBlockNotFound if it is beyond the L2 head
+func (c *Client) BlockByHeight(ctx context.Context, height uint64) (*Block, error) {
+	return c.blockByNumber(ctx, "0x"+strconv.FormatUint(height, 16))
+}
+
+// rpcBlock is the part of a block returned by eth_getBlockByNumber used by the client, with the numbers hex-encoded
+type rpcBlock struct {
+	Number     string `json:"number"`
+	Hash       string `json:"hash"`
+	ParentHash string `json:"parentHash"`
+	Timestamp  string `json:"timestamp"`
+}
+
+func (c *Client) blockByNumber(ctx context.Context, number string) (*Block, error) {
+	var result *rpcBlock
+	// the transactions are not needed, only their hashes are returned
+	if err := c.call(ctx, "eth_getBlockByNumber", []interface{}{number, false}, &result); err != nil {
+		return nil, fmt.Errorf("failed to get L2 block %s: %w", number, err)
+	}
+	if result == nil {
+		return nil, fmt.Errorf("failed to get L2 block %s: %w", number, ErrBlockNotFound)
+	}
+
+	height, err := decodeUint64(result.Number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get L2 block %s: invalid number: %w", number, err)
+	}
+	timestamp, err := decodeUint64(result.Timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get L2 block %s: invalid timestamp: %w", number, err)
+	}
+	if result.Hash == "" || (height > 0 && result.ParentHash == "") {
+		return nil, fmt.Errorf("failed to get L2 block %s: the block hashes are not set", number)
+	}
+	return &Block{
+		L2Block: cwclient.L2Block{
+			BlockHeight:    height,
+			BlockHash:      result.Hash,
+			BlockTimestamp: timestamp,
+		},
+		ParentHash: result.ParentHash,
+	}, nil
+}
+
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type rpcResponse struct {
+	ID     uint64          `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error"`
+}
+
+// call calls the JSON-RPC method until it succeeds, the execution client returns an error, or the attempts are
+// exhausted. Each attempt is bounded by Config.RequestTimeout
+func (c *Client) call(ctx context.Context, method string, params []interface{}, result interface{}) error {
+	return retry.Do(
+		func() error {
+			attemptCtx, cancel := c.attemptContext(ctx)
+			defer cancel()
+			return c.do(attemptCtx, method, params, result)
+		},
+		retry.Context(ctx),
+		retry.Attempts(c.cfg.MaxRetryTimes),
+		retry.Delay(c.cfg.RetryInterval),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(func(err error) bool {
+			// the caller gave up
+			return retry.IsRecoverable(err) && ctx.Err() == nil
+		}),
+		retry.OnRetry(func(n uint, err error) {
+			c.logger.Debug(
+				"failed to call the L2 execution client",
+				zap.String("method", method),
+				zap.Uint("attempt", n+1),
+				zap.Uint("max_attempts", c.cfg.MaxRetryTimes),
+				zap.Error(err),
+			)
+		}),
+	)
+}
+
+func (c *Client) attemptContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.cfg.RequestTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.cfg.RequestTimeout)
+}
+
+// do sends one JSON-RPC request, and decodes the result of the response into result
+func (c *Client) do(ctx context.Context, method string, params []interface{}, result interface{}) error {
+	id := c.requestID.Add(1)
+	reqBody, err := json.Marshal(&rpcRequest{JSONRPC: "2.0", ID: id, Method: method, Params: params})
+	if err != nil {
+		return retry.Unrecoverable(err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.rpcAddr, bytes.NewReader(reqBody))
+	if err != nil {
+		return retry.Unrecoverable(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("the L2 execution client returned status %d: %s", resp.StatusCode,
+			strings.TrimSpace(string(body)))
+		// the request itself is rejected, e.g. the credentials are missing, so retrying does not help
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return retry.Unrecoverable(err)
+		}
+		return err
+	}
+
+	var rpcResp rpcResponse
+	if err := json.Unmarshal(body, &rpcResp); err != nil {
+		return fmt.Errorf("invalid JSON-RPC response: %w", err)
+	}
+	if rpcResp.Error != nil {
+		return retry.Unrecoverable(rpcResp.Error)
+	}
+	if rpcResp.ID != id {
+		return fmt.Errorf("the JSON-RPC response has id %d instead of %d", rpcResp.ID, id)
+	}
+	if err := json.Unmarshal(rpcResp.Result, result); err != nil {
+		return retry.Unrecoverable(fmt.Errorf("invalid JSON-RPC result: %w", err))
+	}
+	return nil
+}
+
+// decodeUint64 decodes a hex-encoded quantity of the JSON-RPC, e.g. 0x1b4
+func decodeUint64(s string) (uint64, error) {
+	digits, ok := strings.CutPrefix(s, "0x")
+	if !ok || digits == "" {
+		return 0, fmt.Errorf("%q is not a hex quantity", s)
+	}
+	return strconv.ParseUint(digits, 16, 64)
+}
@@ -0,0 +1,226 @@
+package l2client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/babylon-finality-gadget/sdk/cwclient"
+)
+
+// fakeL2Node serves eth_getBlockByNumber over the blocks of its canonical chain
+//
+// the first failures requests fail with a 503 status, and the safe head is unknown if safeHeight is negative
+type fakeL2Node struct {
+	mu         sync.Mutex
+	chain      []*Block
+	safeHeight int64
+
+	failures atomic.Int64
+	requests atomic.Int64
+}
+
+// newTestBlock returns the L2 block at the height of the chain of the fork, whose blocks have their own hashes
+func newTestBlock(height uint64, fork uint64, parentHash string) *Block {
+	return &Block{
+		L2Block: cwclient.L2Block{
+			BlockHeight:    height,
+			BlockHash:      fmt.Sprintf("0x%064x", fork<<32|height),
+			BlockTimestamp: 1_700_000_000 + 2*height,
+		},
+		ParentHash: parentHash,
+	}
+}
+
+// newFakeL2Node creates a node whose chain goes from the genesis up to the head
+func newFakeL2Node(head uint64) *fakeL2Node {
+	node := &fakeL2Node{chain: []*Block{newTestBlock(0, 0, "")}, safeHeight: -1}
+	node.extend(head, 0)
+	return node
+}
+
+// extend extends the chain of the node up to the head with the blocks of the fork
+func (n *fakeL2Node) extend(head uint64, fork uint64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for height := uint64(len(n.chain)); height <= head; height++ {
+		n.chain = append(n.chain, newTestBlock(height, fork, n.chain[height-1].BlockHash))
+	}
+}
+
+// reorg replaces the blocks of the chain from the height on with the blocks of the fork up to the head
+func (n *fakeL2Node) reorg(height uint64, head uint64, fork uint64) {
+	n.mu.Lock()
+	n.chain = n.chain[:height]
+	n.mu.Unlock()
+	n.extend(head, fork)
+}
+
+func (n *fakeL2Node) setSafeHeight(height int64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.safeHeight = height
+}
+
+func (n *fakeL2Node) block(height uint64) *Block {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.chain[height]
+}
+
+func (n *fakeL2Node) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n.requests.Add(1)
+	if n.failures.Add(-1) >= 0 {
+		http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	var req struct {
+		ID     uint64            `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var number string
+	if req.Method != "eth_getBlockByNumber" || len(req.Params) != 2 || json.Unmarshal(req.Params[0], &number) != nil {
+		writeRPCResponse(w, req.ID, nil, &RPCError{Code: -32601, Message: "the method does not exist"})
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	var height uint64
+	switch number {
+	case latestBlockTag:
+		height = uint64(len(n.chain) - 1)
+	case safeBlockTag:
+		if n.safeHeight < 0 {
+			writeRPCResponse(w, req.ID, nil, &RPCError{Code: -32000, Message: "unknown block"})
+			return
+		}
+		height = uint64(n.safeHeight)
+	default:
+		var err error
+		if height, err = strconv.ParseUint(strings.TrimPrefix(number, "0x"), 16, 64); err != nil {
+			writeRPCResponse(w, req.ID, nil, &RPCError{Code: -32602, Message: "invalid block number"})
+			return
+		}
+	}
+	if height >= uint64(len(n.chain)) {
+		writeRPCResponse(w, req.ID, nil, nil)
+		return
+	}
+	block := n.chain[height]
+	writeRPCResponse(w, req.ID, &rpcBlock{
+		Number:     "0x" + strconv.FormatUint(block.BlockHeight, 16),
+		Hash:       block.BlockHash,
+		ParentHash: block.ParentHash,
+		Timestamp:  "0x" + strconv.FormatUint(block.BlockTimestamp, 16),
+	}, nil)
+}
+
+func writeRPCResponse(w http.ResponseWriter, id uint64, result *rpcBlock, rpcErr *RPCError) {
+	resp := map[string]interface{}{"jsonrpc": "2.0", "id": id}
+	if rpcErr != nil {
+		resp["error"] = rpcErr
+	} else {
+		resp["result"] = result
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func testConfig(rpcAddr string) *Config {
+	return &Config{
+		RPCAddr:        rpcAddr,
+		PollInterval:   10 * time.Millisecond,
+		WindowSize:     16,
+		RequestTimeout: time.Second,
+		MaxRetryTimes:  3,
+		RetryInterval:  10 * time.Millisecond,
+	}
+}
+
+// newTestClient serves the node over HTTP, and returns a client of it
+func newTestClient(t *testing.T, node *fakeL2Node) *Client {
+	httpServer := httptest.NewServer(node)
+	t.Cleanup(httpServer.Close)
+	l2Client, err := NewClient(testConfig(httpServer.URL), zap.NewNop())
+	require.NoError(t, err)
+	return l2Client
+}
+
+func TestNewClient(t *testing.T) {
+	_, err := NewClient(testConfig("localhost:8545"), zap.NewNop())
+	require.ErrorContains(t, err, "invalid L2 RPC address")
+
+	cfg := testConfig("http://localhost:8545")
+	cfg.WindowSize = 0
+	_, err = NewClient(cfg, zap.NewNop())
+	require.ErrorContains(t, err, "the L2 window size must be positive")
+}
+
+func TestClientBlocks(t *testing.T) {
+	node := newFakeL2Node(100)
+	l2Client := newTestClient(t, node)
+
+	head, err := l2Client.HeadBlock(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, node.block(100), head)
+
+	block, err := l2Client.BlockByHeight(context.Background(), 42)
+	require.NoError(t, err)
+	require.Equal(t, node.block(42), block)
+	require.Equal(t, node.block(41).BlockHash, block.ParentHash)
+
+	_, err = l2Client.BlockByHeight(context.Background(), 101)
+	require.ErrorIs(t, err, ErrBlockNotFound)
+
+	// the safe head is unknown
+	_, err = l2Client.SafeBlock(context.Background())
+	var rpcErr *RPCError
+	require.ErrorAs(t, err, &rpcErr)
+	require.Equal(t, -32000, rpcErr.Code)
+
+	node.setSafeHeight(90)
+	safe, err := l2Client.SafeBlock(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, node.block(90), safe)
+}
+
+func TestClientRetry(t *testing.T) {
+	node := newFakeL2Node(10)
+	l2Client := newTestClient(t, node)
+
+	// the failures in transit are retried
+	node.failures.Store(2)
+	head, err := l2Client.HeadBlock(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, node.block(10), head)
+	require.Equal(t, int64(3), node.requests.Load())
+
+	// until the attempts are exhausted
+	node.failures.Store(3)
+	_, err = l2Client.HeadBlock(context.Background())
+	require.ErrorContains(t, err, "the L2 execution client returned status 503")
+
+	// the errors of the execution client are not retried
+	node.requests.Store(0)
+	_, err = l2Client.SafeBlock(context.Background())
+	require.ErrorContains(t, err, "unknown block")
+	require.Equal(t, int64(1), node.requests.Load())
+}
@@ -0,0 +1,67 @@
+package l2client
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	defaultPollInterval   = 2 * time.Second
+	defaultWindowSize     = 256
+	defaultRequestTimeout = 10 * time.Second
+	defaultMaxRetryTimes  = 3
+	defaultRetryInterval  = 500 * time.Millisecond
+)
+
+// Config defines configuration for the client of the L2 execution client
+type Config struct {
+	// RPCAddr is the JSON-RPC address of the L2 execution client, e.g. http://localhost:8545
+	RPCAddr string `mapstructure:"rpc-addr"`
+	// PollInterval is the interval the Follower polls the L2 head at
+	PollInterval time.Duration `mapstructure:"poll-interval"`
+	// WindowSize is the number of the latest L2 blocks the Follower keeps to detect the L2 reorgs. The window starts
+	// over if the L2 head gets more than WindowSize blocks ahead, or a reorg is deeper than the window
+	WindowSize uint64 `mapstructure:"window-size"`
+	// RequestTimeout bounds each attempt of a JSON-RPC call. Set to 0 to only honor the context of the caller
+	RequestTimeout time.Duration `mapstructure:"request-timeout"`
+	// MaxRetryTimes is the max number of attempts of a JSON-RPC call that fails in transit. The errors returned by
+	// the execution client are not retried
+	MaxRetryTimes uint `mapstructure:"max-retry-times"`
+	// RetryInterval is the initial time between the attempts of a JSON-RPC call, backed off on each retry
+	RetryInterval time.Duration `mapstructure:"retry-interval"`
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		PollInterval:   defaultPollInterval,
+		WindowSize:     defaultWindowSize,
+		RequestTimeout: defaultRequestTimeout,
+		MaxRetryTimes:  defaultMaxRetryTimes,
+		RetryInterval:  defaultRetryInterval,
+	}
+}
+
+func (cfg *Config) Validate() error {
+	u, err := url.Parse(cfg.RPCAddr)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid L2 RPC address %q", cfg.RPCAddr)
+	}
+	if cfg.PollInterval <= 0 {
+		return errors.New("the L2 poll interval must be positive")
+	}
+	if cfg.WindowSize == 0 {
+		return errors.New("the L2 window size must be positive")
+	}
+	if cfg.RequestTimeout < 0 {
+		return errors.New("the request timeout must not be negative")
+	}
+	if cfg.MaxRetryTimes == 0 {
+		return errors.New("the max retry times must be positive")
+	}
+	if cfg.RetryInterval < 0 {
+		return errors.New("the retry interval must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package l2client
+
+import "fmt"
+
+var (
+	// ErrBlockNotFound means that the L2 execution client does not know the block, e.g. it is beyond the L2 head
+	ErrBlockNotFound = fmt.Errorf("the L2 block is not found")
+)
+
+// RPCError is an error returned by the L2 execution client to a JSON-RPC call
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("JSON-RPC error %d: %s", e.Code, e.Message)
+}
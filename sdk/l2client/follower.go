@@ -0,0 +1,268 @@
+package l2client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/babylon-finality-gadget/sdk/client"
+	"github.com/babylonchain/babylon-finality-gadget/sdk/cwclient"
+)
+
+// Follower follows the L2 chain of an L2 execution client, and implements client.IL2Client over the unsafe L2 head, so
+// that it drives the finality Tracker of the SDK client
+//
+//   - it keeps the latest Config.WindowSize blocks of the canonical chain, each linked to the previous one by its
+//     parent hash, so that the Tracker checks the finality of blocks of one chain
+//   - an L2 reorg is detected by a new block whose parent hash does not match the block below it in the window. The
+//     blocks of the new chain are fetched down to the common ancestor and replace the orphaned ones
+//   - the safe L2 head is tracked too, and a reorg of safe blocks is reported as an error, as it means that the L1
+//     reorged or the execution client is faulty
+type Follower struct {
+	l2Client *Client
+	cfg      *Config
+	logger   *zap.Logger
+
+	// syncMu serializes the syncs of the window
+	syncMu sync.Mutex
+	// running is set while Run polls the L2 head
+	running atomic.Bool
+
+	mu sync.RWMutex
+	// window holds the latest blocks of the canonical chain, from low to high, the last one being the unsafe head
+	window   []*Block
+	safeHead *Block
+}
+
+var _ client.IL2Client = (*Follower)(nil)
+
+// NewFollower creates a follower of the L2 chain of l2Client. It follows the L2 head once Run is called, or on demand
+// on each HeadBlock call otherwise
+func NewFollower(l2Client *Client, cfg *Config, logger *zap.Logger) *Follower {
+	return &Follower{
+		l2Client: l2Client,
+		cfg:      cfg,
+		logger:   logger,
+	}
+}
+
+// Run polls the L2 head every Config.PollInterval until the context is done
+func (f *Follower) Run(ctx context.Context) {
+	f.running.Store(true)
+	defer f.running.Store(false)
+	ticker := time.NewTicker(f.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		if err := f.sync(ctx); err != nil && ctx.Err() == nil {
+			f.logger.Warn("Failed to follow the L2 chain", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// HeadBlock returns the unsafe L2 head known to the follower. While Run is not running, the follower syncs first on
+// each call, and otherwise only if it has no head yet
+func (f *Follower) HeadBlock(ctx context.Context) (*cwclient.L2Block, error) {
+	if head := f.UnsafeHead(); head != nil && f.running.Load() {
+		return head, nil
+	}
+	if err := f.sync(ctx); err != nil {
+		return nil, err
+	}
+	return f.UnsafeHead(), nil
+}
+
+// BlockByHeight returns the L2 block of the canonical chain at the height. The blocks below the window are fetched
+// from the execution client, and the blocks beyond the unsafe head are not found
+func (f *Follower) BlockByHeight(ctx context.Context, height uint64) (*cwclient.L2Block, error) {
+	f.mu.RLock()
+	if len(f.window) > 0 {
+		first, last := f.window[0], f.window[len(f.window)-1]
+		if height > last.BlockHeight {
+			f.mu.RUnlock()
+			return nil, fmt.Errorf("L2 block %d is beyond the L2 head %d: %w", height, last.BlockHeight, ErrBlockNotFound)
+		}
+		if height >= first.BlockHeight {
+			block := f.window[height-first.BlockHeight].L2Block
+			f.mu.RUnlock()
+			return &block, nil
+		}
+	}
+	f.mu.RUnlock()
+
+	block, err := f.l2Client.BlockByHeight(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	return &block.L2Block, nil
+}
+
+// UnsafeHead returns the unsafe L2 head, i.e. the latest L2 block, or nil if the follower has not synced yet
+func (f *Follower) UnsafeHead() *cwclient.L2Block {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if len(f.window) == 0 {
+		return nil
+	}
+	block := f.window[len(f.window)-1].L2Block
+	return &block
+}
+
+// SafeHead returns the safe L2 head, or nil if it is unknown, e.g. the execution client does not track it
+func (f *Follower) SafeHead() *cwclient.L2Block {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.safeHead == nil {
+		return nil
+	}
+	block := f.safeHead.L2Block
+	return &block
+}
+
+// sync fetches the unsafe and safe L2 heads, and links the unsafe head to the window
+func (f *Follower) sync(ctx context.Context) error {
+	f.syncMu.Lock()
+	defer f.syncMu.Unlock()
+
+	head, err := f.l2Client.HeadBlock(ctx)
+	if err != nil {
+		return err
+	}
+	branch, startOver, err := f.fetchBranch(ctx, head)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	orphaned := f.apply(branch, startOver)
+	safeHead := f.safeHead
+	f.mu.Unlock()
+	if len(orphaned) > 0 {
+		f.reportReorg(orphaned, head, safeHead)
+	}
+
+	safeHead, err = f.l2Client.SafeBlock(ctx)
+	if err != nil {
+		// the safe head is informative only, e.g. it is unknown to the execution clients of the chains without L1
+		f.logger.Debug("Failed to get the safe L2 head", zap.Error(err))
+		return nil
+	}
+	f.mu.Lock()
+	f.safeHead = safeHead
+	f.mu.Unlock()
+	return nil
+}
+
+// fetchBranch fetches the blocks from the head down to the first block linked to the window, and returns them from
+// low to high, and whether the window starts over with them, i.e. the window is empty, the head is too far ahead of
+// it, or the reorg is deeper than the window
+func (f *Follower) fetchBranch(ctx context.Context, head *Block) ([]*Block, bool, error) {
+	branch := []*Block{head}
+	for {
+		linked, startOver := f.linked(head, branch[0])
+		if startOver {
+			return []*Block{head}, true, nil
+		}
+		if linked {
+			return branch, false, nil
+		}
+		if uint64(len(branch)) >= f.cfg.WindowSize {
+			f.logger.Error("The L2 reorg is deeper than the window of the L2 blocks, starting over",
+				zap.Uint64("window_size", f.cfg.WindowSize), zap.Uint64("head_height", head.BlockHeight))
+			return branch, true, nil
+		}
+
+		child := branch[0]
+		parent, err := f.l2Client.BlockByHeight(ctx, child.BlockHeight-1)
+		if err != nil {
+			return nil, false, err
+		}
+		if parent.BlockHash != child.ParentHash {
+			return nil, false, fmt.Errorf(
+				"L2 block %d %s is not the parent %s of block %d, the L2 chain changed while it was fetched",
+				parent.BlockHeight, parent.BlockHash, child.ParentHash, child.BlockHeight)
+		}
+		branch = append([]*Block{parent}, branch...)
+	}
+}
+
+// linked returns whether the block is linked to the window, i.e. its parent is in the window or below it, and whether
+// the window starts over at the head
+func (f *Follower) linked(head *Block, block *Block) (bool, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if len(f.window) == 0 {
+		return false, true
+	}
+	first, last := f.window[0], f.window[len(f.window)-1]
+	if head.BlockHeight > last.BlockHeight+f.cfg.WindowSize {
+		return false, true
+	}
+	if block.BlockHeight <= first.BlockHeight {
+		return true, false
+	}
+	parentHeight := block.BlockHeight - 1
+	if parentHeight > last.BlockHeight {
+		return false, false
+	}
+	return f.window[parentHeight-first.BlockHeight].BlockHash == block.ParentHash, false
+}
+
+// apply replaces the blocks of the window from the first block of the branch on, or the whole window if it starts
+// over, and returns the replaced blocks at the heights of the branch or above that are not in the branch, i.e. the
+// blocks orphaned by a reorg
+func (f *Follower) apply(branch []*Block, startOver bool) []*Block {
+	start := branch[0].BlockHeight
+	kept, replaced := f.window, []*Block(nil)
+	if len(f.window) > 0 {
+		first := f.window[0]
+		switch {
+		case startOver || start <= first.BlockHeight:
+			kept, replaced = nil, f.window
+		case start-first.BlockHeight < uint64(len(f.window)):
+			kept, replaced = f.window[:start-first.BlockHeight], f.window[start-first.BlockHeight:]
+		}
+	}
+
+	var orphaned []*Block
+	for _, block := range replaced {
+		if block.BlockHeight < start {
+			continue
+		}
+		i := block.BlockHeight - start
+		if i >= uint64(len(branch)) || branch[i].BlockHash != block.BlockHash {
+			orphaned = append(orphaned, block)
+		}
+	}
+
+	window := make([]*Block, 0, len(kept)+len(branch))
+	window = append(append(window, kept...), branch...)
+	if uint64(len(window)) > f.cfg.WindowSize {
+		window = window[uint64(len(window))-f.cfg.WindowSize:]
+	}
+	f.window = window
+	return orphaned
+}
+
+func (f *Follower) reportReorg(orphaned []*Block, head *Block, safeHead *Block) {
+	fields := []zap.Field{
+		zap.Int("depth", len(orphaned)),
+		zap.Uint64("orphaned_from_height", orphaned[0].BlockHeight),
+		zap.String("orphaned_from_hash", orphaned[0].BlockHash),
+		zap.Uint64("head_height", head.BlockHeight),
+		zap.String("head_hash", head.BlockHash),
+	}
+	if safeHead != nil && orphaned[0].BlockHeight <= safeHead.BlockHeight {
+		f.logger.Error("The L2 reorg orphaned safe L2 blocks",
+			append(fields, zap.Uint64("safe_height", safeHead.BlockHeight))...)
+		return
+	}
+	f.logger.Warn("L2 reorg detected", fields...)
+}
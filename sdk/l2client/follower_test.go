@@ -0,0 +1,172 @@
+package l2client
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/babylon-finality-gadget/sdk/client"
+	"github.com/babylonchain/babylon-finality-gadget/sdk/cwclient"
+	"github.com/babylonchain/babylon-finality-gadget/testutil/mocks"
+)
+
+func newTestFollower(t *testing.T, node *fakeL2Node) *Follower {
+	l2Client := newTestClient(t, node)
+	return NewFollower(l2Client, l2Client.cfg, zap.NewNop())
+}
+
+// requireChain checks that the follower serves the blocks of the chain of the node from the height up to the head
+func requireChain(t *testing.T, follower *Follower, node *fakeL2Node, from uint64, head uint64) {
+	require.Equal(t, &node.block(head).L2Block, follower.UnsafeHead())
+	for height := from; height <= head; height++ {
+		block, err := follower.BlockByHeight(context.Background(), height)
+		require.NoError(t, err)
+		require.Equal(t, &node.block(height).L2Block, block)
+	}
+	_, err := follower.BlockByHeight(context.Background(), head+1)
+	require.ErrorIs(t, err, ErrBlockNotFound)
+}
+
+func TestFollowerFollowsHead(t *testing.T) {
+	node := newFakeL2Node(100)
+	follower := newTestFollower(t, node)
+	require.Nil(t, follower.UnsafeHead())
+	require.Nil(t, follower.SafeHead())
+
+	// the follower syncs on the first query
+	head, err := follower.HeadBlock(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, &node.block(100).L2Block, head)
+	// the execution client does not know the safe head
+	require.Nil(t, follower.SafeHead())
+
+	// the new blocks are linked to the window
+	node.extend(110, 0)
+	node.setSafeHeight(105)
+	require.NoError(t, follower.sync(context.Background()))
+	requireChain(t, follower, node, 95, 110)
+	require.Equal(t, &node.block(105).L2Block, follower.SafeHead())
+
+	// the blocks below the window are fetched from the execution client
+	node.requests.Store(0)
+	block, err := follower.BlockByHeight(context.Background(), 10)
+	require.NoError(t, err)
+	require.Equal(t, &node.block(10).L2Block, block)
+	require.Equal(t, int64(1), node.requests.Load())
+}
+
+func TestFollowerSyncsOnDemand(t *testing.T) {
+	node := newFakeL2Node(100)
+	follower := newTestFollower(t, node)
+
+	// without Run, each query of the head syncs the window
+	head, err := follower.HeadBlock(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, &node.block(100).L2Block, head)
+	node.extend(105, 0)
+	head, err = follower.HeadBlock(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, &node.block(105).L2Block, head)
+	requireChain(t, follower, node, 95, 105)
+}
+
+func TestFollowerReorg(t *testing.T) {
+	node := newFakeL2Node(100)
+	follower := newTestFollower(t, node)
+	require.NoError(t, follower.sync(context.Background()))
+	node.extend(105, 0)
+	require.NoError(t, follower.sync(context.Background()))
+
+	// the blocks from 103 on are replaced by the ones of a longer chain
+	node.reorg(103, 108, 1)
+	require.NoError(t, follower.sync(context.Background()))
+	requireChain(t, follower, node, 101, 108)
+
+	// the blocks from 107 on are replaced by the ones of a shorter chain
+	node.reorg(107, 107, 2)
+	require.NoError(t, follower.sync(context.Background()))
+	requireChain(t, follower, node, 101, 107)
+
+	// the reorg is deeper than the window, so the window starts over
+	node.reorg(50, 120, 3)
+	require.NoError(t, follower.sync(context.Background()))
+	requireChain(t, follower, node, 105, 120)
+}
+
+func TestFollowerStartsOver(t *testing.T) {
+	node := newFakeL2Node(100)
+	follower := newTestFollower(t, node)
+	require.NoError(t, follower.sync(context.Background()))
+
+	// the head is too far ahead of the window to fetch the blocks in between
+	node.extend(1000, 0)
+	node.requests.Store(0)
+	require.NoError(t, follower.sync(context.Background()))
+	// the head and the safe head
+	require.Equal(t, int64(2), node.requests.Load())
+	requireChain(t, follower, node, 990, 1000)
+}
+
+func TestFollowerDrivesTracker(t *testing.T) {
+	node := newFakeL2Node(100)
+	follower := newTestFollower(t, node)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go follower.Run(ctx)
+
+	// the L2 blocks are finalized up to finalizedHeight, if they are the blocks of the chain of the node
+	var finalizedHeight atomic.Uint64
+	ctl := gomock.NewController(t)
+	sdkClient := mocks.NewMockISdkClient(ctl)
+	sdkClient.EXPECT().QueryBlockRangeBabylonFinalized(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, queryBlocks []*cwclient.L2Block) (*uint64, error) {
+			var lastFinalizedHeight *uint64
+			for _, block := range queryBlocks {
+				if block.BlockHeight > finalizedHeight.Load() ||
+					block.BlockHash != node.block(block.BlockHeight).BlockHash {
+					break
+				}
+				height := block.BlockHeight
+				lastFinalizedHeight = &height
+			}
+			return lastFinalizedHeight, nil
+		}).AnyTimes()
+	bbnClient := mocks.NewMockIBabylonClient(ctl)
+	bbnClient.EXPECT().SubscribeNewBlocks(gomock.Any()).Return(nil, fmt.Errorf("websocket unavailable")).AnyTimes()
+
+	trackerCfg := &client.TrackerConfig{StartHeight: 90, PollInterval: 10 * time.Millisecond, MaxRangeSize: 100}
+	tracker := client.NewTracker(sdkClient, bbnClient, follower, trackerCfg, zap.NewNop())
+	go tracker.Run(ctx)
+	events := tracker.Subscribe(ctx)
+
+	finalizedHeight.Store(95)
+	requireEvent(t, events, node.block(95))
+
+	// the unfinalized blocks are reorged, and the tracker checks the blocks of the new chain
+	node.reorg(98, 110, 1)
+	finalizedHeight.Store(105)
+	requireEvent(t, events, node.block(105))
+}
+
+func requireEvent(t *testing.T, events <-chan client.FinalityEvent, block *Block) {
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event := <-events:
+			// the intermediate events are skipped
+			if event.Block.BlockHeight < block.BlockHeight {
+				continue
+			}
+			require.Equal(t, block.L2Block, event.Block)
+			return
+		case <-timeout:
+			t.Fatalf("no event for block %d", block.BlockHeight)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Package api holds the wire types and the error codes of the HTTP/JSON API of the finality gadget server. It has no
+// dependencies, so that the remote clients share them with the server without importing it
+package api
+
+// the error codes of the API, returned with the error message in ErrorResponse
+const (
+	ErrCodeInvalidRequest         = "invalid_request"
+	ErrCodeNotFound               = "not_found"
+	ErrCodeTimestampAheadOfBtcTip = "timestamp_ahead_of_btc_tip"
+	ErrCodeTimestampBeforeGenesis = "timestamp_before_genesis"
+	ErrCodeBtcStakingNotActivated = "btc_staking_not_activated"
+	ErrCodeNoFpHasVotingPower     = "no_fp_has_voting_power"
+	ErrCodeTimeout                = "timeout"
+	ErrCodeCanceled               = "canceled"
+	ErrCodeNotReady               = "not_ready"
+	ErrCodeTrackerNotStarted      = "tracker_not_started"
+	ErrCodeInternal               = "internal"
+)
+
+// APIError is an error returned by the API
+type APIError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// ErrorResponse is the body of the responses with an error status
+type ErrorResponse struct {
+	Error *APIError `json:"error"`
+}
+
+// Block is an L2 block
+type Block struct {
+	Height    uint64 `json:"height"`
+	Hash      string `json:"hash"`
+	Timestamp uint64 `json:"timestamp"`
+}
+
+// BlockFinalizedResponse is the response of GET /v1/blocks/{height}/finalized
+type BlockFinalizedResponse struct {
+	Finalized bool `json:"finalized"`
+}
+
+// the response of GET /v1/blocks/{height}/finality-result is a Result of the sdk/finality package
+
+// RangeFinalizedRequest is the request of POST /v1/blocks/range-finalized, the blocks being consecutive and
+// sorted from low to high
+type RangeFinalizedRequest struct {
+	Blocks []Block `json:"blocks"`
+}
+
+// RangeFinalizedResponse is the response of POST /v1/blocks/range-finalized
+//
+// LastFinalizedHeight is the height of the last block of the row of consecutive finalized blocks starting at the
+// first block, or nil if the first block is not finalized. If the search failed midway, the response has an error
+// status and Error is set, with LastFinalizedHeight the last finalized block found before the failure
+type RangeFinalizedResponse struct {
+	LastFinalizedHeight *uint64   `json:"last_finalized_height"`
+	Error               *APIError `json:"error,omitempty"`
+}
+
+// ActivatedTimestampResponse is the response of GET /v1/btc-staking/activated-timestamp
+type ActivatedTimestampResponse struct {
+	ActivatedTimestamp uint64 `json:"activated_timestamp"`
+}
+
+// StatusResponse is the response of the health and readiness probes
+type StatusResponse struct {
+	Status string `json:"status"`
+}
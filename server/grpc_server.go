@@ -0,0 +1,273 @@
+package server
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/babylonchain/babylon-finality-gadget/proto"
+	"github.com/babylonchain/babylon-finality-gadget/sdk/client"
+	"github.com/babylonchain/babylon-finality-gadget/sdk/cwclient"
+	"github.com/babylonchain/babylon-finality-gadget/sdk/finality"
+	"github.com/babylonchain/babylon-finality-gadget/server/api"
+)
+
+// GRPCServer serves the queries of an SDK client over gRPC, see proto/finalitygadget.proto
+//
+// The errors of the queries are returned as gRPC statuses with a proto.ErrorDetail carrying the same error codes as
+// the HTTP API, so that the remote clients return the same errors as the SDK client
+type GRPCServer struct {
+	proto.UnimplementedFinalityGadgetServer
+
+	sdkClient client.ISdkClient
+	// subscribe returns the finality events streamed by SubscribeFinalizedBlocks, see client.SdkClient.Subscribe
+	subscribe func(ctx context.Context) (<-chan client.FinalityEvent, error)
+	cfg       *Config
+	logger    *zap.Logger
+}
+
+// NewGRPCServer creates a gRPC server over the SDK client. SubscribeFinalizedBlocks streams the events returned by
+// subscribe, and fails if it is nil
+func NewGRPCServer(
+	sdkClient client.ISdkClient,
+	subscribe func(ctx context.Context) (<-chan client.FinalityEvent, error),
+	cfg *Config,
+	logger *zap.Logger,
+) *GRPCServer {
+	return &GRPCServer{
+		sdkClient: sdkClient,
+		subscribe: subscribe,
+		cfg:       cfg,
+		logger:    logger,
+	}
+}
+
+// Serve serves the gRPC API on the listener until the context is done, then stops gracefully, waiting up to
+// Config.ShutdownTimeout for the in-flight requests
+func (s *GRPCServer) Serve(ctx context.Context, listener net.Listener) error {
+	grpcServer := grpc.NewServer()
+	proto.RegisterFinalityGadgetServer(grpcServer, s)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- grpcServer.Serve(listener)
+	}()
+	s.logger.Info("Finality gadget gRPC server started", zap.String("address", listener.Addr().String()))
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	s.logger.Info("Shutting down the finality gadget gRPC server")
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(s.cfg.ShutdownTimeout):
+		// cancel the requests still in flight
+		grpcServer.Stop()
+	}
+	return nil
+}
+
+func (s *GRPCServer) QueryIsBlockBabylonFinalized(
+	ctx context.Context,
+	req *proto.QueryIsBlockBabylonFinalizedRequest,
+) (*proto.QueryIsBlockBabylonFinalizedResponse, error) {
+	if req.Block == nil {
+		return nil, invalidArgument("the block is not set")
+	}
+
+	ctx, cancel := s.requestContext(ctx)
+	defer cancel()
+	finalized, err := s.sdkClient.QueryIsBlockBabylonFinalized(ctx, *fromProtoBlock(req.Block))
+	if err != nil {
+		return nil, s.toStatusError("QueryIsBlockBabylonFinalized", err, nil)
+	}
+	return &proto.QueryIsBlockBabylonFinalizedResponse{IsFinalized: finalized}, nil
+}
+
+func (s *GRPCServer) QueryBlockRangeBabylonFinalized(
+	ctx context.Context,
+	req *proto.QueryBlockRangeBabylonFinalizedRequest,
+) (*proto.QueryBlockRangeBabylonFinalizedResponse, error) {
+	queryBlocks := make([]*cwclient.L2Block, len(req.Blocks))
+	for i, block := range req.Blocks {
+		if block == nil {
+			return nil, invalidArgument("the block is not set")
+		}
+		queryBlocks[i] = fromProtoBlock(block)
+	}
+	if err := checkBlockRange(queryBlocks); err != nil {
+		return nil, invalidArgument(err.Error())
+	}
+
+	ctx, cancel := s.requestContext(ctx)
+	defer cancel()
+	lastFinalizedHeight, err := s.sdkClient.QueryBlockRangeBabylonFinalized(ctx, queryBlocks)
+	if err != nil {
+		// the last finalized block found before the failure goes with the error
+		return nil, s.toStatusError("QueryBlockRangeBabylonFinalized", err, lastFinalizedHeight)
+	}
+	return &proto.QueryBlockRangeBabylonFinalizedResponse{LastFinalizedBlockHeight: lastFinalizedHeight}, nil
+}
+
+func (s *GRPCServer) QueryBtcStakingActivatedTimestamp(
+	ctx context.Context,
+	req *proto.QueryBtcStakingActivatedTimestampRequest,
+) (*proto.QueryBtcStakingActivatedTimestampResponse, error) {
+	ctx, cancel := s.requestContext(ctx)
+	defer cancel()
+	activatedTimestamp, err := s.sdkClient.QueryBtcStakingActivatedTimestamp(ctx)
+	if err != nil {
+		return nil, s.toStatusError("QueryBtcStakingActivatedTimestamp", err, nil)
+	}
+	return &proto.QueryBtcStakingActivatedTimestampResponse{ActivatedTimestamp: activatedTimestamp}, nil
+}
+
+func (s *GRPCServer) QueryBlockFinalityResult(
+	ctx context.Context,
+	req *proto.QueryBlockFinalityResultRequest,
+) (*proto.QueryBlockFinalityResultResponse, error) {
+	if req.Block == nil {
+		return nil, invalidArgument("the block is not set")
+	}
+
+	ctx, cancel := s.requestContext(ctx)
+	defer cancel()
+	result, err := s.sdkClient.QueryBlockFinalityResult(ctx, *fromProtoBlock(req.Block))
+	if err != nil {
+		return nil, s.toStatusError("QueryBlockFinalityResult", err, nil)
+	}
+	return toProtoFinalityResult(result), nil
+}
+
+func (s *GRPCServer) SubscribeFinalizedBlocks(
+	_ *proto.SubscribeFinalizedBlocksRequest,
+	stream proto.FinalityGadget_SubscribeFinalizedBlocksServer,
+) error {
+	if s.subscribe == nil {
+		return s.toStatusError("SubscribeFinalizedBlocks", client.ErrTrackerNotStarted, nil)
+	}
+	events, err := s.subscribe(stream.Context())
+	if err != nil {
+		return s.toStatusError("SubscribeFinalizedBlocks", err, nil)
+	}
+	// the headers tell the client that the subscription succeeded before the first event, which may take a while
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-events:
+			// the tracker stopped
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(&proto.SubscribeFinalizedBlocksResponse{Block: toProtoBlock(&event.Block)}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// requestContext bounds the queries of the request by Config.RequestTimeout
+func (s *GRPCServer) requestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.cfg.RequestTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, s.cfg.RequestTimeout)
+}
+
+// toStatusError maps the error of a query to a gRPC status carrying its API error code
+func (s *GRPCServer) toStatusError(method string, err error, lastFinalizedHeight *uint64) error {
+	code := errorCode(err)
+	switch code {
+	case api.ErrCodeInternal:
+		s.logger.Error("Failed to serve the finality query", zap.String("method", method), zap.Error(err))
+	case api.ErrCodeCanceled:
+		s.logger.Debug("The finality query was canceled", zap.String("method", method), zap.Error(err))
+	}
+	st, detailErr := status.New(grpcCodes[code], err.Error()).WithDetails(&proto.ErrorDetail{
+		Code:                     code,
+		LastFinalizedBlockHeight: lastFinalizedHeight,
+	})
+	if detailErr != nil {
+		return status.Error(grpcCodes[code], err.Error())
+	}
+	return st.Err()
+}
+
+// grpcCodes are the gRPC codes of the error codes of the queries
+var grpcCodes = map[string]codes.Code{
+	api.ErrCodeInvalidRequest:         codes.InvalidArgument,
+	api.ErrCodeTimestampAheadOfBtcTip: codes.Unavailable,
+	api.ErrCodeTimestampBeforeGenesis: codes.OutOfRange,
+	api.ErrCodeBtcStakingNotActivated: codes.FailedPrecondition,
+	api.ErrCodeNoFpHasVotingPower:     codes.FailedPrecondition,
+	api.ErrCodeTrackerNotStarted:      codes.FailedPrecondition,
+	api.ErrCodeTimeout:                codes.DeadlineExceeded,
+	api.ErrCodeCanceled:               codes.Canceled,
+	api.ErrCodeInternal:               codes.Internal,
+}
+
+func invalidArgument(message string) error {
+	st, err := status.New(codes.InvalidArgument, message).WithDetails(&proto.ErrorDetail{Code: api.ErrCodeInvalidRequest})
+	if err != nil {
+		return status.Error(codes.InvalidArgument, message)
+	}
+	return st.Err()
+}
+
+func fromProtoBlock(block *proto.Block) *cwclient.L2Block {
+	return &cwclient.L2Block{
+		BlockHeight:    block.BlockHeight,
+		BlockHash:      block.BlockHash,
+		BlockTimestamp: block.BlockTimestamp,
+	}
+}
+
+func toProtoBlock(block *cwclient.L2Block) *proto.Block {
+	return &proto.Block{
+		BlockHeight:    block.BlockHeight,
+		BlockHash:      block.BlockHash,
+		BlockTimestamp: block.BlockTimestamp,
+	}
+}
+
+func toProtoFinalityResult(result *finality.Result) *proto.QueryBlockFinalityResultResponse {
+	resp := &proto.QueryBlockFinalityResultResponse{
+		Enabled:           result.Enabled,
+		Finalized:         result.Finalized,
+		BtcHeight:         result.BtcHeight,
+		FpVotes:           make([]*proto.FpVote, len(result.FpVotes)),
+		ConflictingFps:    result.ConflictingFps,
+		QuorumNumerator:   result.QuorumNumerator,
+		QuorumDenominator: result.QuorumDenominator,
+	}
+	// the voting power is unset if the finality gadget is disabled
+	if result.TotalPower != nil {
+		resp.TotalPower = result.TotalPower.String()
+	}
+	if result.VotedPower != nil {
+		resp.VotedPower = result.VotedPower.String()
+	}
+	for i, vote := range result.FpVotes {
+		resp.FpVotes[i] = &proto.FpVote{FpBtcPkHex: vote.FpBtcPkHex, Power: vote.Power, Voted: vote.Voted}
+	}
+	return resp
+}
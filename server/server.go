@@ -0,0 +1,362 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/babylon-finality-gadget/sdk/client"
+	"github.com/babylonchain/babylon-finality-gadget/sdk/cwclient"
+	"github.com/babylonchain/babylon-finality-gadget/server/api"
+)
+
+const (
+	// maxRequestBodySize bounds the body of a request, i.e. ~8k blocks for a range
+	maxRequestBodySize = 1 << 20
+	readHeaderTimeout  = 10 * time.Second
+)
+
+// Config defines configuration for the finality gadget server
+type Config struct {
+	// RequestTimeout bounds the queries of a request to Babylon and Bitcoin. Set to 0 to disable
+	RequestTimeout time.Duration
+	// ShutdownTimeout bounds the time the in-flight requests are waited for on shutdown
+	ShutdownTimeout time.Duration
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		RequestTimeout:  30 * time.Second,
+		ShutdownTimeout: 30 * time.Second,
+	}
+}
+
+// Server serves the finality view of an SDK client over HTTP/JSON, so that several L2 services share it
+//
+//   - GET /v1/blocks/{height}/finalized?hash=&ts= returns whether the L2 block is finalized
+//   - GET /v1/blocks/{height}/finality-result?hash=&ts= returns the detailed finality verdict of the L2 block
+//   - POST /v1/blocks/range-finalized returns the last finalized block of a block range
+//   - GET /v1/btc-staking/activated-timestamp returns the timestamp the BTC staking was activated at
+//   - GET /healthz is the liveness probe, and GET /readyz the readiness probe, which fails once the server
+//     shuts down or if the readiness check fails
+type Server struct {
+	sdkClient client.ISdkClient
+	// readinessCheck checks that the server can serve the queries, e.g. that Babylon is reachable
+	readinessCheck func(ctx context.Context) error
+	cfg            *Config
+	logger         *zap.Logger
+
+	shuttingDown atomic.Bool
+}
+
+// New creates a server over the SDK client. readinessCheck may be nil
+func New(
+	sdkClient client.ISdkClient,
+	readinessCheck func(ctx context.Context) error,
+	cfg *Config,
+	logger *zap.Logger,
+) *Server {
+	return &Server{
+		sdkClient:      sdkClient,
+		readinessCheck: readinessCheck,
+		cfg:            cfg,
+		logger:         logger,
+	}
+}
+
+// Serve serves the API on the listener until the context is done, then shuts down gracefully, waiting up to
+// Config.ShutdownTimeout for the in-flight requests
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	httpServer := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- httpServer.Serve(listener)
+	}()
+	s.logger.Info("Finality gadget server started", zap.String("address", listener.Addr().String()))
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	s.logger.Info("Shutting down the finality gadget server")
+	s.shuttingDown.Store(true)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.cfg.ShutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down the finality gadget server: %w", err)
+	}
+	return nil
+}
+
+// Handler returns the HTTP handler of the API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/blocks/", s.handleBlocks)
+	mux.HandleFunc("/v1/btc-staking/activated-timestamp", s.handleActivatedTimestamp)
+	mux.HandleFunc("/healthz", s.handleHealth)
+	mux.HandleFunc("/readyz", s.handleReady)
+	return mux
+}
+
+// handleBlocks routes /v1/blocks/range-finalized and /v1/blocks/{height}/{finalized,finality-result}
+func (s *Server) handleBlocks(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/v1/blocks/")
+	if path == "range-finalized" {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+		s.handleRangeFinalized(w, r)
+		return
+	}
+
+	heightStr, query, ok := strings.Cut(path, "/")
+	if !ok || (query != "finalized" && query != "finality-result") {
+		writeError(w, http.StatusNotFound, &api.APIError{Code: api.ErrCodeNotFound, Message: "unknown path " + r.URL.Path})
+		return
+	}
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	block, err := parseBlock(r, heightStr)
+	if err != nil {
+		writeInvalidRequest(w, err.Error())
+		return
+	}
+	if query == "finalized" {
+		s.handleBlockFinalized(w, r, block)
+	} else {
+		s.handleFinalityResult(w, r, block)
+	}
+}
+
+// parseBlock parses the L2 block of the path /v1/blocks/{height}/...?hash=&ts=
+func parseBlock(r *http.Request, heightStr string) (*cwclient.L2Block, error) {
+	height, err := strconv.ParseUint(heightStr, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid block height %q", heightStr)
+	}
+	query := r.URL.Query()
+	hash := query.Get("hash")
+	if hash == "" {
+		return nil, errors.New("the block hash is not set")
+	}
+	timestamp, err := strconv.ParseUint(query.Get("ts"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid block timestamp %q", query.Get("ts"))
+	}
+	return &cwclient.L2Block{
+		BlockHeight:    height,
+		BlockHash:      hash,
+		BlockTimestamp: timestamp,
+	}, nil
+}
+
+func (s *Server) handleBlockFinalized(w http.ResponseWriter, r *http.Request, block *cwclient.L2Block) {
+	ctx, cancel := s.requestContext(r)
+	defer cancel()
+	finalized, err := s.sdkClient.QueryIsBlockBabylonFinalized(ctx, *block)
+	if err != nil {
+		s.writeQueryError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, &api.BlockFinalizedResponse{Finalized: finalized})
+}
+
+func (s *Server) handleFinalityResult(w http.ResponseWriter, r *http.Request, block *cwclient.L2Block) {
+	ctx, cancel := s.requestContext(r)
+	defer cancel()
+	result, err := s.sdkClient.QueryBlockFinalityResult(ctx, *block)
+	if err != nil {
+		s.writeQueryError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handleRangeFinalized(w http.ResponseWriter, r *http.Request) {
+	var req api.RangeFinalizedRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodySize)).Decode(&req); err != nil {
+		writeInvalidRequest(w, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+	queryBlocks := make([]*cwclient.L2Block, len(req.Blocks))
+	for i := range req.Blocks {
+		queryBlocks[i] = toL2Block(&req.Blocks[i])
+	}
+	if err := checkBlockRange(queryBlocks); err != nil {
+		writeInvalidRequest(w, err.Error())
+		return
+	}
+
+	ctx, cancel := s.requestContext(r)
+	defer cancel()
+	lastFinalizedHeight, err := s.sdkClient.QueryBlockRangeBabylonFinalized(ctx, queryBlocks)
+	if err != nil {
+		status, apiErr := s.toAPIError(r, err)
+		writeJSON(w, status, &api.RangeFinalizedResponse{LastFinalizedHeight: lastFinalizedHeight, Error: apiErr})
+		return
+	}
+	writeJSON(w, http.StatusOK, &api.RangeFinalizedResponse{LastFinalizedHeight: lastFinalizedHeight})
+}
+
+func (s *Server) handleActivatedTimestamp(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	ctx, cancel := s.requestContext(r)
+	defer cancel()
+	activatedTimestamp, err := s.sdkClient.QueryBtcStakingActivatedTimestamp(ctx)
+	if err != nil {
+		s.writeQueryError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, &api.ActivatedTimestampResponse{ActivatedTimestamp: activatedTimestamp})
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, &api.StatusResponse{Status: "ok"})
+}
+
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	if s.shuttingDown.Load() {
+		writeError(w, http.StatusServiceUnavailable, &api.APIError{Code: api.ErrCodeNotReady, Message: "shutting down"})
+		return
+	}
+	if s.readinessCheck != nil {
+		ctx, cancel := s.requestContext(r)
+		defer cancel()
+		if err := s.readinessCheck(ctx); err != nil {
+			writeError(w, http.StatusServiceUnavailable, &api.APIError{Code: api.ErrCodeNotReady, Message: err.Error()})
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, &api.StatusResponse{Status: "ready"})
+}
+
+// requestContext bounds the queries of the request by Config.RequestTimeout
+func (s *Server) requestContext(r *http.Request) (context.Context, context.CancelFunc) {
+	if s.cfg.RequestTimeout <= 0 {
+		return context.WithCancel(r.Context())
+	}
+	return context.WithTimeout(r.Context(), s.cfg.RequestTimeout)
+}
+
+func (s *Server) writeQueryError(w http.ResponseWriter, r *http.Request, err error) {
+	status, apiErr := s.toAPIError(r, err)
+	writeError(w, status, apiErr)
+}
+
+// toAPIError maps the error of a query to its HTTP status and API error
+func (s *Server) toAPIError(r *http.Request, err error) (int, *api.APIError) {
+	code := errorCode(err)
+	switch code {
+	case api.ErrCodeInternal:
+		s.logger.Error("Failed to serve the finality query", zap.String("path", r.URL.Path), zap.Error(err))
+	case api.ErrCodeCanceled:
+		s.logger.Debug("The finality query was canceled", zap.String("path", r.URL.Path), zap.Error(err))
+	}
+	return httpStatuses[code], &api.APIError{Code: code, Message: err.Error()}
+}
+
+// errorCode maps the error of a query to its API error code, shared by the HTTP and gRPC APIs
+func errorCode(err error) string {
+	switch {
+	case errors.Is(err, client.ErrTimestampAheadOfBtcTip):
+		return api.ErrCodeTimestampAheadOfBtcTip
+	case errors.Is(err, client.ErrTimestampBeforeGenesis):
+		return api.ErrCodeTimestampBeforeGenesis
+	case errors.Is(err, client.ErrBtcStakingNotActivated):
+		return api.ErrCodeBtcStakingNotActivated
+	case errors.Is(err, client.ErrNoFpHasVotingPower):
+		return api.ErrCodeNoFpHasVotingPower
+	case errors.Is(err, client.ErrTrackerNotStarted):
+		return api.ErrCodeTrackerNotStarted
+	case errors.Is(err, context.DeadlineExceeded):
+		return api.ErrCodeTimeout
+	// the client went away, or the server is shutting down
+	case errors.Is(err, context.Canceled):
+		return api.ErrCodeCanceled
+	default:
+		return api.ErrCodeInternal
+	}
+}
+
+// statusClientClosedRequest is the non-standard HTTP status of nginx for a request canceled by the client
+const statusClientClosedRequest = 499
+
+// httpStatuses are the HTTP statuses of the error codes of the queries
+var httpStatuses = map[string]int{
+	api.ErrCodeTimestampAheadOfBtcTip: http.StatusTooEarly,
+	api.ErrCodeTimestampBeforeGenesis: http.StatusBadRequest,
+	api.ErrCodeBtcStakingNotActivated: http.StatusConflict,
+	api.ErrCodeNoFpHasVotingPower:     http.StatusConflict,
+	api.ErrCodeTrackerNotStarted:      http.StatusServiceUnavailable,
+	api.ErrCodeTimeout:                http.StatusGatewayTimeout,
+	api.ErrCodeCanceled:               statusClientClosedRequest,
+	api.ErrCodeInternal:               http.StatusInternalServerError,
+}
+
+func toL2Block(block *api.Block) *cwclient.L2Block {
+	return &cwclient.L2Block{
+		BlockHeight:    block.Height,
+		BlockHash:      block.Hash,
+		BlockTimestamp: block.Timestamp,
+	}
+}
+
+// checkBlockRange checks that the blocks of a range query are consecutive and sorted from low to high
+func checkBlockRange(blocks []*cwclient.L2Block) error {
+	if len(blocks) == 0 {
+		return errors.New("no block is given")
+	}
+	for i := 1; i < len(blocks); i++ {
+		if blocks[i].BlockHeight != blocks[i-1].BlockHeight+1 {
+			return fmt.Errorf("block %d does not follow block %d", blocks[i].BlockHeight, blocks[i-1].BlockHeight)
+		}
+	}
+	return nil
+}
+
+// allowMethod writes a 405 response unless the request has the given method
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	writeError(w, http.StatusMethodNotAllowed, &api.APIError{
+		Code:    api.ErrCodeInvalidRequest,
+		Message: fmt.Sprintf("method %s is not allowed", r.Method),
+	})
+	return false
+}
+
+func writeInvalidRequest(w http.ResponseWriter, message string) {
+	writeError(w, http.StatusBadRequest, &api.APIError{Code: api.ErrCodeInvalidRequest, Message: message})
+}
+
+func writeError(w http.ResponseWriter, status int, apiErr *api.APIError) {
+	writeJSON(w, status, &api.ErrorResponse{Error: apiErr})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	// the status is already sent, so an encoding error can only be dropped
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,275 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/babylon-finality-gadget/sdk/client"
+	"github.com/babylonchain/babylon-finality-gadget/sdk/cwclient"
+	"github.com/babylonchain/babylon-finality-gadget/sdk/finality"
+	"github.com/babylonchain/babylon-finality-gadget/server/api"
+	"github.com/babylonchain/babylon-finality-gadget/testutil/mocks"
+)
+
+func newTestServer(t *testing.T, readinessCheck func(ctx context.Context) error) (*mocks.MockISdkClient, *httptest.Server) {
+	ctl := gomock.NewController(t)
+	sdkClient := mocks.NewMockISdkClient(ctl)
+	s := New(sdkClient, readinessCheck, DefaultConfig(), zap.NewNop())
+	httpServer := httptest.NewServer(s.Handler())
+	t.Cleanup(httpServer.Close)
+	return sdkClient, httpServer
+}
+
+// doRequest sends the request, and decodes the JSON response body into resp
+func doRequest(t *testing.T, method string, url string, body interface{}, resp interface{}) int {
+	var reqBody bytes.Buffer
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&reqBody).Encode(body))
+	}
+	req, err := http.NewRequest(method, url, &reqBody)
+	require.NoError(t, err)
+	httpResp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer httpResp.Body.Close()
+	require.Equal(t, "application/json", httpResp.Header.Get("Content-Type"))
+	require.NoError(t, json.NewDecoder(httpResp.Body).Decode(resp))
+	return httpResp.StatusCode
+}
+
+func TestBlockFinalized(t *testing.T) {
+	sdkClient, httpServer := newTestServer(t, nil)
+	block := cwclient.L2Block{BlockHeight: 100, BlockHash: "0x1234", BlockTimestamp: 1_700_000_000}
+
+	sdkClient.EXPECT().QueryIsBlockBabylonFinalized(gomock.Any(), block).Return(true, nil).Times(1)
+	var resp api.BlockFinalizedResponse
+	status := doRequest(t, http.MethodGet, httpServer.URL+"/v1/blocks/100/finalized?hash=0x1234&ts=1700000000", nil, &resp)
+	require.Equal(t, http.StatusOK, status)
+	require.True(t, resp.Finalized)
+
+	sdkClient.EXPECT().QueryIsBlockBabylonFinalized(gomock.Any(), block).Return(false, nil).Times(1)
+	status = doRequest(t, http.MethodGet, httpServer.URL+"/v1/blocks/100/finalized?hash=0x1234&ts=1700000000", nil, &resp)
+	require.Equal(t, http.StatusOK, status)
+	require.False(t, resp.Finalized)
+}
+
+func TestFinalityResult(t *testing.T) {
+	sdkClient, httpServer := newTestServer(t, nil)
+	block := cwclient.L2Block{BlockHeight: 100, BlockHash: "0x1234", BlockTimestamp: 1_700_000_000}
+	result := &finality.Result{
+		Enabled:           true,
+		Finalized:         true,
+		BtcHeight:         1000,
+		TotalPower:        big.NewInt(600),
+		VotedPower:        big.NewInt(500),
+		FpVotes:           []finality.FpVote{{FpBtcPkHex: "fp1", Power: 500, Voted: true}, {FpBtcPkHex: "fp2", Power: 100}},
+		ConflictingFps:    []string{"fp2"},
+		QuorumNumerator:   2,
+		QuorumDenominator: 3,
+	}
+
+	sdkClient.EXPECT().QueryBlockFinalityResult(gomock.Any(), block).Return(result, nil).Times(1)
+	var resp finality.Result
+	status := doRequest(t, http.MethodGet,
+		httpServer.URL+"/v1/blocks/100/finality-result?hash=0x1234&ts=1700000000", nil, &resp)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, result, &resp)
+}
+
+func TestBlockFinalizedErrors(t *testing.T) {
+	testCases := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedCode   string
+	}{
+		{"ahead of BTC tip", fmt.Errorf("query: %w", client.ErrTimestampAheadOfBtcTip),
+			http.StatusTooEarly, api.ErrCodeTimestampAheadOfBtcTip},
+		{"before BTC genesis", client.ErrTimestampBeforeGenesis, http.StatusBadRequest, api.ErrCodeTimestampBeforeGenesis},
+		{"BTC staking not activated", client.ErrBtcStakingNotActivated,
+			http.StatusConflict, api.ErrCodeBtcStakingNotActivated},
+		{"no FP has voting power", client.ErrNoFpHasVotingPower, http.StatusConflict, api.ErrCodeNoFpHasVotingPower},
+		{"timeout", context.DeadlineExceeded, http.StatusGatewayTimeout, api.ErrCodeTimeout},
+		{"canceled", fmt.Errorf("query: %w", context.Canceled), statusClientClosedRequest, api.ErrCodeCanceled},
+		{"internal", fmt.Errorf("connection refused"), http.StatusInternalServerError, api.ErrCodeInternal},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sdkClient, httpServer := newTestServer(t, nil)
+			sdkClient.EXPECT().QueryIsBlockBabylonFinalized(gomock.Any(), gomock.Any()).Return(false, tc.err).Times(1)
+
+			var resp api.ErrorResponse
+			status := doRequest(t, http.MethodGet, httpServer.URL+"/v1/blocks/1/finalized?hash=0x12&ts=1", nil, &resp)
+			require.Equal(t, tc.expectedStatus, status)
+			require.Equal(t, tc.expectedCode, resp.Error.Code)
+			require.Equal(t, tc.err.Error(), resp.Error.Message)
+		})
+	}
+}
+
+func TestBlockFinalizedInvalidRequest(t *testing.T) {
+	_, httpServer := newTestServer(t, nil)
+
+	testCases := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+	}{
+		{"invalid height", http.MethodGet, "/v1/blocks/abc/finalized?hash=0x12&ts=1", http.StatusBadRequest},
+		{"no hash", http.MethodGet, "/v1/blocks/1/finalized?ts=1", http.StatusBadRequest},
+		{"invalid timestamp", http.MethodGet, "/v1/blocks/1/finalized?hash=0x12&ts=-1", http.StatusBadRequest},
+		{"unknown path", http.MethodGet, "/v1/blocks/1/voters", http.StatusNotFound},
+		{"nested path", http.MethodGet, "/v1/blocks/1/2/finalized?hash=0x12&ts=1", http.StatusNotFound},
+		{"wrong method", http.MethodPost, "/v1/blocks/1/finalized?hash=0x12&ts=1", http.StatusMethodNotAllowed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var resp api.ErrorResponse
+			status := doRequest(t, tc.method, httpServer.URL+tc.path, nil, &resp)
+			require.Equal(t, tc.expectedStatus, status)
+			require.NotEmpty(t, resp.Error.Message)
+		})
+	}
+}
+
+func TestRangeFinalized(t *testing.T) {
+	sdkClient, httpServer := newTestServer(t, nil)
+	req := &api.RangeFinalizedRequest{Blocks: []api.Block{
+		{Height: 1, Hash: "0x01", Timestamp: 10},
+		{Height: 2, Hash: "0x02", Timestamp: 20},
+		{Height: 3, Hash: "0x03", Timestamp: 30},
+	}}
+	queryBlocks := []*cwclient.L2Block{
+		{BlockHeight: 1, BlockHash: "0x01", BlockTimestamp: 10},
+		{BlockHeight: 2, BlockHash: "0x02", BlockTimestamp: 20},
+		{BlockHeight: 3, BlockHash: "0x03", BlockTimestamp: 30},
+	}
+	url := httpServer.URL + "/v1/blocks/range-finalized"
+
+	lastFinalizedHeight := uint64(2)
+	sdkClient.EXPECT().QueryBlockRangeBabylonFinalized(gomock.Any(), queryBlocks).Return(&lastFinalizedHeight, nil).Times(1)
+	var resp api.RangeFinalizedResponse
+	require.Equal(t, http.StatusOK, doRequest(t, http.MethodPost, url, req, &resp))
+	require.Equal(t, &lastFinalizedHeight, resp.LastFinalizedHeight)
+	require.Nil(t, resp.Error)
+
+	// no block is finalized
+	sdkClient.EXPECT().QueryBlockRangeBabylonFinalized(gomock.Any(), queryBlocks).Return(nil, nil).Times(1)
+	resp = api.RangeFinalizedResponse{}
+	require.Equal(t, http.StatusOK, doRequest(t, http.MethodPost, url, req, &resp))
+	require.Nil(t, resp.LastFinalizedHeight)
+
+	// the search fails midway, the last finalized block found is returned with the error
+	sdkClient.EXPECT().QueryBlockRangeBabylonFinalized(gomock.Any(), queryBlocks).
+		Return(&lastFinalizedHeight, client.ErrTimestampAheadOfBtcTip).Times(1)
+	resp = api.RangeFinalizedResponse{}
+	require.Equal(t, http.StatusTooEarly, doRequest(t, http.MethodPost, url, req, &resp))
+	require.Equal(t, &lastFinalizedHeight, resp.LastFinalizedHeight)
+	require.Equal(t, api.ErrCodeTimestampAheadOfBtcTip, resp.Error.Code)
+}
+
+func TestRangeFinalizedInvalidRequest(t *testing.T) {
+	_, httpServer := newTestServer(t, nil)
+	url := httpServer.URL + "/v1/blocks/range-finalized"
+
+	testCases := []struct {
+		name string
+		body interface{}
+	}{
+		{"no block", &api.RangeFinalizedRequest{}},
+		{"not consecutive", &api.RangeFinalizedRequest{Blocks: []api.Block{{Height: 1}, {Height: 3}}}},
+		{"not a JSON object", []int{1, 2}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var resp api.ErrorResponse
+			require.Equal(t, http.StatusBadRequest, doRequest(t, http.MethodPost, url, tc.body, &resp))
+			require.Equal(t, api.ErrCodeInvalidRequest, resp.Error.Code)
+		})
+	}
+}
+
+func TestActivatedTimestamp(t *testing.T) {
+	sdkClient, httpServer := newTestServer(t, nil)
+	url := httpServer.URL + "/v1/btc-staking/activated-timestamp"
+
+	sdkClient.EXPECT().QueryBtcStakingActivatedTimestamp(gomock.Any()).Return(uint64(1_700_000_000), nil).Times(1)
+	var resp api.ActivatedTimestampResponse
+	require.Equal(t, http.StatusOK, doRequest(t, http.MethodGet, url, nil, &resp))
+	require.Equal(t, uint64(1_700_000_000), resp.ActivatedTimestamp)
+
+	sdkClient.EXPECT().QueryBtcStakingActivatedTimestamp(gomock.Any()).
+		Return(uint64(math.MaxUint64), client.ErrBtcStakingNotActivated).Times(1)
+	var errResp api.ErrorResponse
+	require.Equal(t, http.StatusConflict, doRequest(t, http.MethodGet, url, nil, &errResp))
+	require.Equal(t, api.ErrCodeBtcStakingNotActivated, errResp.Error.Code)
+}
+
+func TestProbes(t *testing.T) {
+	var readinessErr error
+	_, httpServer := newTestServer(t, func(context.Context) error { return readinessErr })
+
+	var resp api.StatusResponse
+	require.Equal(t, http.StatusOK, doRequest(t, http.MethodGet, httpServer.URL+"/healthz", nil, &resp))
+	require.Equal(t, http.StatusOK, doRequest(t, http.MethodGet, httpServer.URL+"/readyz", nil, &resp))
+
+	// the server is alive but not ready while Babylon is unreachable
+	readinessErr = fmt.Errorf("all Babylon RPC endpoints failed")
+	require.Equal(t, http.StatusOK, doRequest(t, http.MethodGet, httpServer.URL+"/healthz", nil, &resp))
+	var errResp api.ErrorResponse
+	require.Equal(t, http.StatusServiceUnavailable, doRequest(t, http.MethodGet, httpServer.URL+"/readyz", nil, &errResp))
+	require.Equal(t, api.ErrCodeNotReady, errResp.Error.Code)
+}
+
+func TestServeGracefulShutdown(t *testing.T) {
+	ctl := gomock.NewController(t)
+	sdkClient := mocks.NewMockISdkClient(ctl)
+	s := New(sdkClient, nil, DefaultConfig(), zap.NewNop())
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.Serve(ctx, listener)
+	}()
+
+	// an in-flight request is completed once the shutdown starts
+	started := make(chan struct{})
+	sdkClient.EXPECT().QueryBtcStakingActivatedTimestamp(gomock.Any()).DoAndReturn(func(context.Context) (uint64, error) {
+		close(started)
+		time.Sleep(100 * time.Millisecond)
+		return 1_700_000_000, nil
+	}).Times(1)
+	respCh := make(chan api.ActivatedTimestampResponse, 1)
+	go func() {
+		var resp api.ActivatedTimestampResponse
+		doRequest(t, http.MethodGet, "http://"+listener.Addr().String()+"/v1/btc-staking/activated-timestamp", nil, &resp)
+		respCh <- resp
+	}()
+	<-started
+	cancel()
+
+	require.NoError(t, <-serveErr)
+	require.Equal(t, uint64(1_700_000_000), (<-respCh).ActivatedTimestamp)
+	require.True(t, s.shuttingDown.Load())
+	// the listener is closed
+	_, err = http.Get("http://" + listener.Addr().String() + "/healthz")
+	require.Error(t, err)
+}
@@ -1,6 +1,8 @@
 package testutil
 
 import (
+	"context"
+
 	"github.com/babylonchain/babylon-finality-gadget/sdk/btcclient"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
@@ -21,7 +23,7 @@ func NewMockBTCClient(cfg *btcclient.BTCConfig, logger *zap.Logger) (*MockBtcCli
 }
 
 // GetBlockHeightByTimestamp overrides the BTCClient's GetBlockHeightByTimestamp method.
-func (c *MockBtcClient) GetBlockHeightByTimestamp(targetTimestamp uint64) (uint64, error) {
+func (c *MockBtcClient) GetBlockHeightByTimestamp(ctx context.Context, targetTimestamp uint64) (uint64, error) {
 	// has to be a small number so when FP e2e tests use it, the test can finish quickly
 	// if it's too large, it will result in unbounding of the delegation
 	return 10, nil
@@ -29,6 +31,6 @@ func (c *MockBtcClient) GetBlockHeightByTimestamp(targetTimestamp uint64) (uint6
 
 // this is used to determine when the BTC staking is activated. return 0 to
 // simulate that the BTC staking is always activated
-func (c *MockBtcClient) GetBlockTimestampByHeight(height uint64) (uint64, error) {
+func (c *MockBtcClient) GetBlockTimestampByHeight(ctx context.Context, height uint64) (uint64, error) {
 	return 0, nil
 }
@@ -10,6 +10,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	cwclient "github.com/babylonchain/babylon-finality-gadget/sdk/cwclient"
@@ -42,63 +43,78 @@ func (m *MockIBabylonClient) EXPECT() *MockIBabylonClientMockRecorder {
 }
 
 // QueryAllFpBtcPubKeys mocks base method.
-func (m *MockIBabylonClient) QueryAllFpBtcPubKeys(consumerId string) ([]string, error) {
+func (m *MockIBabylonClient) QueryAllFpBtcPubKeys(ctx context.Context, consumerId string) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QueryAllFpBtcPubKeys", consumerId)
+	ret := m.ctrl.Call(m, "QueryAllFpBtcPubKeys", ctx, consumerId)
 	ret0, _ := ret[0].([]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // QueryAllFpBtcPubKeys indicates an expected call of QueryAllFpBtcPubKeys.
-func (mr *MockIBabylonClientMockRecorder) QueryAllFpBtcPubKeys(consumerId any) *gomock.Call {
+func (mr *MockIBabylonClientMockRecorder) QueryAllFpBtcPubKeys(ctx, consumerId any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryAllFpBtcPubKeys", reflect.TypeOf((*MockIBabylonClient)(nil).QueryAllFpBtcPubKeys), consumerId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryAllFpBtcPubKeys", reflect.TypeOf((*MockIBabylonClient)(nil).QueryAllFpBtcPubKeys), ctx, consumerId)
 }
 
 // QueryEarliestActiveDelBtcHeight mocks base method.
-func (m *MockIBabylonClient) QueryEarliestActiveDelBtcHeight(fpPubkeyHexList []string) (uint64, error) {
+func (m *MockIBabylonClient) QueryEarliestActiveDelBtcHeight(ctx context.Context, fpPubkeyHexList []string) (uint64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QueryEarliestActiveDelBtcHeight", fpPubkeyHexList)
+	ret := m.ctrl.Call(m, "QueryEarliestActiveDelBtcHeight", ctx, fpPubkeyHexList)
 	ret0, _ := ret[0].(uint64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // QueryEarliestActiveDelBtcHeight indicates an expected call of QueryEarliestActiveDelBtcHeight.
-func (mr *MockIBabylonClientMockRecorder) QueryEarliestActiveDelBtcHeight(fpPubkeyHexList any) *gomock.Call {
+func (mr *MockIBabylonClientMockRecorder) QueryEarliestActiveDelBtcHeight(ctx, fpPubkeyHexList any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryEarliestActiveDelBtcHeight", reflect.TypeOf((*MockIBabylonClient)(nil).QueryEarliestActiveDelBtcHeight), fpPubkeyHexList)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryEarliestActiveDelBtcHeight", reflect.TypeOf((*MockIBabylonClient)(nil).QueryEarliestActiveDelBtcHeight), ctx, fpPubkeyHexList)
 }
 
 // QueryFpPower mocks base method.
-func (m *MockIBabylonClient) QueryFpPower(fpPubkeyHex string, btcHeight uint64) (uint64, error) {
+func (m *MockIBabylonClient) QueryFpPower(ctx context.Context, fpPubkeyHex string, btcHeight uint64) (uint64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QueryFpPower", fpPubkeyHex, btcHeight)
+	ret := m.ctrl.Call(m, "QueryFpPower", ctx, fpPubkeyHex, btcHeight)
 	ret0, _ := ret[0].(uint64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // QueryFpPower indicates an expected call of QueryFpPower.
-func (mr *MockIBabylonClientMockRecorder) QueryFpPower(fpPubkeyHex, btcHeight any) *gomock.Call {
+func (mr *MockIBabylonClientMockRecorder) QueryFpPower(ctx, fpPubkeyHex, btcHeight any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryFpPower", reflect.TypeOf((*MockIBabylonClient)(nil).QueryFpPower), fpPubkeyHex, btcHeight)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryFpPower", reflect.TypeOf((*MockIBabylonClient)(nil).QueryFpPower), ctx, fpPubkeyHex, btcHeight)
 }
 
 // QueryMultiFpPower mocks base method.
-func (m *MockIBabylonClient) QueryMultiFpPower(fpPubkeyHexList []string, btcHeight uint64) (map[string]uint64, error) {
+func (m *MockIBabylonClient) QueryMultiFpPower(ctx context.Context, consumerId string, fpPubkeyHexList []string, btcHeight uint64) (map[string]uint64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QueryMultiFpPower", fpPubkeyHexList, btcHeight)
+	ret := m.ctrl.Call(m, "QueryMultiFpPower", ctx, consumerId, fpPubkeyHexList, btcHeight)
 	ret0, _ := ret[0].(map[string]uint64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // QueryMultiFpPower indicates an expected call of QueryMultiFpPower.
-func (mr *MockIBabylonClientMockRecorder) QueryMultiFpPower(fpPubkeyHexList, btcHeight any) *gomock.Call {
+func (mr *MockIBabylonClientMockRecorder) QueryMultiFpPower(ctx, consumerId, fpPubkeyHexList, btcHeight any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryMultiFpPower", reflect.TypeOf((*MockIBabylonClient)(nil).QueryMultiFpPower), fpPubkeyHexList, btcHeight)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryMultiFpPower", reflect.TypeOf((*MockIBabylonClient)(nil).QueryMultiFpPower), ctx, consumerId, fpPubkeyHexList, btcHeight)
+}
+
+// SubscribeNewBlocks mocks base method.
+func (m *MockIBabylonClient) SubscribeNewBlocks(ctx context.Context) (<-chan uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeNewBlocks", ctx)
+	ret0, _ := ret[0].(<-chan uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeNewBlocks indicates an expected call of SubscribeNewBlocks.
+func (mr *MockIBabylonClientMockRecorder) SubscribeNewBlocks(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeNewBlocks", reflect.TypeOf((*MockIBabylonClient)(nil).SubscribeNewBlocks), ctx)
 }
 
 // MockIBitcoinClient is a mock of IBitcoinClient interface.
@@ -125,78 +141,78 @@ func (m *MockIBitcoinClient) EXPECT() *MockIBitcoinClientMockRecorder {
 }
 
 // GetBlockCount mocks base method.
-func (m *MockIBitcoinClient) GetBlockCount() (uint64, error) {
+func (m *MockIBitcoinClient) GetBlockCount(ctx context.Context) (uint64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBlockCount")
+	ret := m.ctrl.Call(m, "GetBlockCount", ctx)
 	ret0, _ := ret[0].(uint64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetBlockCount indicates an expected call of GetBlockCount.
-func (mr *MockIBitcoinClientMockRecorder) GetBlockCount() *gomock.Call {
+func (mr *MockIBitcoinClientMockRecorder) GetBlockCount(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockCount", reflect.TypeOf((*MockIBitcoinClient)(nil).GetBlockCount))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockCount", reflect.TypeOf((*MockIBitcoinClient)(nil).GetBlockCount), ctx)
 }
 
 // GetBlockHashByHeight mocks base method.
-func (m *MockIBitcoinClient) GetBlockHashByHeight(height uint64) (*chainhash.Hash, error) {
+func (m *MockIBitcoinClient) GetBlockHashByHeight(ctx context.Context, height uint64) (*chainhash.Hash, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBlockHashByHeight", height)
+	ret := m.ctrl.Call(m, "GetBlockHashByHeight", ctx, height)
 	ret0, _ := ret[0].(*chainhash.Hash)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetBlockHashByHeight indicates an expected call of GetBlockHashByHeight.
-func (mr *MockIBitcoinClientMockRecorder) GetBlockHashByHeight(height any) *gomock.Call {
+func (mr *MockIBitcoinClientMockRecorder) GetBlockHashByHeight(ctx, height any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockHashByHeight", reflect.TypeOf((*MockIBitcoinClient)(nil).GetBlockHashByHeight), height)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockHashByHeight", reflect.TypeOf((*MockIBitcoinClient)(nil).GetBlockHashByHeight), ctx, height)
 }
 
 // GetBlockHeaderByHash mocks base method.
-func (m *MockIBitcoinClient) GetBlockHeaderByHash(blockHash *chainhash.Hash) (*wire.BlockHeader, error) {
+func (m *MockIBitcoinClient) GetBlockHeaderByHash(ctx context.Context, blockHash *chainhash.Hash) (*wire.BlockHeader, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBlockHeaderByHash", blockHash)
+	ret := m.ctrl.Call(m, "GetBlockHeaderByHash", ctx, blockHash)
 	ret0, _ := ret[0].(*wire.BlockHeader)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetBlockHeaderByHash indicates an expected call of GetBlockHeaderByHash.
-func (mr *MockIBitcoinClientMockRecorder) GetBlockHeaderByHash(blockHash any) *gomock.Call {
+func (mr *MockIBitcoinClientMockRecorder) GetBlockHeaderByHash(ctx, blockHash any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockHeaderByHash", reflect.TypeOf((*MockIBitcoinClient)(nil).GetBlockHeaderByHash), blockHash)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockHeaderByHash", reflect.TypeOf((*MockIBitcoinClient)(nil).GetBlockHeaderByHash), ctx, blockHash)
 }
 
 // GetBlockHeightByTimestamp mocks base method.
-func (m *MockIBitcoinClient) GetBlockHeightByTimestamp(targetTimestamp uint64) (uint64, error) {
+func (m *MockIBitcoinClient) GetBlockHeightByTimestamp(ctx context.Context, targetTimestamp uint64) (uint64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBlockHeightByTimestamp", targetTimestamp)
+	ret := m.ctrl.Call(m, "GetBlockHeightByTimestamp", ctx, targetTimestamp)
 	ret0, _ := ret[0].(uint64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetBlockHeightByTimestamp indicates an expected call of GetBlockHeightByTimestamp.
-func (mr *MockIBitcoinClientMockRecorder) GetBlockHeightByTimestamp(targetTimestamp any) *gomock.Call {
+func (mr *MockIBitcoinClientMockRecorder) GetBlockHeightByTimestamp(ctx, targetTimestamp any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockHeightByTimestamp", reflect.TypeOf((*MockIBitcoinClient)(nil).GetBlockHeightByTimestamp), targetTimestamp)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockHeightByTimestamp", reflect.TypeOf((*MockIBitcoinClient)(nil).GetBlockHeightByTimestamp), ctx, targetTimestamp)
 }
 
 // GetBlockTimestampByHeight mocks base method.
-func (m *MockIBitcoinClient) GetBlockTimestampByHeight(height uint64) (uint64, error) {
+func (m *MockIBitcoinClient) GetBlockTimestampByHeight(ctx context.Context, height uint64) (uint64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBlockTimestampByHeight", height)
+	ret := m.ctrl.Call(m, "GetBlockTimestampByHeight", ctx, height)
 	ret0, _ := ret[0].(uint64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetBlockTimestampByHeight indicates an expected call of GetBlockTimestampByHeight.
-func (mr *MockIBitcoinClientMockRecorder) GetBlockTimestampByHeight(height any) *gomock.Call {
+func (mr *MockIBitcoinClientMockRecorder) GetBlockTimestampByHeight(ctx, height any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockTimestampByHeight", reflect.TypeOf((*MockIBitcoinClient)(nil).GetBlockTimestampByHeight), height)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockTimestampByHeight", reflect.TypeOf((*MockIBitcoinClient)(nil).GetBlockTimestampByHeight), ctx, height)
 }
 
 // MockICosmWasmClient is a mock of ICosmWasmClient interface.
@@ -223,46 +239,114 @@ func (m *MockICosmWasmClient) EXPECT() *MockICosmWasmClientMockRecorder {
 }
 
 // QueryConsumerId mocks base method.
-func (m *MockICosmWasmClient) QueryConsumerId() (string, error) {
+func (m *MockICosmWasmClient) QueryConsumerId(ctx context.Context) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QueryConsumerId")
+	ret := m.ctrl.Call(m, "QueryConsumerId", ctx)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // QueryConsumerId indicates an expected call of QueryConsumerId.
-func (mr *MockICosmWasmClientMockRecorder) QueryConsumerId() *gomock.Call {
+func (mr *MockICosmWasmClientMockRecorder) QueryConsumerId(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryConsumerId", reflect.TypeOf((*MockICosmWasmClient)(nil).QueryConsumerId), ctx)
+}
+
+// QueryEvidence mocks base method.
+func (m *MockICosmWasmClient) QueryEvidence(ctx context.Context, fpPubkeyHex string, height uint64) (*cwclient.Evidence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryEvidence", ctx, fpPubkeyHex, height)
+	ret0, _ := ret[0].(*cwclient.Evidence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryEvidence indicates an expected call of QueryEvidence.
+func (mr *MockICosmWasmClientMockRecorder) QueryEvidence(ctx, fpPubkeyHex, height any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryConsumerId", reflect.TypeOf((*MockICosmWasmClient)(nil).QueryConsumerId))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryEvidence", reflect.TypeOf((*MockICosmWasmClient)(nil).QueryEvidence), ctx, fpPubkeyHex, height)
 }
 
 // QueryIsEnabled mocks base method.
-func (m *MockICosmWasmClient) QueryIsEnabled() (bool, error) {
+func (m *MockICosmWasmClient) QueryIsEnabled(ctx context.Context) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QueryIsEnabled")
+	ret := m.ctrl.Call(m, "QueryIsEnabled", ctx)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // QueryIsEnabled indicates an expected call of QueryIsEnabled.
-func (mr *MockICosmWasmClientMockRecorder) QueryIsEnabled() *gomock.Call {
+func (mr *MockICosmWasmClientMockRecorder) QueryIsEnabled(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryIsEnabled", reflect.TypeOf((*MockICosmWasmClient)(nil).QueryIsEnabled))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryIsEnabled", reflect.TypeOf((*MockICosmWasmClient)(nil).QueryIsEnabled), ctx)
 }
 
 // QueryListOfVotedFinalityProviders mocks base method.
-func (m *MockICosmWasmClient) QueryListOfVotedFinalityProviders(queryParams *cwclient.L2Block) ([]string, error) {
+func (m *MockICosmWasmClient) QueryListOfVotedFinalityProviders(ctx context.Context, queryParams *cwclient.L2Block) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QueryListOfVotedFinalityProviders", queryParams)
+	ret := m.ctrl.Call(m, "QueryListOfVotedFinalityProviders", ctx, queryParams)
 	ret0, _ := ret[0].([]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // QueryListOfVotedFinalityProviders indicates an expected call of QueryListOfVotedFinalityProviders.
-func (mr *MockICosmWasmClientMockRecorder) QueryListOfVotedFinalityProviders(queryParams any) *gomock.Call {
+func (mr *MockICosmWasmClientMockRecorder) QueryListOfVotedFinalityProviders(ctx, queryParams any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryListOfVotedFinalityProviders", reflect.TypeOf((*MockICosmWasmClient)(nil).QueryListOfVotedFinalityProviders), ctx, queryParams)
+}
+
+// MockIL2Client is a mock of IL2Client interface.
+type MockIL2Client struct {
+	ctrl     *gomock.Controller
+	recorder *MockIL2ClientMockRecorder
+}
+
+// MockIL2ClientMockRecorder is the mock recorder for MockIL2Client.
+type MockIL2ClientMockRecorder struct {
+	mock *MockIL2Client
+}
+
+// NewMockIL2Client creates a new mock instance.
+func NewMockIL2Client(ctrl *gomock.Controller) *MockIL2Client {
+	mock := &MockIL2Client{ctrl: ctrl}
+	mock.recorder = &MockIL2ClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIL2Client) EXPECT() *MockIL2ClientMockRecorder {
+	return m.recorder
+}
+
+// BlockByHeight mocks base method.
+func (m *MockIL2Client) BlockByHeight(ctx context.Context, height uint64) (*cwclient.L2Block, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockByHeight", ctx, height)
+	ret0, _ := ret[0].(*cwclient.L2Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockByHeight indicates an expected call of BlockByHeight.
+func (mr *MockIL2ClientMockRecorder) BlockByHeight(ctx, height any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockByHeight", reflect.TypeOf((*MockIL2Client)(nil).BlockByHeight), ctx, height)
+}
+
+// HeadBlock mocks base method.
+func (m *MockIL2Client) HeadBlock(ctx context.Context) (*cwclient.L2Block, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HeadBlock", ctx)
+	ret0, _ := ret[0].(*cwclient.L2Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HeadBlock indicates an expected call of HeadBlock.
+func (mr *MockIL2ClientMockRecorder) HeadBlock(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryListOfVotedFinalityProviders", reflect.TypeOf((*MockICosmWasmClient)(nil).QueryListOfVotedFinalityProviders), queryParams)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HeadBlock", reflect.TypeOf((*MockIL2Client)(nil).HeadBlock), ctx)
 }
@@ -10,9 +10,11 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	cwclient "github.com/babylonchain/babylon-finality-gadget/sdk/cwclient"
+	finality "github.com/babylonchain/babylon-finality-gadget/sdk/finality"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -39,47 +41,62 @@ func (m *MockISdkClient) EXPECT() *MockISdkClientMockRecorder {
 	return m.recorder
 }
 
+// QueryBlockFinalityResult mocks base method.
+func (m *MockISdkClient) QueryBlockFinalityResult(ctx context.Context, queryParams cwclient.L2Block) (*finality.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryBlockFinalityResult", ctx, queryParams)
+	ret0, _ := ret[0].(*finality.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryBlockFinalityResult indicates an expected call of QueryBlockFinalityResult.
+func (mr *MockISdkClientMockRecorder) QueryBlockFinalityResult(ctx, queryParams any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryBlockFinalityResult", reflect.TypeOf((*MockISdkClient)(nil).QueryBlockFinalityResult), ctx, queryParams)
+}
+
 // QueryBlockRangeBabylonFinalized mocks base method.
-func (m *MockISdkClient) QueryBlockRangeBabylonFinalized(queryBlocks []*cwclient.L2Block) (*uint64, error) {
+func (m *MockISdkClient) QueryBlockRangeBabylonFinalized(ctx context.Context, queryBlocks []*cwclient.L2Block) (*uint64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QueryBlockRangeBabylonFinalized", queryBlocks)
+	ret := m.ctrl.Call(m, "QueryBlockRangeBabylonFinalized", ctx, queryBlocks)
 	ret0, _ := ret[0].(*uint64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // QueryBlockRangeBabylonFinalized indicates an expected call of QueryBlockRangeBabylonFinalized.
-func (mr *MockISdkClientMockRecorder) QueryBlockRangeBabylonFinalized(queryBlocks any) *gomock.Call {
+func (mr *MockISdkClientMockRecorder) QueryBlockRangeBabylonFinalized(ctx, queryBlocks any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryBlockRangeBabylonFinalized", reflect.TypeOf((*MockISdkClient)(nil).QueryBlockRangeBabylonFinalized), queryBlocks)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryBlockRangeBabylonFinalized", reflect.TypeOf((*MockISdkClient)(nil).QueryBlockRangeBabylonFinalized), ctx, queryBlocks)
 }
 
 // QueryBtcStakingActivatedTimestamp mocks base method.
-func (m *MockISdkClient) QueryBtcStakingActivatedTimestamp() (uint64, error) {
+func (m *MockISdkClient) QueryBtcStakingActivatedTimestamp(ctx context.Context) (uint64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QueryBtcStakingActivatedTimestamp")
+	ret := m.ctrl.Call(m, "QueryBtcStakingActivatedTimestamp", ctx)
 	ret0, _ := ret[0].(uint64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // QueryBtcStakingActivatedTimestamp indicates an expected call of QueryBtcStakingActivatedTimestamp.
-func (mr *MockISdkClientMockRecorder) QueryBtcStakingActivatedTimestamp() *gomock.Call {
+func (mr *MockISdkClientMockRecorder) QueryBtcStakingActivatedTimestamp(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryBtcStakingActivatedTimestamp", reflect.TypeOf((*MockISdkClient)(nil).QueryBtcStakingActivatedTimestamp))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryBtcStakingActivatedTimestamp", reflect.TypeOf((*MockISdkClient)(nil).QueryBtcStakingActivatedTimestamp), ctx)
 }
 
 // QueryIsBlockBabylonFinalized mocks base method.
-func (m *MockISdkClient) QueryIsBlockBabylonFinalized(queryParams cwclient.L2Block) (bool, error) {
+func (m *MockISdkClient) QueryIsBlockBabylonFinalized(ctx context.Context, queryParams cwclient.L2Block) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QueryIsBlockBabylonFinalized", queryParams)
+	ret := m.ctrl.Call(m, "QueryIsBlockBabylonFinalized", ctx, queryParams)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // QueryIsBlockBabylonFinalized indicates an expected call of QueryIsBlockBabylonFinalized.
-func (mr *MockISdkClientMockRecorder) QueryIsBlockBabylonFinalized(queryParams any) *gomock.Call {
+func (mr *MockISdkClientMockRecorder) QueryIsBlockBabylonFinalized(ctx, queryParams any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryIsBlockBabylonFinalized", reflect.TypeOf((*MockISdkClient)(nil).QueryIsBlockBabylonFinalized), queryParams)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryIsBlockBabylonFinalized", reflect.TypeOf((*MockISdkClient)(nil).QueryIsBlockBabylonFinalized), ctx, queryParams)
 }